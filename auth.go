@@ -0,0 +1,148 @@
+package goPinotAPI
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AuthScheme is the scheme used in the Authorization header sent to the controller
+type AuthScheme string
+
+const (
+	AuthTypeBasic  AuthScheme = "Basic"
+	AuthTypeBearer AuthScheme = "Bearer"
+)
+
+// Environment variables read by CredentialsFromEnv
+const (
+	EnvAuthToken = "PINOT_AUTH_TOKEN"
+	EnvAuthType  = "PINOT_AUTH_TYPE"
+	EnvUsername  = "PINOT_USERNAME"
+	EnvPassword  = "PINOT_PASSWORD"
+)
+
+const basicAuth = "basicAuth"
+const credentials = "credentials"
+
+// Credentials is the content of a credentials file, either a username and password for basic auth
+// or a token with an optional auth type
+type Credentials struct {
+	Username string     `json:"username,omitempty"`
+	Password string     `json:"password,omitempty"`
+	Token    string     `json:"token,omitempty"`
+	Type     AuthScheme `json:"type,omitempty"`
+}
+
+func (cr Credentials) apply(c *cfg) error {
+
+	if cr.Token != "" && (cr.Username != "" || cr.Password != "") {
+		return fmt.Errorf("credentials must have either a token or a username and password, not both")
+	}
+
+	if cr.Token != "" {
+		c.authToken = cr.Token
+		c.authType = string(cr.Type)
+		return nil
+	}
+
+	if cr.Username == "" || cr.Password == "" {
+		return fmt.Errorf("credentials must have a token or a username and password")
+	}
+
+	c.authToken = encodeBasicAuth(cr.Username, cr.Password)
+	c.authType = string(AuthTypeBasic)
+	return nil
+}
+
+// basicAuthOpt is an option to set basic auth credentials for the client
+type basicAuthOpt struct {
+	username string
+	password string
+}
+
+func (o *basicAuthOpt) apply(c *cfg) {
+	c.authToken = encodeBasicAuth(o.username, o.password)
+	c.authType = string(AuthTypeBasic)
+}
+
+func (o *basicAuthOpt) Type() string {
+	return basicAuth
+}
+
+// credentialsOpt is an option to load the client credentials from a source at construction
+type credentialsOpt struct {
+	load func() (Credentials, error)
+}
+
+func (o *credentialsOpt) apply(c *cfg) {
+	creds, err := o.load()
+	if err == nil {
+		err = creds.apply(c)
+	}
+	if err != nil {
+		c.errs = append(c.errs, fmt.Errorf("unable to load credentials: %w", err))
+	}
+}
+
+func (o *credentialsOpt) Type() string {
+	return credentials
+}
+
+// BasicAuth authenticates with a username and password, encoding them itself
+func BasicAuth(username string, password string) Opt {
+	return &basicAuthOpt{username: username, password: password}
+}
+
+// CredentialsFromEnv reads credentials from PINOT_AUTH_TOKEN and PINOT_AUTH_TYPE, or from
+// PINOT_USERNAME and PINOT_PASSWORD when no token is set
+func CredentialsFromEnv() Opt {
+	return &credentialsOpt{load: func() (Credentials, error) {
+		creds := Credentials{
+			Token:    os.Getenv(EnvAuthToken),
+			Type:     AuthScheme(os.Getenv(EnvAuthType)),
+			Username: os.Getenv(EnvUsername),
+			Password: os.Getenv(EnvPassword),
+		}
+		if creds.Token != "" {
+			creds.Username, creds.Password = "", ""
+		}
+		return creds, nil
+	}}
+}
+
+// CredentialsFromFile reads credentials from a json file holding either a username and password or
+// a token and type, see Credentials
+func CredentialsFromFile(path string) Opt {
+	return &credentialsOpt{load: func() (Credentials, error) {
+		var creds Credentials
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return creds, err
+		}
+
+		if err := json.Unmarshal(data, &creds); err != nil {
+			return creds, fmt.Errorf("invalid credentials file %s: %w", path, err)
+		}
+
+		return creds, nil
+	}}
+}
+
+func encodeBasicAuth(username string, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+func parseAuthScheme(authType string) (AuthScheme, error) {
+	switch strings.ToLower(authType) {
+	case "", "basic":
+		return AuthTypeBasic, nil
+	case "bearer":
+		return AuthTypeBearer, nil
+	default:
+		return "", fmt.Errorf("auth type %q is not supported, use %s or %s", authType, AuthTypeBasic, AuthTypeBearer)
+	}
+}
@@ -0,0 +1,189 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+// createAuthCheckServer serves the cluster info route, recording the Authorization header it received
+func createAuthCheckServer(authHeader *string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteClusterInfo, func(w http.ResponseWriter, r *http.Request) {
+		*authHeader = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestBasicAuth(t *testing.T) {
+	var authHeader string
+	server := createAuthCheckServer(&authHeader)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.BasicAuth("admin", "verysecret"),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic YWRtaW46dmVyeXNlY3JldA==", authHeader)
+}
+
+func TestTypedAuthType(t *testing.T) {
+	var authHeader string
+	server := createAuthCheckServer(&authHeader)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.AuthType(goPinotAPI.AuthTypeBearer),
+		goPinotAPI.AuthToken("your_token"),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer your_token", authHeader)
+}
+
+func TestUnsupportedAuthType(t *testing.T) {
+	assert.Panics(t, func() {
+		goPinotAPI.NewPinotAPIClient(
+			goPinotAPI.ControllerUrl("http://localhost:9000"),
+			goPinotAPI.AuthType("Digest"),
+			goPinotAPI.AuthToken("your_token"),
+		)
+	}, "Expected unsupported auth type to be rejected")
+}
+
+func TestBasicAuthWithAuthToken(t *testing.T) {
+	assert.Panics(t, func() {
+		goPinotAPI.NewPinotAPIClient(
+			goPinotAPI.ControllerUrl("http://localhost:9000"),
+			goPinotAPI.BasicAuth("admin", "verysecret"),
+			goPinotAPI.AuthToken("your_token"),
+		)
+	}, "Expected conflicting credentials to be rejected")
+}
+
+func TestCredentialsFromEnv(t *testing.T) {
+	var authHeader string
+	server := createAuthCheckServer(&authHeader)
+	defer server.Close()
+
+	t.Setenv(goPinotAPI.EnvAuthToken, "")
+	t.Setenv(goPinotAPI.EnvUsername, "admin")
+	t.Setenv(goPinotAPI.EnvPassword, "verysecret")
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.CredentialsFromEnv(),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic YWRtaW46dmVyeXNlY3JldA==", authHeader)
+
+	t.Setenv(goPinotAPI.EnvAuthToken, "your_token")
+	t.Setenv(goPinotAPI.EnvAuthType, "bearer")
+
+	client = goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.CredentialsFromEnv(),
+	)
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer your_token", authHeader, "Expected token to take precedence over username and password")
+}
+
+func TestCredentialsFromFile(t *testing.T) {
+	var authHeader string
+	server := createAuthCheckServer(&authHeader)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	err := os.WriteFile(path, []byte(`{"token": "your_token", "type": "Bearer"}`), 0600)
+	assert.NoError(t, err)
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.CredentialsFromFile(path),
+	)
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer your_token", authHeader)
+
+	assert.Panics(t, func() {
+		goPinotAPI.NewPinotAPIClient(
+			goPinotAPI.ControllerUrl(server.URL),
+			goPinotAPI.CredentialsFromFile(filepath.Join(t.TempDir(), "missing.json")),
+		)
+	}, "Expected missing credentials file to be rejected")
+}
+
+// TestAuthSentOnEveryEndpoint calls every exported client method against a controller that records
+// each request, asserting that none of them reach the controller without the Authorization header
+func TestAuthSentOnEveryEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	var unauthenticated []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		if r.Header.Get("Authorization") != "Basic YWRtaW46YWRtaW4K" {
+			unauthenticated = append(unauthenticated, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+		}
+		mu.Unlock()
+		http.Error(w, `{"code": 500, "error": "recorded"}`, http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := createPinotClient(server.URL)
+	clientValue := reflect.ValueOf(client)
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	for i := 0; i < clientValue.NumMethod(); i++ {
+		fn := clientValue.Method(i)
+
+		args := make([]reflect.Value, fn.Type().NumIn())
+		for j := range args {
+			argType := fn.Type().In(j)
+			if argType == contextType {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+				args[j] = reflect.ValueOf(ctx)
+				continue
+			}
+			args[j] = reflect.Zero(argType)
+		}
+
+		func() {
+			// zero arguments are not valid for every method, only the requests that are sent matter
+			defer func() { _ = recover() }()
+			if fn.Type().IsVariadic() {
+				fn.CallSlice(args)
+			} else {
+				fn.Call(args)
+			}
+		}()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Greater(t, requests, 100, "Expected most client methods to send a request")
+	assert.Empty(t, unauthenticated, "Expected every request to carry the Authorization header")
+}
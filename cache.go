@@ -0,0 +1,371 @@
+package goPinotAPI
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTLs caches the table and schema metadata dashboards read most
+var DefaultCacheTTLs = map[string]time.Duration{
+	"/tables":             30 * time.Second,
+	"/tables/*":           30 * time.Second,
+	"/tables/*/instances": 30 * time.Second,
+	"/tables/*/schema":    30 * time.Second,
+	"/schemas":            30 * time.Second,
+	"/schemas/*":          30 * time.Second,
+}
+
+// CacheOptions controls which responses the client caches and for how long
+type CacheOptions struct {
+	// TTLs maps endpoint patterns to how long their responses are cached, where * in a pattern
+	// matches one path segment, e.g. /tables/*/instances. Only GET requests to endpoints matching
+	// a pattern are cached, DefaultCacheTTLs when nil
+	TTLs map[string]time.Duration
+	// MaxEntries is the number of responses kept, the least recently used is evicted first,
+	// defaults to 1000
+	MaxEntries int
+}
+
+// CacheStats counts how the response cache served requests
+type CacheStats struct {
+	Hits          int64 // served from the cache
+	Misses        int64 // sent to the controller
+	Revalidations int64 // expired responses the controller confirmed unchanged with 304, also counted as hits
+	Evictions     int64
+	Invalidations int64 // responses dropped because of a mutating call on their resource
+	Entries       int
+}
+
+const responseCaching = "responseCache"
+
+// responseCacheOpt is an option to cache responses of read heavy endpoints
+type responseCacheOpt struct {
+	options CacheOptions
+}
+
+func (o *responseCacheOpt) apply(c *cfg) {
+	c.cache = &o.options
+}
+
+func (o *responseCacheOpt) Type() string {
+	return responseCaching
+}
+
+// ResponseCache caches the responses of read heavy metadata endpoints, such as those behind
+// GetTables, GetSchema and GetTableInstances. Mutating calls through the client drop the cached
+// responses of the resource they change. Responses are not cached by default
+func ResponseCache(options CacheOptions) Opt {
+	return &responseCacheOpt{options: options}
+}
+
+type cacheEntry struct {
+	key     string
+	path    []string
+	header  http.Header
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// responseCache is an LRU cache of GET responses keyed by url
+type responseCache struct {
+	ttls       map[string]time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	stats   CacheStats
+}
+
+func newResponseCache(options CacheOptions) *responseCache {
+
+	ttls := options.TTLs
+	if ttls == nil {
+		ttls = DefaultCacheTTLs
+	}
+
+	maxEntries := options.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+
+	return &responseCache{
+		ttls:       ttls,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// ttl returns how long responses for the path are cached, 0 when they are not. When several
+// patterns match, the one with the fewest wildcards wins
+func (rc *responseCache) ttl(path []string) time.Duration {
+
+	var ttl time.Duration
+	wildcards := -1
+
+	for _, pattern := range sortedKeys(rc.ttls) {
+		if !matchesPattern(path, pattern) {
+			continue
+		}
+		if n := strings.Count(pattern, "*"); wildcards == -1 || n < wildcards {
+			ttl, wildcards = rc.ttls[pattern], n
+		}
+	}
+
+	return ttl
+}
+
+func matchesPattern(path []string, pattern string) bool {
+
+	patternSegments := pathSegments(pattern)
+	if len(patternSegments) != len(path) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if segment != "*" && segment != path[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// get returns the cached entry for key and whether it is still fresh
+func (rc *responseCache) get(key string) (*cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	element, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	rc.lru.MoveToFront(element)
+	entry := element.Value.(*cacheEntry)
+	return entry, time.Now().Before(entry.expires)
+}
+
+func (rc *responseCache) put(entry *cacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if element, ok := rc.entries[entry.key]; ok {
+		element.Value = entry
+		rc.lru.MoveToFront(element)
+		return
+	}
+
+	rc.entries[entry.key] = rc.lru.PushFront(entry)
+
+	for rc.lru.Len() > rc.maxEntries {
+		oldest := rc.lru.Back()
+		rc.lru.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+		rc.stats.Evictions++
+	}
+}
+
+// invalidate drops the cached responses of the resource a mutating call on path changes, and the
+// listings that include it
+func (rc *responseCache) invalidate(path []string) {
+	if len(path) == 0 {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for key, element := range rc.entries {
+		if affectedBy(element.Value.(*cacheEntry).path, path) {
+			rc.lru.Remove(element)
+			delete(rc.entries, key)
+			rc.stats.Invalidations++
+		}
+	}
+}
+
+// tableResources are the collections whose second path segment is a table
+var tableResources = map[string]bool{"tables": true, "segments": true}
+
+// affectedBy reports whether a cached path is changed by a mutating call on path
+func affectedBy(cached []string, path []string) bool {
+
+	if len(cached) == 0 {
+		return false
+	}
+
+	// a table's schema is read from its schema, which need not share the table's name
+	if path[0] == "schemas" && len(cached) == 3 && cached[0] == "tables" && cached[2] == "schema" {
+		return true
+	}
+
+	sameCollection := cached[0] == path[0] || (tableResources[cached[0]] && tableResources[path[0]])
+	if !sameCollection {
+		return false
+	}
+
+	// a call on the collection itself, or a listing of the collection
+	if len(path) == 1 || len(cached) == 1 {
+		return true
+	}
+
+	cachedName, _ := splitTableNameWithType(cached[1])
+	name, _ := splitTableNameWithType(path[1])
+	return cachedName == name
+}
+
+func (rc *responseCache) hit(revalidated bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.stats.Hits++
+	if revalidated {
+		rc.stats.Revalidations++
+	}
+}
+
+func (rc *responseCache) miss() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.stats.Misses++
+}
+
+func (rc *responseCache) snapshot() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	stats := rc.stats
+	stats.Entries = rc.lru.Len()
+	return stats
+}
+
+func (rc *responseCache) clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries = make(map[string]*list.Element)
+	rc.lru.Init()
+}
+
+// CacheStats returns the statistics of the response cache, zero when it is not enabled
+func (c *PinotAPIClient) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.snapshot()
+}
+
+// ClearCache drops every cached response
+func (c *PinotAPIClient) ClearCache() {
+	if c.cache != nil {
+		c.cache.clear()
+	}
+}
+
+// sendCached serves GET requests to cached endpoints from the cache, revalidating expired responses
+// with their ETag, and invalidates cached responses on mutating calls
+func (c *PinotAPIClient) sendCached(req *http.Request) (*http.Response, error) {
+
+	if c.cache == nil {
+		return c.sendToControllers(req)
+	}
+
+	path := pathSegments(strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(c.pinotControllerUrl.Path, "/")))
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		res, err := c.sendToControllers(req)
+		// the call may have changed the resource even when it failed
+		c.cache.invalidate(path)
+		return res, err
+	}
+
+	ttl := c.cache.ttl(path)
+	if req.Method == http.MethodHead || ttl <= 0 {
+		return c.sendToControllers(req)
+	}
+
+	key := req.URL.String()
+	entry, fresh := c.cache.get(key)
+	if fresh {
+		c.cache.hit(false)
+		return entry.response(req), nil
+	}
+
+	if entry != nil && entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	res, err := c.sendToControllers(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotModified && entry != nil {
+		res.Body.Close()
+		c.cache.put(entry.renewed(ttl))
+		c.cache.hit(true)
+		return entry.response(req), nil
+	}
+
+	c.cache.miss()
+
+	if res.StatusCode != http.StatusOK {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("client: could not read response body: %w", err)
+	}
+
+	fetched := &cacheEntry{
+		key:     key,
+		path:    path,
+		header:  res.Header.Clone(),
+		body:    body,
+		etag:    res.Header.Get("ETag"),
+		expires: time.Now().Add(ttl),
+	}
+	c.cache.put(fetched)
+
+	return fetched.response(req), nil
+}
+
+func (e *cacheEntry) renewed(ttl time.Duration) *cacheEntry {
+	renewed := *e
+	renewed.expires = time.Now().Add(ttl)
+	return &renewed
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,163 @@
+package goPinotAPI_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// countRequests returns middleware counting the requests that reach the controller
+func countRequests(requests *int32) goPinotAPI.Middleware {
+	return func(next goPinotAPI.Handler) goPinotAPI.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(requests, 1)
+			return next(req)
+		}
+	}
+}
+
+func createCachingPinotClient(controllerUrl string, requests *int32, options goPinotAPI.CacheOptions) *goPinotAPI.PinotAPIClient {
+	return goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controllerUrl),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.ResponseCache(options),
+		goPinotAPI.Middlewares(countRequests(requests)),
+	)
+}
+
+func TestResponseCache(t *testing.T) {
+	controller := createMockController(t)
+	var requests int32
+	client := createCachingPinotClient(controller.URL, &requests, goPinotAPI.CacheOptions{})
+
+	for i := 0; i < 3; i++ {
+		res, err := client.GetTables()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test"}, res.Tables)
+
+		_, err = client.GetTableInstances("test")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests), "Expected repeated reads to be served from the cache")
+	stats := client.CacheStats()
+	assert.Equal(t, int64(4), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+	assert.Equal(t, 2, stats.Entries)
+
+	// endpoints without a ttl are not cached
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+}
+
+func TestResponseCacheInvalidation(t *testing.T) {
+	controller := createMockController(t)
+	var requests int32
+	client := createCachingPinotClient(controller.URL, &requests, goPinotAPI.CacheOptions{})
+
+	_, err := client.GetTables()
+	assert.NoError(t, err)
+	_, err = client.GetTableInstances("test")
+	assert.NoError(t, err)
+	_, err = client.GetSchema("test")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteTable("test")
+	assert.NoError(t, err)
+
+	stats := client.CacheStats()
+	assert.Equal(t, int64(2), stats.Invalidations, "Expected the table listing and the table instances to be invalidated")
+	assert.Equal(t, 1, stats.Entries, "Expected the schema to stay cached")
+
+	requests = 0
+	tables, err := client.GetTables()
+	assert.NoError(t, err)
+	assert.Empty(t, tables.Tables, "Expected the deleted table not to be listed")
+	_, err = client.GetSchema("test")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Expected only the invalidated response to be fetched again")
+
+	_, err = client.CreateTable([]byte(testTableConfig))
+	assert.NoError(t, err)
+
+	client.ClearCache()
+	assert.Equal(t, 0, client.CacheStats().Entries)
+
+	_, err = client.GetTableSchema("test")
+	assert.NoError(t, err)
+	_, err = client.GetTableInstances("test")
+	assert.NoError(t, err)
+
+	_, err = client.UpdateSchema(model.Schema{SchemaName: "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.CacheStats().Entries, "Expected the table schema to be invalidated by the schema update")
+
+	requests = 0
+	_, err = client.GetTableSchema("test")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Expected the table schema to be fetched again")
+}
+
+func TestResponseCacheETag(t *testing.T) {
+	var notModified int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteSchemas, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `["test"]`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var requests int32
+	client := createCachingPinotClient(server.URL, &requests, goPinotAPI.CacheOptions{
+		TTLs: map[string]time.Duration{"/schemas": time.Millisecond},
+	})
+
+	_, err := client.GetSchemas()
+	assert.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	res, err := client.GetSchemas()
+	assert.NoError(t, err)
+	assert.Equal(t, model.GetSchemaResponse{"test"}, *res)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notModified), "Expected the expired response to be revalidated")
+
+	stats := client.CacheStats()
+	assert.Equal(t, int64(1), stats.Revalidations)
+	assert.Equal(t, int64(1), stats.Hits)
+}
+
+func TestResponseCacheEviction(t *testing.T) {
+	controller := createMockController(t)
+	var requests int32
+	client := createCachingPinotClient(controller.URL, &requests, goPinotAPI.CacheOptions{MaxEntries: 1})
+
+	_, err := client.GetTables()
+	assert.NoError(t, err)
+	_, err = client.GetSchema("test")
+	assert.NoError(t, err)
+	_, err = client.GetTables()
+	assert.NoError(t, err)
+
+	stats := client.CacheStats()
+	assert.Equal(t, int64(2), stats.Evictions)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,153 @@
+package goPinotAPI
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// GetTenantCapacityReports returns a capacity report for every server tenant in the cluster
+func (c *PinotAPIClient) GetTenantCapacityReports() ([]model.TenantCapacityReport, error) {
+
+	tenants, err := c.GetTenants()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tenants: %w", err)
+	}
+
+	reports := make([]model.TenantCapacityReport, 0, len(tenants.ServerTenants))
+	for _, tenantName := range tenants.ServerTenants {
+		report, err := c.GetTenantCapacityReport(tenantName)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, nil
+}
+
+// GetTenantCapacityReport reports the data size and segments held by each server of a server tenant, the
+// resources of each server, and the skew between servers
+func (c *PinotAPIClient) GetTenantCapacityReport(tenantName string) (*model.TenantCapacityReport, error) {
+
+	metadata, err := c.GetTenantMetadata(tenantName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get metadata of tenant %s: %w", tenantName, err)
+	}
+
+	tables, err := c.GetTenantTables(tenantName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tables of tenant %s: %w", tenantName, err)
+	}
+
+	servers := make(map[string]*model.ServerCapacity, len(metadata.ServerInstances))
+	for _, serverName := range metadata.ServerInstances {
+		instance, err := c.GetInstance(serverName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get instance %s: %w", serverName, err)
+		}
+
+		servers[serverName] = &model.ServerCapacity{
+			InstanceName:  serverName,
+			Enabled:       instance.Enabled,
+			Tables:        []string{},
+			NumCores:      int(parseResource(instance.SystemResourceInfo.NumCores)),
+			TotalMemoryMB: parseResource(instance.SystemResourceInfo.TotalMemoryMB),
+			MaxHeapSizeMB: parseResource(instance.SystemResourceInfo.MaxHeapSizeMB),
+		}
+	}
+
+	report := &model.TenantCapacityReport{
+		TenantName: tenantName,
+		NumServers: len(servers),
+		Tables:     []string{},
+		Servers:    []model.ServerCapacity{},
+	}
+
+	// tenant tables include their type, but table size covers both types of a table
+	tableNames := make(map[string]bool)
+	for _, tableNameWithType := range tables.Tables {
+		tableName, _ := splitTableNameWithType(tableNameWithType)
+		tableNames[tableName] = true
+	}
+
+	for _, tableName := range sortedKeys(tableNames) {
+
+		size, err := c.GetTableSize(tableName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get size of table %s: %w", tableName, err)
+		}
+		report.Tables = append(report.Tables, tableName)
+
+		hostsTable := make(map[string]bool)
+		for _, segments := range []model.TableSegments{size.OfflineSegments, size.RealtimeSegments} {
+			for _, segment := range segments.Segments {
+				for serverName, info := range segment.ServerInfo {
+					server, ok := servers[serverName]
+					if !ok {
+						// the table may also be hosted by servers of another tenant through tag overrides
+						continue
+					}
+					server.DataSizeInBytes += info.DiskSizeInBytes
+					server.Segments++
+					hostsTable[serverName] = true
+				}
+			}
+		}
+
+		for serverName := range hostsTable {
+			servers[serverName].Tables = append(servers[serverName].Tables, tableName)
+		}
+	}
+
+	var maxDataSize int64
+	var maxSegments int
+	for _, serverName := range sortedKeys(servers) {
+		server := servers[serverName]
+		report.Servers = append(report.Servers, *server)
+
+		report.TotalDataSizeInBytes += server.DataSizeInBytes
+		report.TotalSegments += server.Segments
+		report.TotalMemoryMB += server.TotalMemoryMB
+		maxDataSize = max(maxDataSize, server.DataSizeInBytes)
+		maxSegments = max(maxSegments, server.Segments)
+	}
+
+	if report.NumServers > 0 {
+		report.AverageDataSizePerServer = report.TotalDataSizeInBytes / int64(report.NumServers)
+		report.DataSizeSkew = skew(float64(maxDataSize), float64(report.TotalDataSizeInBytes), report.NumServers)
+		report.SegmentSkew = skew(float64(maxSegments), float64(report.TotalSegments), report.NumServers)
+	}
+	if report.TotalMemoryMB > 0 {
+		report.DataSizeInBytesPerMemoryMB = float64(report.TotalDataSizeInBytes) / float64(report.TotalMemoryMB)
+	}
+
+	return report, nil
+}
+
+// skew is the largest value divided by the average, or 0 if there is nothing to compare
+func skew(largest float64, total float64, count int) float64 {
+	if total == 0 || count == 0 {
+		return 0
+	}
+	return largest / (total / float64(count))
+}
+
+// parseResource parses the system resource info of an instance, which is reported as strings
+func parseResource(value string) int64 {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
+// WriteTenantCapacityReportsJSON writes the reports as indented json
+func WriteTenantCapacityReportsJSON(w io.Writer, reports []model.TenantCapacityReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reports)
+}
@@ -0,0 +1,58 @@
+package goPinotAPI_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTenantCapacityReport(t *testing.T) {
+	controller := createAirlineStatsController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTenantCapacityReport("airlineTenant")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, 2, res.NumServers, "Expected 2 servers")
+	assert.Equal(t, []string{"airlineStats"}, res.Tables, "Expected airlineStats to be hosted")
+	assert.Equal(t, int64(4000), res.TotalDataSizeInBytes, "Expected 4000 bytes across all servers")
+	assert.Equal(t, int64(2000), res.AverageDataSizePerServer, "Expected 2000 bytes per server")
+	assert.Equal(t, 3, res.TotalSegments, "Expected 3 segment replicas")
+	assert.Equal(t, 1.5, res.DataSizeSkew, "Expected the first server to hold 1.5 times the average")
+	assert.Equal(t, int64(31944), res.TotalMemoryMB, "Expected memory of both servers")
+
+	assert.Equal(t, int64(3000), res.Servers[0].DataSizeInBytes, "Expected first server to hold 3000 bytes")
+	assert.Equal(t, 2, res.Servers[0].Segments, "Expected first server to hold 2 segments")
+	assert.Equal(t, 8, res.Servers[0].NumCores, "Expected 8 cores")
+	assert.False(t, res.Servers[1].Enabled, "Expected second server to be disabled")
+}
+
+func TestWriteTenantCapacityReportsJSON(t *testing.T) {
+	controller := createAirlineStatsController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTenantCapacityReport("airlineTenant")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = goPinotAPI.WriteTenantCapacityReportsJSON(&buf, []model.TenantCapacityReport{*res})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	var reports []model.TenantCapacityReport
+	err = json.Unmarshal(buf.Bytes(), &reports)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, *res, reports[0], "Expected report to round trip through json")
+}
@@ -1,18 +1,20 @@
 package goPinotAPI
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
 )
 
 const controllerUrl = "controllerUrl"
 const authToken = "authToken"
 const authType = "authType"
 const logger = "logger"
+const instrumentation = "instrumentation"
+const transport = "transport"
 
 type Opt interface {
 	apply(*cfg)
@@ -20,11 +22,20 @@ type Opt interface {
 }
 
 type cfg struct {
-	controllerUrl  string
-	authToken      string
-	authType       string
-	httpAuthWriter httpAuthWriter
-	logger         *slog.Logger
+	controllerUrl   string
+	controllerUrls  []string
+	controllers     []*url.URL
+	failover        FailoverOptions
+	rateLimit       RateLimitOptions
+	cache           *CacheOptions
+	transport       http.RoundTripper
+	authToken       string
+	authType        string
+	middlewares     []Middleware
+	instrumentation Instrumentation
+	credentials     CredentialProvider
+	logger          *slog.Logger
+	errs            []error
 }
 
 type clientOpt struct{ fn func(*cfg) }
@@ -42,6 +53,22 @@ func (o *controllerUrlOpt) Type() string {
 	return controllerUrl
 }
 
+// controllerUrlsOpt is an option to set several controller urls for the client
+type controllerUrlsOpt struct {
+	controllerUrls []string
+}
+
+func (o *controllerUrlsOpt) apply(c *cfg) {
+	c.controllerUrls = o.controllerUrls
+	if len(o.controllerUrls) > 0 {
+		c.controllerUrl = o.controllerUrls[0]
+	}
+}
+
+func (o *controllerUrlsOpt) Type() string {
+	return controllerUrl
+}
+
 // authTokenOpt is an option to set the auth token for the client
 type authTokenOpt struct {
 	authToken string
@@ -81,12 +108,45 @@ func (o *loggerOpt) Type() string {
 	return logger
 }
 
+// instrumentationOpt is an option to observe the calls the client makes
+type instrumentationOpt struct {
+	instrumentation Instrumentation
+}
+
+func (o *instrumentationOpt) apply(c *cfg) {
+	c.instrumentation = o.instrumentation
+}
+
+func (o *instrumentationOpt) Type() string {
+	return instrumentation
+}
+
+// transportOpt is an option to set the transport the client sends requests with
+type transportOpt struct {
+	transport http.RoundTripper
+}
+
+func (o *transportOpt) apply(c *cfg) {
+	c.transport = o.transport
+}
+
+func (o *transportOpt) Type() string {
+	return transport
+}
+
 func (opt clientOpt) apply(cfg *cfg) { opt.fn(cfg) }
 
 func ControllerUrl(pinotControllerUrl string) Opt {
 	return &controllerUrlOpt{controllerUrl: pinotControllerUrl}
 }
 
+// ControllerUrls sets the controllers of a cluster without a load balancer in front of them. Requests
+// go to the first controller, mutating calls on a table go to the table's lead controller, and
+// controllers that can't be reached or fail health checks are skipped in favour of the next one, see Failover
+func ControllerUrls(pinotControllerUrls ...string) Opt {
+	return &controllerUrlsOpt{controllerUrls: pinotControllerUrls}
+}
+
 func AuthToken(token string) Opt {
 	return &authTokenOpt{authToken: token}
 }
@@ -95,13 +155,26 @@ func Logger(logger *slog.Logger) Opt {
 	return &loggerOpt{logger: logger}
 }
 
-func AuthType(authType string) Opt {
-	return &authTypeOpt{authType: authType}
+// Instrument reports every call the client makes to the given instrumentation, such as the
+// OpenTelemetry tracing and metrics of the otelpinot module. Calls are not instrumented by default
+func Instrument(instrumentation Instrumentation) Opt {
+	return &instrumentationOpt{instrumentation: instrumentation}
+}
+
+// Transport sets the transport requests to the controller are sent with, such as the record and
+// replay transport of the pinotreplay package, http.DefaultTransport by default
+func Transport(transport http.RoundTripper) Opt {
+	return &transportOpt{transport: transport}
+}
+
+// AuthType sets the scheme of the auth token, AuthTypeBasic when not set
+func AuthType(authType AuthScheme) Opt {
+	return &authTypeOpt{authType: string(authType)}
 }
 
 func validateOpts(opts ...Opt) (*cfg, *url.URL, error) {
 
-	// with default auth writer that does nothing
+	// with no credentials, requests are unauthenticated by default
 	optCfg := defaultCfg()
 	optCounts := make(map[string]int)
 	for _, opt := range opts {
@@ -111,10 +184,13 @@ func validateOpts(opts ...Opt) (*cfg, *url.URL, error) {
 			optCounts[authType]++
 		case *authTokenOpt:
 			optCounts[authToken]++
-		case *controllerUrlOpt:
+		case *controllerUrlOpt, *controllerUrlsOpt:
 			optCounts[controllerUrl]++
 		case *loggerOpt:
 			optCounts[logger]++
+		case *basicAuthOpt, *credentialsOpt, *authProviderOpt:
+			optCounts[authType]++
+			optCounts[authToken]++
 		default:
 			optCounts[opt.Type()]++
 		}
@@ -124,6 +200,14 @@ func validateOpts(opts ...Opt) (*cfg, *url.URL, error) {
 		if optCounts[authType] > 1 {
 			return nil, nil, fmt.Errorf("multiple auth types provided")
 		}
+
+		if optCounts[authToken] > 1 {
+			return nil, nil, fmt.Errorf("multiple auth tokens provided")
+		}
+	}
+
+	if len(optCfg.errs) > 0 {
+		return nil, nil, errors.Join(optCfg.errs...)
 	}
 
 	// validate controller url
@@ -131,6 +215,13 @@ func validateOpts(opts ...Opt) (*cfg, *url.URL, error) {
 	if err != nil {
 		return nil, nil, fmt.Errorf("controller url is invalid: %w", err)
 	}
+	for _, controllerUrl := range optCfg.controllerUrls {
+		controller, err := url.Parse(controllerUrl)
+		if err != nil {
+			return nil, nil, fmt.Errorf("controller url %s is invalid: %w", controllerUrl, err)
+		}
+		optCfg.controllers = append(optCfg.controllers, controller)
+	}
 	// TODO: remove the redundant check
 	// Currently this is designed to avoid a breaking change
 	if optCfg.authType != "" && optCfg.authToken == "" {
@@ -138,17 +229,11 @@ func validateOpts(opts ...Opt) (*cfg, *url.URL, error) {
 	}
 	// if auth token passed, handle authenticated requests
 	if optCfg.authToken != "" {
-		switch strings.ToLower(optCfg.authType) {
-		case "bearer":
-			optCfg.httpAuthWriter = func(req *http.Request) {
-				req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", optCfg.authToken))
-			}
-		default:
-			optCfg.httpAuthWriter = func(req *http.Request) {
-				req.Header.Set("Authorization", fmt.Sprintf("Basic %s", optCfg.authToken))
-			}
-			fmt.Println("auth type not supported, defaulting to basic auth")
+		scheme, err := parseAuthScheme(optCfg.authType)
+		if err != nil {
+			return nil, nil, err
 		}
+		optCfg.credentials = StaticCredentials(scheme, optCfg.authToken)
 	}
 
 	return optCfg, pinotControllerUrl, nil
@@ -157,14 +242,7 @@ func validateOpts(opts ...Opt) (*cfg, *url.URL, error) {
 
 func defaultCfg() *cfg {
 	return &cfg{
-		httpAuthWriter: defaultAuthWriter(),
-		logger:         defaultLogger(),
-	}
-}
-
-func defaultAuthWriter() func(*http.Request) {
-	return func(req *http.Request) {
-		// do nothing
+		logger: defaultLogger(),
 	}
 }
 
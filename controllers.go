@@ -0,0 +1,340 @@
+package goPinotAPI
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FailoverOptions controls how a client with several controllers routes and fails over requests
+type FailoverOptions struct {
+	// EjectionDuration is how long a controller that could not be reached is only tried after every
+	// other controller, defaults to 30s
+	EjectionDuration time.Duration
+	// LeaderCacheTTL is how long the lead controller of a table is cached, defaults to 1m
+	LeaderCacheTTL time.Duration
+	// HealthCheckInterval is how often the health endpoint of each controller is checked before sending
+	// it requests, controllers reporting unhealthy are ejected until they report healthy again. Controllers
+	// are only ejected on connection errors when zero, the default
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout is the time allowed for a controller health check, defaults to 2s
+	HealthCheckTimeout time.Duration
+}
+
+func (o FailoverOptions) withDefaults() FailoverOptions {
+	if o.EjectionDuration <= 0 {
+		o.EjectionDuration = 30 * time.Second
+	}
+	if o.LeaderCacheTTL <= 0 {
+		o.LeaderCacheTTL = time.Minute
+	}
+	if o.HealthCheckTimeout <= 0 {
+		o.HealthCheckTimeout = 2 * time.Second
+	}
+	return o
+}
+
+const failover = "failover"
+
+// failoverOpt is an option to set how the client fails over between controllers
+type failoverOpt struct {
+	options FailoverOptions
+}
+
+func (o *failoverOpt) apply(c *cfg) {
+	c.failover = o.options
+}
+
+func (o *failoverOpt) Type() string {
+	return failover
+}
+
+// Failover sets how a client created with ControllerUrls fails over between its controllers
+func Failover(options FailoverOptions) Opt {
+	return &failoverOpt{options: options}
+}
+
+type controller struct {
+	url          *url.URL
+	id           string
+	ejectedUntil time.Time
+	checkedAt    time.Time
+}
+
+type leadController struct {
+	controller *controller
+	expires    time.Time
+}
+
+// controllerPool holds the controllers of a client created with ControllerUrls
+type controllerPool struct {
+	primary     *url.URL
+	controllers []*controller
+	options     FailoverOptions
+
+	mu      sync.Mutex
+	leaders map[string]leadController
+}
+
+func newControllerPool(primary *url.URL, urls []*url.URL, options FailoverOptions) *controllerPool {
+
+	pool := &controllerPool{
+		primary: primary,
+		options: options.withDefaults(),
+		leaders: make(map[string]leadController),
+	}
+
+	for _, u := range urls {
+		pool.controllers = append(pool.controllers, &controller{url: u, id: controllerInstanceId(u)})
+	}
+
+	return pool
+}
+
+// controllerInstanceId is the helix instance id of the controller at the url, Controller_{host}_{port}
+func controllerInstanceId(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return fmt.Sprintf("Controller_%s_%s", u.Hostname(), port)
+}
+
+// candidates orders the controllers to try, the lead controller first when known and reachable,
+// then the other reachable controllers, then the ejected ones
+func (p *controllerPool) candidates(lead *controller) []*controller {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var reachable, ejected []*controller
+
+	if lead != nil {
+		if now.Before(lead.ejectedUntil) {
+			ejected = append(ejected, lead)
+		} else {
+			reachable = append(reachable, lead)
+		}
+	}
+
+	for _, c := range p.controllers {
+		if c == lead {
+			continue
+		}
+		if now.Before(c.ejectedUntil) {
+			ejected = append(ejected, c)
+		} else {
+			reachable = append(reachable, c)
+		}
+	}
+
+	return append(reachable, ejected...)
+}
+
+func (p *controllerPool) eject(c *controller) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c.ejectedUntil = time.Now().Add(p.options.EjectionDuration)
+}
+
+func (p *controllerPool) restore(c *controller) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	c.ejectedUntil = time.Time{}
+}
+
+// dueForHealthCheck returns the controllers whose health was last checked longer than the health check
+// interval ago, marking them as checked so concurrent requests don't check them again
+func (p *controllerPool) dueForHealthCheck() []*controller {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.options.HealthCheckInterval <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var due []*controller
+	for _, c := range p.controllers {
+		if now.Sub(c.checkedAt) >= p.options.HealthCheckInterval {
+			c.checkedAt = now
+			due = append(due, c)
+		}
+	}
+	return due
+}
+
+func (p *controllerPool) cachedLeader(tableName string) (*controller, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lead, ok := p.leaders[tableName]
+	if !ok || time.Now().After(lead.expires) {
+		return nil, false
+	}
+	return lead.controller, true
+}
+
+// setLeader caches the lead controller of a table, nil when it is unknown
+func (p *controllerPool) setLeader(tableName string, c *controller) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.leaders[tableName] = leadController{controller: c, expires: time.Now().Add(p.options.LeaderCacheTTL)}
+}
+
+func (p *controllerPool) byInstanceId(id string) *controller {
+	for _, c := range p.controllers {
+		if c.id == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// rewrite points a request built against the primary controller at another controller
+func (p *controllerPool) rewrite(req *http.Request, c *controller) (*http.Request, error) {
+
+	path := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(p.primary.Path, "/"))
+	target := c.url.JoinPath(path)
+	target.RawQuery = req.URL.RawQuery
+	// JoinPath on a url without a path leaves the path relative
+	if !strings.HasPrefix(target.Path, "/") {
+		target.Path = "/" + target.Path
+	}
+
+	attempt := req.Clone(req.Context())
+	attempt.URL = target
+	attempt.Host = ""
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("client: could not replay request body: %w", err)
+		}
+		attempt.Body = body
+	}
+
+	return attempt, nil
+}
+
+// sendToControllers sends a request to the client's controllers, routing mutating calls on a table to
+// its lead controller and failing over to the next controller when one can't be reached
+func (c *PinotAPIClient) sendToControllers(req *http.Request) (*http.Response, error) {
+
+	if c.controllers == nil {
+		return c.pinotHttp.Do(req)
+	}
+
+	c.checkControllersHealth(req.Context())
+
+	var lead *controller
+	if req.Method != http.MethodGet {
+		if tableName := operationTableName(req.URL.Path); tableName != "" {
+			lead = c.leadController(tableName)
+		}
+	}
+
+	var lastErr error
+	for _, controller := range c.controllers.candidates(lead) {
+
+		attempt, err := c.controllers.rewrite(req, controller)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := c.pinotHttp.Do(attempt)
+		if err == nil {
+			c.controllers.restore(controller)
+			return res, nil
+		}
+
+		if req.Context().Err() != nil || !isConnectionError(err, attempt) {
+			return nil, err
+		}
+
+		c.log.Debug(fmt.Sprintf("controller %s can not be reached, failing over: %s", controller.url, err))
+		c.controllers.eject(controller)
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("client: no controller could be reached: %w", lastErr)
+}
+
+// checkControllersHealth checks the health of the controllers that are due a health check, ejecting
+// the unhealthy ones and restoring the healthy ones
+func (c *PinotAPIClient) checkControllersHealth(ctx context.Context) {
+
+	for _, controller := range c.controllers.dueForHealthCheck() {
+
+		probeCtx, cancel := context.WithTimeout(ctx, c.controllers.options.HealthCheckTimeout)
+		err := c.probeHealth(probeCtx, controller.url.JoinPath("/health").String())
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			c.log.Debug(fmt.Sprintf("controller %s is unhealthy, ejecting: %s", controller.url, err))
+			c.controllers.eject(controller)
+			continue
+		}
+		c.controllers.restore(controller)
+	}
+}
+
+// leadController returns the controller leading a table, nil when it is unknown or not one of the
+// client's controllers
+func (c *PinotAPIClient) leadController(tableName string) *controller {
+
+	rawTableName, _ := splitTableNameWithType(tableName)
+
+	if lead, ok := c.controllers.cachedLeader(rawTableName); ok {
+		return lead
+	}
+
+	var lead *controller
+	leaders, err := c.GetLeaderForTable(rawTableName)
+	if err != nil {
+		c.log.Debug(fmt.Sprintf("unable to get lead controller of table %s: %s", rawTableName, err))
+	} else if id, ok := leaders.LeaderForTable(rawTableName); ok {
+		lead = c.controllers.byInstanceId(id)
+	}
+
+	c.controllers.setLeader(rawTableName, lead)
+	return lead
+}
+
+// isConnectionError reports whether a request failed because the controller could not be reached,
+// as opposed to failing to get credentials. Requests that are not idempotent only fail over when the
+// connection could not be made, a connection lost after sending them may have been applied already
+func isConnectionError(err error, req *http.Request) bool {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil || u.Host != req.URL.Host {
+		return false
+	}
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
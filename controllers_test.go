@@ -0,0 +1,220 @@
+package goPinotAPI_test
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// testController is a controller of a test cluster that counts the requests it receives
+type testController struct {
+	*httptest.Server
+	requests int32
+	leader   func() string
+}
+
+func newTestController(t *testing.T) *testController {
+	c := &testController{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&c.requests, 1)
+		switch {
+		case r.URL.Path == "/leader/tables/airlineStats":
+			fmt.Fprintf(w, `{"leadControllerResourceEnabled": true, "leadControllerEntryMap": {"leadControllerResource_0": {"tableNames": ["airlineStats"], "leadControllerId": "%s"}}}`, c.leader())
+		case r.URL.Path == "/health":
+			fmt.Fprint(w, "OK")
+		case r.URL.Path == "/cluster/info":
+			fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/users":
+			fmt.Fprint(w, `{"status": "User test_BROKER has been successfully added!"}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/tables/airlineStats":
+			fmt.Fprint(w, `{"status": "Tables: [airlineStats_OFFLINE] deleted"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *testController) instanceId() string {
+	u, _ := url.Parse(c.URL)
+	return fmt.Sprintf("Controller_%s_%s", u.Hostname(), u.Port())
+}
+
+// unreachableControllerUrl returns the url of a port nothing listens on
+func unreachableControllerUrl(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	return "http://" + address
+}
+
+func TestControllerFailover(t *testing.T) {
+	second := newTestController(t)
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrls(unreachableControllerUrl(t), second.URL),
+	)
+
+	res, err := client.GetClusterInfo()
+	assert.NoError(t, err, "Expected request to fail over to the second controller")
+	assert.Equal(t, "PinotCluster", res.ClusterName)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&second.requests))
+}
+
+func TestControllerFailoverAllUnreachable(t *testing.T) {
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrls(unreachableControllerUrl(t), unreachableControllerUrl(t)),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.ErrorContains(t, err, "no controller could be reached")
+}
+
+func TestControllerFailoverNotSent(t *testing.T) {
+	second := newTestController(t)
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrls(unreachableControllerUrl(t), second.URL),
+	)
+
+	_, err := client.CreateUser(model.User{Username: "test", Component: model.UserComponentBroker})
+	assert.NoError(t, err, "Expected a request that was never sent to fail over")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&second.requests))
+}
+
+func TestControllerNoFailoverAfterSent(t *testing.T) {
+	first := newTestController(t)
+	second := newTestController(t)
+
+	// the first controller leads the table but resets the connection once a delete reaches it
+	first.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&first.requests, 1)
+		if r.Method == http.MethodDelete {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		fmt.Fprintf(w, `{"leadControllerResourceEnabled": true, "leadControllerEntryMap": {"leadControllerResource_0": {"tableNames": ["airlineStats"], "leadControllerId": "%s"}}}`, first.instanceId())
+	})
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrls(first.URL, second.URL),
+	)
+
+	_, err := client.DeleteTable("airlineStats")
+	assert.Error(t, err, "Expected the delete to fail without being retried")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&second.requests), "Expected a delete that may have been applied not to fail over")
+}
+
+func TestControllerEjection(t *testing.T) {
+	first := newTestController(t)
+	second := newTestController(t)
+
+	// the first controller resets every connection until it is healthy again
+	var firstHealthy atomic.Bool
+	first.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&first.requests, 1)
+		if !firstHealthy.Load() {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+	})
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrls(first.URL, second.URL),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	failedRequests := atomic.LoadInt32(&first.requests)
+	assert.Greater(t, failedRequests, int32(0))
+
+	firstHealthy.Store(true)
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, failedRequests, atomic.LoadInt32(&first.requests), "Expected ejected controller to be skipped")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&second.requests))
+}
+
+func TestControllerHealthEjection(t *testing.T) {
+	first := newTestController(t)
+	second := newTestController(t)
+
+	// the first controller answers requests but reports unhealthy until it is healthy again
+	var firstHealthy atomic.Bool
+	var healthChecks int32
+	first.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			atomic.AddInt32(&healthChecks, 1)
+			if !firstHealthy.Load() {
+				http.Error(w, "UNHEALTHY", http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, "OK")
+			return
+		}
+		atomic.AddInt32(&first.requests, 1)
+		fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+	})
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrls(first.URL, second.URL),
+		goPinotAPI.Failover(goPinotAPI.FailoverOptions{HealthCheckInterval: 50 * time.Millisecond}),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&first.requests), "Expected the unhealthy controller to be ejected")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&healthChecks), "Expected health to be checked once per interval")
+
+	firstHealthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&first.requests), "Expected the controller to be restored once healthy")
+}
+
+func TestControllerLeaderRouting(t *testing.T) {
+	first := newTestController(t)
+	second := newTestController(t)
+	first.leader = second.instanceId
+	second.leader = second.instanceId
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrls(first.URL, second.URL),
+	)
+
+	_, err := client.DeleteTable("airlineStats")
+	assert.NoError(t, err)
+
+	// the leader is looked up on the first controller and the delete is sent to the leader
+	assert.Equal(t, int32(1), atomic.LoadInt32(&first.requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&second.requests))
+
+	_, err = client.DeleteTable("airlineStats")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&first.requests), "Expected lead controller to be cached")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&second.requests))
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&first.requests), "Expected reads to go to the first controller")
+}
@@ -0,0 +1,237 @@
+package goPinotAPI
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCredentialsNotRefreshable is returned by CredentialProvider.Refresh when the credentials are fixed
+var ErrCredentialsNotRefreshable = errors.New("credentials can not be refreshed")
+
+// CredentialProvider supplies the Authorization header for requests to the controller.
+// Providers cache their credentials and must be safe for concurrent use
+type CredentialProvider interface {
+	// Authorization returns the Authorization header value, fetching or refreshing the credentials
+	// when they are missing or about to expire. An empty value sends the request unauthenticated
+	Authorization(ctx context.Context) (string, error)
+	// Refresh discards the cached credentials and fetches new ones. It is called once when the
+	// controller rejects a request with 401, and the request is retried if it returns nil
+	Refresh(ctx context.Context) error
+}
+
+const authProvider = "authProvider"
+
+// authProviderOpt is an option to set the credential provider for the client
+type authProviderOpt struct {
+	provider CredentialProvider
+}
+
+func (o *authProviderOpt) apply(c *cfg) {
+	c.credentials = o.provider
+}
+
+func (o *authProviderOpt) Type() string {
+	return authProvider
+}
+
+// AuthProvider authenticates requests with the given credential provider
+func AuthProvider(provider CredentialProvider) Opt {
+	return &authProviderOpt{provider: provider}
+}
+
+// staticCredentials always sends the same token
+type staticCredentials struct {
+	authorization string
+}
+
+// StaticCredentials returns a provider for a fixed token of the given scheme
+func StaticCredentials(scheme AuthScheme, token string) CredentialProvider {
+	return &staticCredentials{authorization: fmt.Sprintf("%s %s", scheme, token)}
+}
+
+func (s *staticCredentials) Authorization(ctx context.Context) (string, error) {
+	return s.authorization, nil
+}
+
+func (s *staticCredentials) Refresh(ctx context.Context) error {
+	return ErrCredentialsNotRefreshable
+}
+
+// OAuth2ClientCredentials fetches bearer tokens from an OAuth2 token endpoint with the client
+// credentials grant, caching each token until shortly before it expires
+type OAuth2ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// EarlyExpiry is how long before expiry a token is refreshed, defaults to 30s and is capped at
+	// half of the token lifetime
+	EarlyExpiry time.Duration
+	// HTTPClient is used to call the token endpoint, defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (o *OAuth2ClientCredentials) Authorization(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token == "" || (!o.refreshAt.IsZero() && !time.Now().Before(o.refreshAt)) {
+		if err := o.fetch(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%s %s", AuthTypeBearer, o.token), nil
+}
+
+func (o *OAuth2ClientCredentials) Refresh(ctx context.Context) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.fetch(ctx)
+}
+
+func (o *OAuth2ClientCredentials) fetch(ctx context.Context) error {
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(o.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("client: could not create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret))
+
+	httpClient := o.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("client: could not send token request: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("client: could not read token response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("client: token request failed with status code %d: %s", res.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var token oauth2TokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return fmt.Errorf("client: could not unmarshal token response: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return fmt.Errorf("client: token response has no access token")
+	}
+
+	o.token = token.AccessToken
+	o.refreshAt = time.Time{}
+	if token.ExpiresIn > 0 {
+		lifetime := time.Duration(token.ExpiresIn) * time.Second
+		early := o.EarlyExpiry
+		if early <= 0 {
+			early = 30 * time.Second
+		}
+		early = min(early, lifetime/2)
+		o.refreshAt = time.Now().Add(lifetime - early)
+	}
+
+	return nil
+}
+
+// fileCredentials reads a token from a file, reloading it whenever the file changes
+type fileCredentials struct {
+	path   string
+	scheme AuthScheme
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// FileCredentials returns a provider that reads a token of the given scheme from a file, such as a
+// mounted secret or a token written by a sidecar, and picks up changes to the file
+func FileCredentials(path string, scheme AuthScheme) CredentialProvider {
+	return &fileCredentials{path: path, scheme: scheme}
+}
+
+func (f *fileCredentials) Authorization(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("client: could not stat token file: %w", err)
+	}
+
+	if f.token == "" || !info.ModTime().Equal(f.modTime) || info.Size() != f.size {
+		if err := f.load(); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%s %s", f.scheme, f.token), nil
+}
+
+func (f *fileCredentials) Refresh(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.load()
+}
+
+func (f *fileCredentials) load() error {
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("client: could not stat token file: %w", err)
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("client: could not read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return fmt.Errorf("client: token file %s is empty", f.path)
+	}
+
+	f.token = token
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return nil
+}
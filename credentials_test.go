@@ -0,0 +1,133 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+// createOAuth2Server issues a new token on every token request and only accepts the latest one
+func createOAuth2Server(t *testing.T, tokensIssued *int32) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok, "Expected client credentials in basic auth")
+		assert.Equal(t, "pinot-client", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "pinot:read pinot:write", r.PostForm.Get("scope"))
+
+		issued := atomic.AddInt32(tokensIssued, 1)
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600}`, issued)
+	})
+
+	mux.HandleFunc(RouteClusterInfo, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", atomic.LoadInt32(tokensIssued)) {
+			http.Error(w, `{"code": 401, "error": "HTTP 401 Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var tokensIssued int32
+	server := createOAuth2Server(t, &tokensIssued)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.AuthProvider(&goPinotAPI.OAuth2ClientCredentials{
+			TokenURL:     server.URL + "/oauth/token",
+			ClientID:     "pinot-client",
+			ClientSecret: "secret",
+			Scopes:       []string{"pinot:read", "pinot:write"},
+		}),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokensIssued), "Expected token to be cached")
+
+	// revoke the cached token, the client should refresh and retry once
+	atomic.AddInt32(&tokensIssued, 1)
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&tokensIssued), "Expected token to be refreshed after 401")
+}
+
+func TestOAuth2ClientCredentialsTokenError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	provider := &goPinotAPI.OAuth2ClientCredentials{TokenURL: server.URL, ClientID: "pinot-client", ClientSecret: "wrong"}
+
+	_, err := provider.Authorization(context.Background())
+	assert.ErrorContains(t, err, "invalid_client")
+}
+
+func TestStaticCredentialsNotRetried(t *testing.T) {
+	var requests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteClusterInfo, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, `{"code": 401, "error": "HTTP 401 Unauthorized"}`, http.StatusUnauthorized)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.AuthProvider(goPinotAPI.StaticCredentials(goPinotAPI.AuthTypeBearer, "your_token")),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Expected static credentials not to be retried")
+}
+
+func TestFileCredentials(t *testing.T) {
+	var authHeader string
+	server := createAuthCheckServer(&authHeader)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(path, []byte("first\n"), 0600))
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.AuthProvider(goPinotAPI.FileCredentials(path, goPinotAPI.AuthTypeBearer)),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer first", authHeader)
+
+	assert.NoError(t, os.WriteFile(path, []byte("rotated\n"), 0600))
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer rotated", authHeader, "Expected rotated token to be picked up")
+
+	assert.NoError(t, os.Remove(path))
+
+	_, err = client.GetClusterInfo()
+	assert.Error(t, err, "Expected error when the token file is missing")
+}
@@ -0,0 +1,256 @@
+package goPinotAPI
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// DecommissionOptions configures DecommissionServer
+type DecommissionOptions struct {
+	// DryRun reports the tables and segments that would be moved without changing the cluster
+	DryRun bool
+	// RebalanceOptions are used for every table rebalance, instances are always reassigned and consuming
+	// segments always included
+	RebalanceOptions model.RebalanceTableOptions
+	// AllowDowntime lets tables with a single replica be rebalanced with downtime, the decommission fails
+	// before untagging the server without it if it has segments of such tables
+	AllowDowntime bool
+	// Wait configures how rebalances and external view convergence are polled
+	Wait *WaitOptions
+	// OnProgress is called before each step and table, save Progress.State to resume after an interruption
+	OnProgress func(progress DecommissionProgress)
+}
+
+// DecommissionProgress is passed to DecommissionOptions.OnProgress
+type DecommissionProgress struct {
+	Step    model.DecommissionStep
+	Table   string
+	Message string
+	State   *model.DecommissionState
+}
+
+// DecommissionServer removes a server from the cluster: it untags the server, rebalances every table with segments
+// on it, waits for the external views to converge, verifies no ideal state references it, disables it and deletes it.
+//
+// Pass the state returned by a previous call to resume from the step that was interrupted, or nil to start. If a
+// step before the server is disabled fails, the server's tags are restored and the state is reset to start over.
+// Context cancellation does not roll back, so the decommission can be resumed.
+func (c *PinotAPIClient) DecommissionServer(ctx context.Context, instanceName string, state *model.DecommissionState, opts *DecommissionOptions) (*model.DecommissionState, error) {
+
+	var decommissionOpts DecommissionOptions
+	if opts != nil {
+		decommissionOpts = *opts
+	}
+	if model.InstanceTypeOf(instanceName) != model.InstanceTypeServer {
+		return nil, fmt.Errorf("instance %s is not a server", instanceName)
+	}
+
+	if decommissionOpts.DryRun {
+		return c.planDecommission(instanceName)
+	}
+
+	if state == nil {
+		state = &model.DecommissionState{InstanceName: instanceName, Step: model.DecommissionStepUntag}
+	} else if state.InstanceName != instanceName {
+		return nil, fmt.Errorf("state is for instance %s, not %s", state.InstanceName, instanceName)
+	}
+	state.Error = ""
+
+	progress := func(table string, message string) {
+		if decommissionOpts.OnProgress != nil {
+			decommissionOpts.OnProgress(DecommissionProgress{Step: state.Step, Table: table, Message: message, State: state})
+		}
+	}
+
+	for !state.IsDone() {
+
+		err := c.runDecommissionStep(ctx, state, decommissionOpts, progress)
+		if err == nil {
+			continue
+		}
+
+		state.Error = err.Error()
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return state, err
+		}
+
+		stepIndex := slices.Index(model.DecommissionSteps, state.Step)
+		if stepIndex > 0 && stepIndex < slices.Index(model.DecommissionSteps, model.DecommissionStepDisable) {
+			// untagging the server with no tags would leave it out of every tenant
+			if len(state.PreviousTags) == 0 {
+				return state, fmt.Errorf("%w, and the tags of server %s are unknown so they can not be restored", err, instanceName)
+			}
+
+			progress("", "rolling back tags")
+			if _, rollbackErr := c.UpdateInstanceTags(instanceName, state.PreviousTags, false); rollbackErr != nil {
+				return state, fmt.Errorf("%w, and unable to restore tags of server %s: %w", err, instanceName, rollbackErr)
+			}
+			state.RolledBack = true
+			state.Step = model.DecommissionStepUntag
+			state.RebalancedTables = nil
+			state.ConvergedTables = nil
+		}
+
+		return state, err
+	}
+
+	progress("", "server decommissioned")
+	return state, nil
+}
+
+func (c *PinotAPIClient) runDecommissionStep(ctx context.Context, state *model.DecommissionState, opts DecommissionOptions, progress func(table string, message string)) error {
+
+	switch state.Step {
+
+	case model.DecommissionStepUntag:
+		progress("", "untagging server")
+
+		instance, err := c.GetInstance(state.InstanceName)
+		if err != nil {
+			return fmt.Errorf("unable to get instance %s: %w", state.InstanceName, err)
+		}
+
+		tables, err := c.GetInstanceTableReferences(state.InstanceName)
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.drainRebalanceOptions(tables, opts.RebalanceOptions, opts.AllowDowntime); err != nil {
+			return err
+		}
+
+		// a decommission interrupted after untagging keeps the original tags rather than the untagged tag
+		if !slices.Equal(instance.Tags, []string{untaggedServerTag}) {
+			state.PreviousTags = instance.Tags
+		}
+		state.Tables = tables
+		state.RolledBack = false
+
+		if _, err := c.UpdateInstanceTags(state.InstanceName, []string{untaggedServerTag}, false); err != nil {
+			return fmt.Errorf("unable to untag server %s: %w", state.InstanceName, err)
+		}
+		state.Step = model.DecommissionStepRebalance
+
+	case model.DecommissionStepRebalance:
+		rebalanceOptions, err := c.drainRebalanceOptions(state.Tables, opts.RebalanceOptions, opts.AllowDowntime)
+		if err != nil {
+			return err
+		}
+
+		for _, tableNameWithType := range state.Tables {
+			if slices.Contains(state.RebalancedTables, tableNameWithType) {
+				continue
+			}
+			progress(tableNameWithType, "rebalancing table")
+
+			tableName, tableType := splitTableNameWithType(tableNameWithType)
+			status, err := c.RebalanceTableAndWait(ctx, tableName, tableType, rebalanceOptions[tableNameWithType], opts.Wait)
+			if err != nil {
+				return fmt.Errorf("unable to rebalance table %s: %w", tableNameWithType, err)
+			}
+
+			stats := status.TableRebalanceProgressStats
+			if stats.Status != model.RebalanceStatusDone && stats.Status != model.RebalanceStatusNoOp {
+				return fmt.Errorf("rebalance of table %s finished with status %s: %s", tableNameWithType, stats.Status, stats.CompletionStatusMsg)
+			}
+			state.RebalancedTables = append(state.RebalancedTables, tableNameWithType)
+		}
+		state.Step = model.DecommissionStepConverge
+
+	case model.DecommissionStepConverge:
+		for _, tableNameWithType := range state.Tables {
+			if slices.Contains(state.ConvergedTables, tableNameWithType) {
+				continue
+			}
+			progress(tableNameWithType, "waiting for external view to converge")
+
+			tableName, _ := splitTableNameWithType(tableNameWithType)
+			if _, err := c.WaitForTableConvergence(ctx, tableName, opts.Wait); err != nil {
+				return fmt.Errorf("external view of table %s did not converge: %w", tableNameWithType, err)
+			}
+			state.ConvergedTables = append(state.ConvergedTables, tableNameWithType)
+		}
+		state.Step = model.DecommissionStepVerify
+
+	case model.DecommissionStepVerify:
+		progress("", "verifying no segments remain on server")
+
+		references, err := c.tablesWithSegmentsOn(state.InstanceName, state.Tables)
+		if err != nil {
+			return err
+		}
+		if len(references) > 0 {
+			return fmt.Errorf("server %s still has segments of tables: %v", state.InstanceName, references)
+		}
+		state.Step = model.DecommissionStepDisable
+
+	case model.DecommissionStepDisable:
+		progress("", "disabling server")
+
+		if _, err := c.DisableInstance(state.InstanceName); err != nil {
+			return fmt.Errorf("unable to disable server %s: %w", state.InstanceName, err)
+		}
+		state.Step = model.DecommissionStepDelete
+
+	case model.DecommissionStepDelete:
+		progress("", "deleting server")
+
+		if _, err := c.SafeDeleteInstance(state.InstanceName); err != nil {
+			return fmt.Errorf("unable to delete server %s: %w", state.InstanceName, err)
+		}
+		state.Step = model.DecommissionStepDone
+
+	default:
+		return fmt.Errorf("unknown decommission step %s", state.Step)
+	}
+
+	return nil
+}
+
+// planDecommission reports the tables and segments a decommission would move without changing the cluster
+func (c *PinotAPIClient) planDecommission(instanceName string) (*model.DecommissionState, error) {
+
+	instance, err := c.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instance %s: %w", instanceName, err)
+	}
+
+	tables, err := c.GetInstanceTableReferences(instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &model.DecommissionState{
+		InstanceName:     instanceName,
+		Step:             model.DecommissionStepUntag,
+		PreviousTags:     instance.Tags,
+		Tables:           tables,
+		SegmentsOnServer: make(map[string]int, len(tables)),
+		DryRun:           true,
+	}
+
+	for _, tableNameWithType := range tables {
+		tableName, tableType := splitTableNameWithType(tableNameWithType)
+
+		idealState, err := c.GetTableIdealState(tableName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get ideal state of table %s: %w", tableName, err)
+		}
+
+		segments := idealState.Offline
+		if tableType == "REALTIME" {
+			segments = idealState.Realtime
+		}
+		for _, instanceStates := range segments {
+			if _, ok := instanceStates[instanceName]; ok {
+				state.SegmentsOnServer[tableNameWithType]++
+			}
+		}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,148 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecommissionServerDryRun(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.DecommissionServer(context.Background(), "Server_172.17.0.3_7050", nil, &goPinotAPI.DecommissionOptions{DryRun: true})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.True(t, res.DryRun, "Expected state to be a dry run")
+	assert.Equal(t, []string{"test_OFFLINE"}, res.Tables, "Expected test_OFFLINE to be affected")
+	assert.Equal(t, 1, res.SegmentsOnServer["test_OFFLINE"], "Expected 1 segment to be moved")
+	assert.Equal(t, model.DecommissionStepUntag, res.Step, "Expected no steps to have run")
+}
+
+func TestDecommissionServer(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.DecommissionServer(context.Background(), "Server_172.17.0.3_7050", nil, &goPinotAPI.DecommissionOptions{AllowDowntime: true, Wait: fastWaitOptions})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.True(t, res.IsDone(), "Expected server to be decommissioned")
+	assert.Equal(t, []string{"test_OFFLINE"}, res.RebalancedTables, "Expected test_OFFLINE to be moved off the server")
+
+	idealState, err := client.GetTableIdealState("test")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Server_172.17.0.4_7050": "ONLINE"}, idealState.Offline["test_OFFLINE_0"], "Expected the segment to be moved to the other server")
+
+	_, err = client.GetInstance("Server_172.17.0.3_7050")
+	assert.Error(t, err, "Expected the server to be deleted")
+}
+
+func TestDecommissionServerRollback(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	// the ideal state never changes so verification fails after rebalancing
+	controller.Handle(http.MethodGet, RouteTablesTestIdealState, handleTableIdealState)
+	controller.Handle(http.MethodGet, RouteTablesTestExternalView, handleTableExternalView)
+
+	var steps []model.DecommissionStep
+	opts := &goPinotAPI.DecommissionOptions{
+		AllowDowntime: true,
+		Wait:          fastWaitOptions,
+		OnProgress: func(progress goPinotAPI.DecommissionProgress) {
+			steps = append(steps, progress.Step)
+		},
+	}
+
+	res, err := client.DecommissionServer(context.Background(), "Server_172.17.0.3_7050", nil, opts)
+	assert.ErrorContains(t, err, "still has segments", "Expected verification to fail")
+
+	assert.True(t, res.RolledBack, "Expected tags to be rolled back")
+	assert.Equal(t, model.DecommissionStepUntag, res.Step, "Expected decommission to restart from the beginning")
+	assert.Equal(t, []string{"DefaultTenant_OFFLINE", "DefaultTenant_REALTIME"}, res.PreviousTags, "Expected previous tags to be recorded")
+	assert.Equal(t, []model.DecommissionStep{
+		model.DecommissionStepUntag,
+		model.DecommissionStepRebalance,
+		model.DecommissionStepConverge,
+		model.DecommissionStepVerify,
+		model.DecommissionStepVerify,
+	}, steps, "Expected progress for every step and the rollback")
+}
+
+func TestDecommissionServerResume(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	state := &model.DecommissionState{
+		InstanceName: "Server_172.17.0.4_7050",
+		Step:         model.DecommissionStepDisable,
+		PreviousTags: []string{"DefaultTenant_OFFLINE"},
+	}
+
+	res, err := client.DecommissionServer(context.Background(), "Server_172.17.0.4_7050", state, &goPinotAPI.DecommissionOptions{Wait: fastWaitOptions})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.True(t, res.IsDone(), "Expected server to be decommissioned")
+}
+
+func TestDecommissionServerCancelled(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.UpdateInstanceTags("Server_172.17.0.3_7050", []string{"server_untagged"}, false)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	state := &model.DecommissionState{
+		InstanceName: "Server_172.17.0.3_7050",
+		Step:         model.DecommissionStepRebalance,
+		PreviousTags: []string{"DefaultTenant_OFFLINE"},
+		Tables:       []string{"test_OFFLINE"},
+	}
+
+	res, err := client.DecommissionServer(ctx, "Server_172.17.0.3_7050", state, &goPinotAPI.DecommissionOptions{AllowDowntime: true, Wait: fastWaitOptions})
+	assert.ErrorIs(t, err, context.Canceled, "Expected decommission to stop when cancelled")
+
+	assert.False(t, res.RolledBack, "Expected cancellation not to roll back")
+	assert.Equal(t, model.DecommissionStepRebalance, res.Step, "Expected decommission to resume from the rebalance")
+}
+
+func TestDecommissionServerSingleReplica(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.DecommissionServer(context.Background(), "Server_172.17.0.3_7050", nil, &goPinotAPI.DecommissionOptions{Wait: fastWaitOptions})
+	assert.ErrorContains(t, err, "single replica", "Expected a table with a single replica to need downtime")
+
+	assert.Equal(t, model.DecommissionStepUntag, res.Step, "Expected the server not to be untagged")
+	assert.False(t, res.RolledBack, "Expected nothing to roll back")
+}
+
+func TestDecommissionServerRollbackUnknownTags(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	state := &model.DecommissionState{
+		InstanceName: "Server_172.17.0.3_7050",
+		Step:         model.DecommissionStepVerify,
+		Tables:       []string{"test_OFFLINE"},
+	}
+
+	res, err := client.DecommissionServer(context.Background(), "Server_172.17.0.3_7050", state, &goPinotAPI.DecommissionOptions{Wait: fastWaitOptions})
+	assert.ErrorContains(t, err, "can not be restored", "Expected a rollback without previous tags to be refused")
+
+	assert.False(t, res.RolledBack, "Expected the server not to be given empty tags")
+	assert.Equal(t, model.DecommissionStepVerify, res.Step, "Expected the decommission to stay at the failed step")
+}
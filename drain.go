@@ -0,0 +1,250 @@
+package goPinotAPI
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// untaggedServerTag is the tag pinot gives servers that do not belong to a tenant
+const untaggedServerTag = "server_untagged"
+
+// GetInstanceTableReferences returns the tables, with their type suffix, whose ideal state assigns segments to the instance
+func (c *PinotAPIClient) GetInstanceTableReferences(instanceName string) ([]string, error) {
+
+	tables, err := c.GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tables: %w", err)
+	}
+
+	references := []string{}
+	for _, tableName := range tables.Tables {
+
+		idealState, err := c.GetTableIdealState(tableName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get ideal state of table %s: %w", tableName, err)
+		}
+
+		if referencesInstance(idealState.Offline, instanceName) {
+			references = append(references, tableName+"_OFFLINE")
+		}
+		if referencesInstance(idealState.Realtime, instanceName) {
+			references = append(references, tableName+"_REALTIME")
+		}
+	}
+
+	sort.Strings(references)
+	return references, nil
+}
+
+func referencesInstance(idealState map[string]map[string]string, instanceName string) bool {
+	for _, instanceStates := range idealState {
+		if _, ok := instanceStates[instanceName]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTableNameWithType splits a table name such as test_OFFLINE into test and OFFLINE
+func splitTableNameWithType(tableNameWithType string) (string, string) {
+	for _, tableType := range []string{"OFFLINE", "REALTIME"} {
+		if tableName, ok := strings.CutSuffix(tableNameWithType, "_"+tableType); ok {
+			return tableName, tableType
+		}
+	}
+	return tableNameWithType, ""
+}
+
+// DrainServerOptions configures DrainServer
+type DrainServerOptions struct {
+	// AllowDowntime lets tables with a single replica be rebalanced with downtime, their segments on the server are
+	// unavailable while they move. Draining a server with segments of such tables fails without it
+	AllowDowntime bool
+	// Wait configures how rebalances are polled
+	Wait *WaitOptions
+}
+
+// DrainServer moves every segment off a server by untagging it and rebalancing each table that references it,
+// waiting for each rebalance to finish. Instances are reassigned so persisted instance partitions don't keep the
+// server, and consuming segments are moved too. It fails if the ideal state or external view of a table still
+// holds segments on the server afterwards. The server is left running, use SafeDeleteInstance once it is drained.
+func (c *PinotAPIClient) DrainServer(ctx context.Context, instanceName string, opts *DrainServerOptions) (*model.DrainServerResult, error) {
+
+	var drainOpts DrainServerOptions
+	if opts != nil {
+		drainOpts = *opts
+	}
+
+	if model.InstanceTypeOf(instanceName) != model.InstanceTypeServer {
+		return nil, fmt.Errorf("instance %s is not a server", instanceName)
+	}
+
+	instance, err := c.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instance %s: %w", instanceName, err)
+	}
+
+	tables, err := c.GetInstanceTableReferences(instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	rebalanceOptions, err := c.drainRebalanceOptions(tables, model.RebalanceTableOptions{}, drainOpts.AllowDowntime)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.DrainServerResult{
+		InstanceName:     instanceName,
+		PreviousTags:     instance.Tags,
+		RebalancedTables: tables,
+	}
+
+	_, err = c.UpdateInstanceTags(instanceName, []string{untaggedServerTag}, false)
+	if err != nil {
+		return result, fmt.Errorf("unable to untag server %s: %w", instanceName, err)
+	}
+
+	for _, tableNameWithType := range tables {
+		tableName, tableType := splitTableNameWithType(tableNameWithType)
+
+		status, err := c.RebalanceTableAndWait(ctx, tableName, tableType, rebalanceOptions[tableNameWithType], drainOpts.Wait)
+		if err != nil {
+			return result, fmt.Errorf("unable to rebalance table %s: %w", tableNameWithType, err)
+		}
+
+		if status.TableRebalanceProgressStats.Status != model.RebalanceStatusDone && status.TableRebalanceProgressStats.Status != model.RebalanceStatusNoOp {
+			return result, fmt.Errorf("rebalance of table %s finished with status %s: %s", tableNameWithType,
+				status.TableRebalanceProgressStats.Status, status.TableRebalanceProgressStats.CompletionStatusMsg)
+		}
+	}
+
+	remaining, err := c.tablesWithSegmentsOn(instanceName, tables)
+	if err != nil {
+		return result, err
+	}
+	if len(remaining) > 0 {
+		result.RemainingTables = remaining
+		return result, fmt.Errorf("server %s still has segments of tables: %s", instanceName, strings.Join(remaining, ", "))
+	}
+
+	return result, nil
+}
+
+// drainRebalanceOptions returns the options to rebalance each table off an untagged server. Instances are always
+// reassigned and consuming segments included. Tables with a single replica can't keep a replica up while their
+// segments move, so they are rebalanced with downtime when allowDowntime is set and rejected otherwise
+func (c *PinotAPIClient) drainRebalanceOptions(tables []string, options model.RebalanceTableOptions, allowDowntime bool) (map[string]model.RebalanceTableOptions, error) {
+
+	options.ReassignInstances = true
+	options.IncludeConsuming = true
+	options.DryRun = false
+
+	result := make(map[string]model.RebalanceTableOptions, len(tables))
+	var singleReplica []string
+	for _, tableNameWithType := range tables {
+
+		replication, err := c.tableReplication(tableNameWithType)
+		if err != nil {
+			return nil, err
+		}
+
+		tableOptions := options
+		if replication <= 1 {
+			singleReplica = append(singleReplica, tableNameWithType)
+			tableOptions.Downtime = true
+		}
+		result[tableNameWithType] = tableOptions
+	}
+
+	if len(singleReplica) > 0 && !allowDowntime {
+		return nil, fmt.Errorf("tables with a single replica can only be moved with downtime, allow downtime to move them: %s", strings.Join(singleReplica, ", "))
+	}
+
+	return result, nil
+}
+
+// tableReplication returns the number of replicas of a table with type, pinot defaults to 1 when it is not set
+func (c *PinotAPIClient) tableReplication(tableNameWithType string) (int, error) {
+
+	tableName, tableType := splitTableNameWithType(tableNameWithType)
+
+	tables, err := c.GetTable(tableName)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get table %s: %w", tableName, err)
+	}
+
+	table := tables.OFFLINE
+	if tableType == "REALTIME" {
+		table = tables.REALTIME
+	}
+
+	replication := table.SegmentsConfig.Replication
+	if tableType == "REALTIME" && table.SegmentsConfig.ReplicasPerPartition != "" {
+		replication = table.SegmentsConfig.ReplicasPerPartition
+	}
+	if replication == "" {
+		return 1, nil
+	}
+
+	replicas, err := strconv.Atoi(replication)
+	if err != nil {
+		return 0, fmt.Errorf("table %s has invalid replication %s: %w", tableNameWithType, replication, err)
+	}
+
+	return replicas, nil
+}
+
+// tablesWithSegmentsOn returns the tables, with type, whose ideal state assigns segments to the instance or whose
+// external view still has segments on it, checking the external view of the given tables
+func (c *PinotAPIClient) tablesWithSegmentsOn(instanceName string, tables []string) ([]string, error) {
+
+	remaining, err := c.GetInstanceTableReferences(instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tableNameWithType := range tables {
+		if slices.Contains(remaining, tableNameWithType) {
+			continue
+		}
+
+		tableName, tableType := splitTableNameWithType(tableNameWithType)
+		externalView, err := c.GetTableExternalView(tableName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get external view of table %s: %w", tableName, err)
+		}
+
+		segments := externalView.Offline
+		if tableType == "REALTIME" {
+			segments = externalView.Realtime
+		}
+		if referencesInstance(segments, instanceName) {
+			remaining = append(remaining, tableNameWithType)
+		}
+	}
+
+	sort.Strings(remaining)
+	return remaining, nil
+}
+
+// SafeDeleteInstance deletes an instance only if no table ideal state still assigns segments to it
+func (c *PinotAPIClient) SafeDeleteInstance(instanceName string) (*model.UserActionResponse, error) {
+
+	references, err := c.GetInstanceTableReferences(instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(references) > 0 {
+		return nil, fmt.Errorf("instance %s is still referenced by the ideal state of tables: %s", instanceName, strings.Join(references, ", "))
+	}
+
+	return c.DeleteInstance(instanceName)
+}
@@ -0,0 +1,87 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInstanceTableReferences(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetInstanceTableReferences("Server_172.17.0.3_7050")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, []string{"test_OFFLINE"}, res, "Expected server to be referenced by test_OFFLINE")
+}
+
+func TestDrainServer(t *testing.T) {
+	controller := createMockController(t)
+
+	var rebalanceQuery url.Values
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controller.URL),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.Middlewares(func(next goPinotAPI.Handler) goPinotAPI.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				if strings.HasSuffix(req.URL.Path, "/rebalance") {
+					rebalanceQuery = req.URL.Query()
+				}
+				return next(req)
+			}
+		}),
+	)
+
+	res, err := client.DrainServer(context.Background(), "Server_172.17.0.3_7050", &goPinotAPI.DrainServerOptions{AllowDowntime: true, Wait: fastWaitOptions})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, []string{"DefaultTenant_OFFLINE", "DefaultTenant_REALTIME"}, res.PreviousTags, "Expected previous tags to be kept for rollback")
+	assert.Equal(t, []string{"test_OFFLINE"}, res.RebalancedTables, "Expected test_OFFLINE to be rebalanced")
+	assert.True(t, res.IsDrained(), "Expected server to no longer be referenced")
+	assert.Empty(t, res.RemainingTables, "Expected no tables to remain")
+	assert.Equal(t, "true", rebalanceQuery.Get("reassignInstances"), "Expected instances to be reassigned")
+	assert.Equal(t, "true", rebalanceQuery.Get("downtime"), "Expected a table with a single replica to be moved with downtime")
+	assert.Equal(t, "true", rebalanceQuery.Get("includeConsuming"), "Expected consuming segments to be moved")
+}
+
+func TestDrainServerSingleReplica(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.DrainServer(context.Background(), "Server_172.17.0.3_7050", &goPinotAPI.DrainServerOptions{Wait: fastWaitOptions})
+	assert.ErrorContains(t, err, "test_OFFLINE", "Expected a table with a single replica to need downtime")
+	assert.Nil(t, res, "Expected the server not to be untagged")
+}
+
+func TestDrainServerNotAServer(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.DrainServer(context.Background(), "Broker_cdba1ba98e74_8099", nil)
+	assert.Error(t, err, "Expected error when draining a broker")
+}
+
+func TestSafeDeleteInstance(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.SafeDeleteInstance("Server_172.17.0.3_7050")
+	assert.ErrorContains(t, err, "test_OFFLINE", "Expected error when deleting a referenced server")
+
+	res, err := client.SafeDeleteInstance("Server_172.17.0.4_7050")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully dropped instance", "Expected unreferenced server to be deleted")
+}
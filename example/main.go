@@ -521,11 +521,6 @@ func demoUserFunctionality(client *pinot.PinotAPIClient) {
 		Role:      "admin",
 	}
 
-	userBytes, err := json.Marshal(user)
-	if err != nil {
-		log.Panic(err)
-	}
-
 	updateUser := pinotModel.User{
 		Username:  "liam1",
 		Password:  "password",
@@ -541,7 +536,7 @@ func demoUserFunctionality(client *pinot.PinotAPIClient) {
 	fmt.Println("Creating User:")
 
 	// Create User
-	createResp, err := client.CreateUser(userBytes)
+	createResp, err := client.CreateUser(user)
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -1295,17 +1290,12 @@ func demoCreateUserWithACL(client *pinot.PinotAPIClient) {
 		Password:    "password",
 		Component:   "BROKER",
 		Role:        "admin",
-		Permissions: &[]string{"READ"},
-		Tables:      &[]string{"my_table_Offline"},
-	}
-
-	userBytes, err := json.Marshal(user)
-	if err != nil {
-		log.Panic(err)
+		Permissions: []pinotModel.Permission{pinotModel.PermissionRead},
+		Tables:      []string{"my_table_Offline"},
 	}
 
 	// Create User
-	createResp, err := client.CreateUser(userBytes)
+	createResp, err := client.CreateUser(user)
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -0,0 +1,70 @@
+// pinot-exporter serves the metrics of the exporter package for a single Pinot cluster
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/exporter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+
+	controllerUrl := flag.String("controller-url", envOrDefault("PINOT_CONTROLLER_URL", "http://localhost:9000"), "url of the pinot controller")
+	authToken := flag.String("auth-token", os.Getenv("PINOT_AUTH_TOKEN"), "token used to authenticate with the controller")
+	authType := flag.String("auth-type", envOrDefault("PINOT_AUTH_TYPE", "Basic"), "type of the auth token, Basic or Bearer")
+	listenAddress := flag.String("listen-address", ":9888", "address to serve metrics on")
+	metricsPath := flag.String("metrics-path", "/metrics", "path to serve metrics on")
+	cacheTTL := flag.Duration("cache-ttl", time.Minute, "how long metrics are cached between scrapes")
+	tables := flag.String("tables", "", "comma separated tables to export, all tables when empty")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	opts := []goPinotAPI.Opt{
+		goPinotAPI.ControllerUrl(*controllerUrl),
+		goPinotAPI.Logger(logger),
+	}
+	if *authToken != "" {
+		opts = append(opts, goPinotAPI.AuthToken(*authToken), goPinotAPI.AuthType(goPinotAPI.AuthScheme(*authType)))
+	}
+	client := goPinotAPI.NewPinotAPIClient(opts...)
+
+	collectorOpts := &exporter.Options{
+		CacheTTL: *cacheTTL,
+		Logger:   logger,
+	}
+	if *tables != "" {
+		collectorOpts.Tables = strings.Split(*tables, ",")
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		exporter.NewCollector(client, collectorOpts),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	http.Handle(*metricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	logger.Info("serving metrics", "address", *listenAddress, "path", *metricsPath, "controller", *controllerUrl)
+	if err := http.ListenAndServe(*listenAddress, nil); err != nil {
+		logger.Error("unable to serve metrics", "error", err)
+		os.Exit(1)
+	}
+}
+
+func envOrDefault(key string, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,332 @@
+// Package exporter exposes metadata about the tables and instances of a Pinot cluster as prometheus metrics.
+// It is a separate module so the client library does not depend on the prometheus client.
+package exporter
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultCacheTTL    = time.Minute
+	defaultConcurrency = 4
+	namespace          = "pinot"
+)
+
+// Options configures a Collector
+type Options struct {
+	// CacheTTL is how long metrics are served from cache before the controller is queried again, defaults to 1m
+	CacheTTL time.Duration
+	// Tables limits the tables exported, every table is exported when empty
+	Tables []string
+	// Concurrency is the number of tables fetched at once, defaults to 4
+	Concurrency int
+	// Logger logs tables and instances that could not be fetched, defaults to slog.Default
+	Logger *slog.Logger
+}
+
+// Collector is a prometheus.Collector for table sizes, metadata, indexes and health, and instance state.
+// Metrics are cached for Options.CacheTTL so concurrent and frequent scrapes do not each query the controller.
+type Collector struct {
+	client *goPinotAPI.PinotAPIClient
+	opts   Options
+
+	mu       sync.Mutex
+	cached   []prometheus.Metric
+	cachedAt time.Time
+	now      func() time.Time
+}
+
+var (
+	tableReportedSizeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "reported_size_bytes"),
+		"Size of the table reported by servers, across all replicas.",
+		[]string{"table"}, nil)
+	tableEstimatedSizeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "estimated_size_bytes"),
+		"Size of the table estimated from the servers that responded, across all replicas.",
+		[]string{"table"}, nil)
+	tableRowsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "rows"),
+		"Number of rows in the table.",
+		[]string{"table"}, nil)
+	tableSegmentsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "segments"),
+		"Number of segments in the table.",
+		[]string{"table"}, nil)
+	tableOnlineSegmentsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "online_segments"),
+		"Number of online segments in the table.",
+		[]string{"table"}, nil)
+	columnIndexSizeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "column_index_size_bytes"),
+		"Size of the indexes of a column.",
+		[]string{"table", "column"}, nil)
+	columnIndexSegmentsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "column_index_segments"),
+		"Number of segments with an index of the given type on a column.",
+		[]string{"table", "column", "index"}, nil)
+	tableHealthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "health_status"),
+		"Health of the table, 1 for the current status and 0 otherwise.",
+		[]string{"table", "status"}, nil)
+	tableMissingSegmentsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "missing_segments"),
+		"Number of segments in the ideal state but missing from the external view.",
+		[]string{"table"}, nil)
+	tableErrorReplicasDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "error_replicas"),
+		"Number of segment replicas in ERROR state.",
+		[]string{"table"}, nil)
+	tableUnderReplicatedSegmentsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "table", "under_replicated_segments"),
+		"Number of segments with fewer serving replicas than the table replication.",
+		[]string{"table"}, nil)
+	instanceEnabledDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "instance", "enabled"),
+		"Whether the instance is enabled, 1 if so.",
+		[]string{"instance", "type"}, nil)
+	scrapeErrorsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "exporter", "scrape_errors"),
+		"Number of requests to the controller that failed during the last scrape.",
+		nil, nil)
+	scrapeDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "exporter", "scrape_duration_seconds"),
+		"Time taken to query the controller during the last scrape.",
+		nil, nil)
+)
+
+var healthStatuses = []model.HealthStatus{model.HealthStatusHealthy, model.HealthStatusDegraded, model.HealthStatusUnhealthy}
+
+// NewCollector creates a collector that queries the controller through client
+func NewCollector(client *goPinotAPI.PinotAPIClient, opts *Options) *Collector {
+
+	var collectorOpts Options
+	if opts != nil {
+		collectorOpts = *opts
+	}
+	if collectorOpts.CacheTTL <= 0 {
+		collectorOpts.CacheTTL = defaultCacheTTL
+	}
+	if collectorOpts.Concurrency <= 0 {
+		collectorOpts.Concurrency = defaultConcurrency
+	}
+	if collectorOpts.Logger == nil {
+		collectorOpts.Logger = slog.Default()
+	}
+
+	return &Collector{
+		client: client,
+		opts:   collectorOpts,
+		now:    time.Now,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		tableReportedSizeDesc,
+		tableEstimatedSizeDesc,
+		tableRowsDesc,
+		tableSegmentsDesc,
+		tableOnlineSegmentsDesc,
+		columnIndexSizeDesc,
+		columnIndexSegmentsDesc,
+		tableHealthDesc,
+		tableMissingSegmentsDesc,
+		tableErrorReplicasDesc,
+		tableUnderReplicatedSegmentsDesc,
+		instanceEnabledDesc,
+		scrapeErrorsDesc,
+		scrapeDurationDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+
+	// holding the lock while scraping makes concurrent scrapes wait for and share a single refresh
+	c.mu.Lock()
+	if c.cached == nil || c.now().Sub(c.cachedAt) >= c.opts.CacheTTL {
+		c.cached = c.scrape()
+		c.cachedAt = c.now()
+	}
+	metrics := c.cached
+	c.mu.Unlock()
+
+	for _, metric := range metrics {
+		ch <- metric
+	}
+}
+
+// scrape queries the controller for every metric, tables and instances that fail are logged and left out
+func (c *Collector) scrape() []prometheus.Metric {
+
+	start := time.Now()
+	var metrics []prometheus.Metric
+	errors := 0
+
+	tables := c.opts.Tables
+	if len(tables) == 0 {
+		res, err := c.client.GetTables()
+		if err != nil {
+			c.opts.Logger.Error("unable to get tables", "error", err)
+			errors++
+		} else {
+			tables = res.Tables
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.opts.Concurrency)
+	for _, tableName := range tables {
+		wg.Add(1)
+		go func(tableName string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			tableMetrics, tableErrors := c.scrapeTable(tableName)
+
+			mu.Lock()
+			metrics = append(metrics, tableMetrics...)
+			errors += tableErrors
+			mu.Unlock()
+		}(tableName)
+	}
+	wg.Wait()
+
+	instanceMetrics, instanceErrors := c.scrapeInstances()
+	metrics = append(metrics, instanceMetrics...)
+	errors += instanceErrors
+
+	metrics = append(metrics,
+		prometheus.MustNewConstMetric(scrapeErrorsDesc, prometheus.GaugeValue, float64(errors)),
+		prometheus.MustNewConstMetric(scrapeDurationDesc, prometheus.GaugeValue, time.Since(start).Seconds()),
+	)
+
+	return metrics
+}
+
+func (c *Collector) scrapeTable(tableName string) ([]prometheus.Metric, int) {
+
+	var metrics []prometheus.Metric
+	errors := 0
+
+	gauge := func(desc *prometheus.Desc, value float64, labels ...string) {
+		metrics = append(metrics, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append([]string{tableName}, labels...)...))
+	}
+
+	if size, err := c.client.GetTableSize(tableName); err != nil {
+		c.opts.Logger.Error("unable to get table size", "table", tableName, "error", err)
+		errors++
+	} else {
+		gauge(tableReportedSizeDesc, float64(size.ReportedSizeInBytes))
+		gauge(tableEstimatedSizeDesc, float64(size.EstimatedSizeInBytes))
+	}
+
+	if metadata, err := c.client.GetTableMetadata(tableName); err != nil {
+		c.opts.Logger.Error("unable to get table metadata", "table", tableName, "error", err)
+		errors++
+	} else {
+		gauge(tableRowsDesc, float64(metadata.NumRows))
+		gauge(tableSegmentsDesc, float64(metadata.NumSegments))
+		for _, column := range sortedKeys(metadata.ColumnIndexSizeMap) {
+			gauge(columnIndexSizeDesc, float64(metadata.ColumnIndexSizeMap[column]), column)
+		}
+	}
+
+	if indexes, err := c.client.GetTableIndexes(tableName); err != nil {
+		c.opts.Logger.Error("unable to get table indexes", "table", tableName, "error", err)
+		errors++
+	} else {
+		gauge(tableOnlineSegmentsDesc, float64(indexes.TotalOnlineSegments))
+		for _, column := range sortedKeys(indexes.ColumnToIndexesCount) {
+			counts := indexCounts(indexes.ColumnToIndexesCount[column])
+			for _, index := range sortedKeys(counts) {
+				gauge(columnIndexSegmentsDesc, float64(counts[index]), column, index)
+			}
+		}
+	}
+
+	if health, err := c.client.CheckTableHealth(tableName); err != nil {
+		c.opts.Logger.Error("unable to check table health", "table", tableName, "error", err)
+		errors++
+	} else {
+		for _, status := range healthStatuses {
+			value := 0.0
+			if health.Status == status {
+				value = 1
+			}
+			gauge(tableHealthDesc, value, string(status))
+		}
+		gauge(tableMissingSegmentsDesc, float64(len(health.MissingSegments)))
+		gauge(tableErrorReplicasDesc, float64(len(health.ErrorReplicas)))
+		gauge(tableUnderReplicatedSegmentsDesc, float64(len(health.UnderReplicatedSegments)))
+	}
+
+	return metrics, errors
+}
+
+func (c *Collector) scrapeInstances() ([]prometheus.Metric, int) {
+
+	instances, err := c.client.GetInstances()
+	if err != nil {
+		c.opts.Logger.Error("unable to get instances", "error", err)
+		return nil, 1
+	}
+
+	var metrics []prometheus.Metric
+	errors := 0
+
+	for _, instanceName := range instances.Instances {
+		instance, err := c.client.GetInstance(instanceName)
+		if err != nil {
+			c.opts.Logger.Error("unable to get instance", "instance", instanceName, "error", err)
+			errors++
+			continue
+		}
+
+		enabled := 0.0
+		if instance.Enabled {
+			enabled = 1
+		}
+		metrics = append(metrics, prometheus.MustNewConstMetric(instanceEnabledDesc, prometheus.GaugeValue, enabled,
+			instanceName, string(model.InstanceTypeOf(instanceName))))
+	}
+
+	return metrics, errors
+}
+
+// indexCounts maps the index counts of a column to the index type names pinot uses
+func indexCounts(index model.ColumnToIndex) map[string]int {
+	return map[string]int{
+		"vector_index":     index.VectorIndex,
+		"nullvalue_vector": index.NullValueVector,
+		"h3_index":         index.H3Index,
+		"dictionary":       index.Dictionary,
+		"json_index":       index.JsonIndex,
+		"range_index":      index.RangeIndex,
+		"forward_index":    index.ForwardIndex,
+		"bloom_filter":     index.BloomFilter,
+		"inverted_index":   index.InvertedIndex,
+		"text_index":       index.TextIndex,
+		"fst_index":        index.FSTIndex,
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
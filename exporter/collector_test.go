@@ -0,0 +1,128 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockControllerServer(requests *atomic.Int32) *httptest.Server {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tables", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"tables": ["test"]}`)
+	})
+	mux.HandleFunc("/tables/test", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"OFFLINE": {"tableName": "test_OFFLINE", "tableType": "OFFLINE", "segmentsConfig": {"replication": "2"}}}`)
+	})
+	mux.HandleFunc("/tables/test/size", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tableName": "test", "reportedSizeInBytes": 4723495, "estimatedSizeInBytes": 4723495}`)
+	})
+	mux.HandleFunc("/tables/test/metadata", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tableName": "test_OFFLINE", "diskSizeInBytes": 4723495, "numSegments": 2, "numRows": 1000, "columnIndexSizeMap": {"Origin": 2048}}`)
+	})
+	mux.HandleFunc("/tables/test/indexes", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"totalOnlineSegments": 2, "columnToIndexesCount": {"Origin": {"dictionary": 2, "forward_index": 2, "inverted_index": 1}}}`)
+	})
+	mux.HandleFunc("/tables/test/idealstate", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"OFFLINE": {
+			"test_OFFLINE_0": {"Server_172.17.0.3_7050": "ONLINE", "Server_172.17.0.4_7050": "ONLINE"},
+			"test_OFFLINE_1": {"Server_172.17.0.3_7050": "ONLINE", "Server_172.17.0.4_7050": "ONLINE"}
+		}, "REALTIME": null}`)
+	})
+	mux.HandleFunc("/tables/test/externalview", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"OFFLINE": {
+			"test_OFFLINE_0": {"Server_172.17.0.3_7050": "ONLINE", "Server_172.17.0.4_7050": "ONLINE"},
+			"test_OFFLINE_1": {"Server_172.17.0.3_7050": "ONLINE", "Server_172.17.0.4_7050": "ERROR"}
+		}, "REALTIME": null}`)
+	})
+	mux.HandleFunc("/instances", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"instances": ["Server_172.17.0.3_7050", "Server_172.17.0.4_7050"]}`)
+	})
+	mux.HandleFunc("/instances/", func(w http.ResponseWriter, r *http.Request) {
+		instanceName := strings.TrimPrefix(r.URL.Path, "/instances/")
+		fmt.Fprintf(w, `{"instanceName": "%s", "enabled": %t}`, instanceName, instanceName != "Server_172.17.0.4_7050")
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestCollector(t *testing.T) {
+	var requests atomic.Int32
+	server := createMockControllerServer(&requests)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(server.URL))
+	collector := NewCollector(client, nil)
+
+	expected := `
+# HELP pinot_table_rows Number of rows in the table.
+# TYPE pinot_table_rows gauge
+pinot_table_rows{table="test"} 1000
+# HELP pinot_table_column_index_segments Number of segments with an index of the given type on a column.
+# TYPE pinot_table_column_index_segments gauge
+pinot_table_column_index_segments{column="Origin",index="bloom_filter",table="test"} 0
+pinot_table_column_index_segments{column="Origin",index="dictionary",table="test"} 2
+pinot_table_column_index_segments{column="Origin",index="forward_index",table="test"} 2
+pinot_table_column_index_segments{column="Origin",index="fst_index",table="test"} 0
+pinot_table_column_index_segments{column="Origin",index="h3_index",table="test"} 0
+pinot_table_column_index_segments{column="Origin",index="inverted_index",table="test"} 1
+pinot_table_column_index_segments{column="Origin",index="json_index",table="test"} 0
+pinot_table_column_index_segments{column="Origin",index="nullvalue_vector",table="test"} 0
+pinot_table_column_index_segments{column="Origin",index="range_index",table="test"} 0
+pinot_table_column_index_segments{column="Origin",index="text_index",table="test"} 0
+pinot_table_column_index_segments{column="Origin",index="vector_index",table="test"} 0
+# HELP pinot_table_health_status Health of the table, 1 for the current status and 0 otherwise.
+# TYPE pinot_table_health_status gauge
+pinot_table_health_status{status="DEGRADED",table="test"} 1
+pinot_table_health_status{status="HEALTHY",table="test"} 0
+pinot_table_health_status{status="UNHEALTHY",table="test"} 0
+# HELP pinot_instance_enabled Whether the instance is enabled, 1 if so.
+# TYPE pinot_instance_enabled gauge
+pinot_instance_enabled{instance="Server_172.17.0.3_7050",type="SERVER"} 1
+pinot_instance_enabled{instance="Server_172.17.0.4_7050",type="SERVER"} 0
+# HELP pinot_exporter_scrape_errors Number of requests to the controller that failed during the last scrape.
+# TYPE pinot_exporter_scrape_errors gauge
+pinot_exporter_scrape_errors 0
+`
+
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"pinot_table_rows",
+		"pinot_table_column_index_segments",
+		"pinot_table_health_status",
+		"pinot_instance_enabled",
+		"pinot_exporter_scrape_errors",
+	)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestCollectorCache(t *testing.T) {
+	var requests atomic.Int32
+	server := createMockControllerServer(&requests)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(server.URL))
+	collector := NewCollector(client, &Options{CacheTTL: time.Minute})
+
+	now := time.Now()
+	collector.now = func() time.Time { return now }
+
+	testutil.CollectAndCount(collector)
+	testutil.CollectAndCount(collector)
+	assert.Equal(t, int32(1), requests.Load(), "Expected second scrape to be served from cache")
+
+	now = now.Add(time.Minute)
+	testutil.CollectAndCount(collector)
+	assert.Equal(t, int32(2), requests.Load(), "Expected scrape after the cache ttl to query the controller")
+}
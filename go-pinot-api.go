@@ -2,7 +2,9 @@ package goPinotAPI
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,15 +14,23 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/azaurus1/go-pinot-api/model"
 )
 
+const userAgent = "go-pinot-api"
+
 type PinotAPIClient struct {
 	pinotControllerUrl *url.URL
 	pinotHttp          *pinotHttp
 	Host               string
 	log                *slog.Logger
+	instrumentation    Instrumentation
+	controllers        *controllerPool
+	ctx                context.Context
+	operation          string
+	cache              *responseCache
 }
 
 func NewPinotAPIClient(opts ...Opt) *PinotAPIClient {
@@ -30,313 +40,262 @@ func NewPinotAPIClient(opts ...Opt) *PinotAPIClient {
 		log.Panic(err)
 	}
 
+	// a single controller is used directly, without failover
+	var controllers *controllerPool
+	if len(clientCfg.controllers) > 1 {
+		controllers = newControllerPool(pinotControllerUrl, clientCfg.controllers, clientCfg.failover)
+	}
+
+	var cache *responseCache
+	if clientCfg.cache != nil {
+		cache = newResponseCache(*clientCfg.cache)
+	}
+
 	return &PinotAPIClient{
 		pinotControllerUrl: pinotControllerUrl,
 		pinotHttp: &pinotHttp{
-			httpClient:         &http.Client{},
+			httpClient:         &http.Client{Transport: clientCfg.transport},
 			pinotControllerUrl: pinotControllerUrl,
-			httpAuthWriter:     clientCfg.httpAuthWriter,
+			credentials:        clientCfg.credentials,
+			middlewares:        clientCfg.middlewares,
+			readLimiter:        newLimiter(clientCfg.rateLimit.Reads),
+			mutationLimiter:    newLimiter(clientCfg.rateLimit.Mutations),
 		},
-		Host: pinotControllerUrl.Hostname(),
-		log:  clientCfg.logger,
+		Host:            pinotControllerUrl.Hostname(),
+		log:             clientCfg.logger,
+		instrumentation: clientCfg.instrumentation,
+		controllers:     controllers,
+		cache:           cache,
 	}
 }
 
-func (c *PinotAPIClient) FetchData(endpoint string, result any) error {
+// WithContext returns a copy of the client whose requests use ctx, so waiting for rate limits and
+// sending requests stop when ctx is done. The copy shares the connections and limits of the client
+func (c *PinotAPIClient) WithContext(ctx context.Context) *PinotAPIClient {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
 
-	fullURL := prepareRequestURL(c, endpoint)
+// named returns a copy of the client whose requests are reported to the instrumentation as the
+// operation name, the client method making them
+func (c *PinotAPIClient) named(name string) *PinotAPIClient {
+	copied := *c
+	copied.operation = name
+	return &copied
+}
 
-	request, err := http.NewRequest(http.MethodGet, fullURL.String(), nil)
-	if err != nil {
-		return fmt.Errorf("client: could not create request: %w", err)
+// operationName is the operation requests are reported as, pipeline is the exported pipeline method
+// sending them for requests made outside a named client method
+func (c *PinotAPIClient) operationName(pipeline string) string {
+	if c.operation == "" {
+		return pipeline
 	}
-
-	c.log.Debug(fmt.Sprintf("attempting GET %s", fullURL))
-
-	resp, err := c.pinotHttp.Do(request)
-	if err != nil {
-		c.logErrorResp(resp)
-		return fmt.Errorf("client: could not send request: %w", err)
+	return c.operation
+}
+
+// RequestError is returned when the controller responds with a status outside 2xx
+type RequestError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *RequestError) Error() string {
+	var errMsg string
+	switch e.StatusCode {
+	// From client perspective, 409 isnt a failed request
+	case http.StatusConflict:
+		errMsg = "client: conflict, object exists - "
+	case http.StatusForbidden:
+		errMsg = "client: forbidden - "
+	case http.StatusNotFound:
+		errMsg = "client: object can not be found - "
+	default:
+		errMsg = "client: "
 	}
 
-	defer resp.Body.Close()
+	return fmt.Sprintf("%srequest failed: status %d\n%s", errMsg, e.StatusCode, e.Body)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		// extract body contents to add to error message
-		bodyContents, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("client: request failed with status code: %d", resp.StatusCode)
-		}
-		return fmt.Errorf("client: request failed with status code: %d, body: %s", resp.StatusCode, string(bodyContents))
-	}
+// do is the pipeline every call to the controller goes through. It applies auth and headers, logs
+// the call and turns responses outside 2xx into a *RequestError. operation names the call for the
+// instrumentation. The caller closes the response body
+func (c *PinotAPIClient) do(operation string, method string, fullURL *url.URL, body []byte, contentType string) (*http.Response, error) {
 
-	bodyContents, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("client: could not read response body: %w", err)
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
 	}
 
-	err = json.NewDecoder(bytes.NewReader(bodyContents)).Decode(result)
-	if err != nil {
-		c.log.Debug(fmt.Sprintf("unable to decode response from successful request: %s", err))
-		return fmt.Errorf("client: could not unmarshal response JSON: %w\n%s", err, string(bodyContents))
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	return nil
-}
-
-func (c *PinotAPIClient) FetchPlainText(endpoint string, result *model.PlainTextAPIResponse) error {
-
-	fullURL := prepareRequestURL(c, endpoint)
-
-	request, err := http.NewRequest(http.MethodGet, fullURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, method, fullURL.String(), bodyReader)
 	if err != nil {
-		return fmt.Errorf("client: could not create request: %w", err)
+		return nil, fmt.Errorf("client: could not create request: %w", err)
 	}
 
-	c.log.Debug(fmt.Sprintf("attempting GET %s", fullURL))
-
-	resp, err := c.pinotHttp.Do(request)
-	if err != nil {
-		c.logErrorResp(resp)
-		return fmt.Errorf("client: could not send request: %w", err)
+	req.Header.Set("User-Agent", userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
-	defer resp.Body.Close()
+	c.log.Debug(fmt.Sprintf("attempting %s %s", method, fullURL))
 
-	if resp.StatusCode != http.StatusOK {
-		// extract body contents to add to error message
-		bodyContents, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("client: request failed with status code: %d", resp.StatusCode)
-		}
-		return fmt.Errorf("client: request failed with status code: %d, body: %s", resp.StatusCode, string(bodyContents))
-	}
-
-	bodyContents, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("client: could not read response body: %w", err)
+	if c.instrumentation != nil {
+		return c.doInstrumented(operation, req)
 	}
 
-	c.log.Debug(fmt.Sprintf("response from successful request: %s", string(bodyContents)))
-
-	result.Response = string(bodyContents)
-
-	return nil
-
+	return c.roundTrip(req)
 }
 
-func (c *PinotAPIClient) CreateObject(endpoint string, body []byte, result any) error {
+// doInstrumented reports a request to the client instrumentation
+func (c *PinotAPIClient) doInstrumented(operation string, req *http.Request) (*http.Response, error) {
 
-	fullURL := prepareRequestURL(c, endpoint)
+	start := time.Now()
+	retries := 0
 
-	var req *http.Request
-	var err error
+	ctx := context.WithValue(req.Context(), retriesKey{}, &retries)
+	ctx, end := c.instrumentation.Start(ctx, newOperation(operation, req), req.Header)
 
-	if body == nil {
-		c.log.Debug("body is nil")
-		req, err = http.NewRequest(http.MethodPost, fullURL.String(), nil)
-		req.Header.Set("Content-Type", "application/json")
-	} else {
-		req, err = http.NewRequest(http.MethodPost, fullURL.String(), bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
-	}
+	res, err := c.roundTrip(req.WithContext(ctx))
 
-	if err != nil {
-		return fmt.Errorf("client: could not create request: %w", err)
+	result := OperationResult{Retries: retries, Duration: time.Since(start), Err: err}
+	var reqErr *RequestError
+	if res != nil {
+		result.StatusCode = res.StatusCode
+	} else if errors.As(err, &reqErr) {
+		result.StatusCode = reqErr.StatusCode
 	}
+	end(result)
 
-	c.log.Debug(fmt.Sprintf("attempting POST %s", fullURL))
+	return res, err
+}
+
+// roundTrip sends a request and turns responses outside 2xx into a *RequestError
+func (c *PinotAPIClient) roundTrip(req *http.Request) (*http.Response, error) {
+
+	method, fullURL := req.Method, req.URL
 
-	res, err := c.pinotHttp.Do(req)
+	res, err := c.sendCached(req)
 	if err != nil {
-		c.logErrorResp(res)
-		return fmt.Errorf("client: could not send request: %w", err)
+		return nil, fmt.Errorf("client: could not send request: %w", err)
 	}
 
-	// Check the status code
-	if res.StatusCode != http.StatusOK {
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		defer res.Body.Close()
 
-		errRespMessage, err := c.extractErrorMessage(res)
+		bodyContents, err := io.ReadAll(res.Body)
 		if err != nil {
-			return fmt.Errorf("client: could not extract error message: %w", err)
+			return nil, fmt.Errorf("client: request failed with status code %d, could not read response body: %w", res.StatusCode, err)
 		}
 
-		var errMsg string
+		c.log.Debug(fmt.Sprintf("response from failed request: %s", string(bodyContents)))
 
-		// From client perspective, 409 isnt a failed request
-		if res.StatusCode == 409 {
-			errMsg = "client: conflict, object exists - "
-		} else if res.StatusCode == 403 {
-			errMsg = "client: forbidden - "
-		} else {
-			errMsg = "client: "
+		return nil, &RequestError{
+			Method:     method,
+			URL:        fullURL.String(),
+			StatusCode: res.StatusCode,
+			Body:       string(bodyContents),
 		}
-
-		return fmt.Errorf("%srequest failed: status %d\n%s", errMsg, res.StatusCode, errRespMessage)
-	}
-
-	err = json.NewDecoder(res.Body).Decode(&result)
-	if err != nil {
-		return fmt.Errorf("client: could not unmarshal JSON: %w", err)
 	}
 
-	return nil
+	return res, nil
 }
 
-func (c *PinotAPIClient) CreateFormDataObject(endpoint string, body []byte, result any) error {
+// doJSON sends a request through the pipeline and decodes the JSON response into result
+func (c *PinotAPIClient) doJSON(operation string, method string, fullURL *url.URL, body []byte, contentType string, result any) error {
 
-	fullURL := c.pinotControllerUrl.JoinPath(endpoint).String()
-
-	req, err := http.NewRequest(http.MethodPost, fullURL, bytes.NewBuffer(body))
+	res, err := c.do(operation, method, fullURL, body, contentType)
 	if err != nil {
-		return fmt.Errorf("client: could not create request: %w", err)
+		return err
 	}
+	defer res.Body.Close()
 
-	req.Header.Set("Content-Type", "multipart/form-data")
-
-	res, err := c.pinotHttp.Do(req)
+	bodyContents, err := io.ReadAll(res.Body)
 	if err != nil {
-		c.logErrorResp(res)
-		return fmt.Errorf("client: could not send request: %w", err)
-	}
-
-	// Check the status code
-	if res.StatusCode != http.StatusOK {
-		var errMsg string
-		// From client perspective, 409 isnt a failed request
-		if res.StatusCode == 409 {
-			errMsg = "client: conflict, object exists - "
-		} else if res.StatusCode == 403 {
-			errMsg = "client: forbidden - "
-		} else {
-			errMsg = "client: "
-		}
-		return fmt.Errorf("%srequest failed with status code: %d", errMsg, res.StatusCode)
+		return fmt.Errorf("client: could not read response body: %w", err)
 	}
 
-	err = json.NewDecoder(res.Body).Decode(&result)
+	err = json.NewDecoder(bytes.NewReader(bodyContents)).Decode(result)
 	if err != nil {
-		return fmt.Errorf("client: could not unmarshal JSON: %w", err)
+		c.log.Debug(fmt.Sprintf("unable to decode response from successful request: %s", err))
+		return fmt.Errorf("client: could not unmarshal response JSON: %w\n%s", err, string(bodyContents))
 	}
 
 	return nil
 }
 
-func (c *PinotAPIClient) DeleteObject(endpoint string, queryParams map[string]string, result any) error {
-
-	fullURL := prepareRequestURL(c, endpoint)
+func (c *PinotAPIClient) FetchData(endpoint string, result any) error {
+	return c.doJSON(c.operationName("FetchData"), http.MethodGet, prepareRequestURL(c, endpoint), nil, "", result)
+}
 
-	c.encodeParams(fullURL, queryParams)
+func (c *PinotAPIClient) FetchPlainText(endpoint string, result *model.PlainTextAPIResponse) error {
 
-	request, err := http.NewRequest(http.MethodDelete, fullURL.String(), nil)
+	res, err := c.do(c.operationName("FetchPlainText"), http.MethodGet, prepareRequestURL(c, endpoint), nil, "")
 	if err != nil {
-		return fmt.Errorf("client: could not create request: %w", err)
+		return err
 	}
+	defer res.Body.Close()
 
-	c.log.Debug(fmt.Sprintf("attempting DELETE %s", fullURL.String()))
-
-	res, err := c.pinotHttp.Do(request)
+	bodyContents, err := io.ReadAll(res.Body)
 	if err != nil {
-		c.logErrorResp(res)
-		return fmt.Errorf("client: could not send request: %w", err)
+		return fmt.Errorf("client: could not read response body: %w", err)
 	}
 
-	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
-
-		errRespMessage, err := c.extractErrorMessage(res)
-		if err != nil {
-			return fmt.Errorf("client: could not extract error message: %w", err)
-		}
-
-		var errMsg string
-		// From client perspective, 409 isnt a failed request
-		switch res.StatusCode {
-		case http.StatusNotFound:
-			errMsg = "client: object can not be found - "
-		default:
-			errMsg = "client: "
-		}
+	c.log.Debug(fmt.Sprintf("response from successful request: %s", string(bodyContents)))
 
-		return fmt.Errorf("%srequest failed: status %d\n%s", errMsg, res.StatusCode, errRespMessage)
+	result.Response = string(bodyContents)
 
-	}
+	return nil
 
-	err = json.NewDecoder(res.Body).Decode(&result)
-	if err != nil {
-		return fmt.Errorf("client: could not unmarshal JSON: %w", err)
-	}
+}
 
-	return nil
+func (c *PinotAPIClient) CreateObject(endpoint string, body []byte, result any) error {
+	return c.doJSON(c.operationName("CreateObject"), http.MethodPost, prepareRequestURL(c, endpoint), body, "application/json", result)
 }
 
-func (c *PinotAPIClient) UpdateObject(endpoint string, queryParams map[string]string, body []byte, result any) error {
+func (c *PinotAPIClient) CreateFormDataObject(endpoint string, body []byte, result any) error {
+	return c.doJSON(c.operationName("CreateFormDataObject"), http.MethodPost, c.pinotControllerUrl.JoinPath(endpoint), body, "multipart/form-data", result)
+}
 
-	fullURL := c.pinotControllerUrl.JoinPath(endpoint)
+func (c *PinotAPIClient) DeleteObject(endpoint string, queryParams map[string]string, result any) error {
 
+	fullURL := prepareRequestURL(c, endpoint)
 	c.encodeParams(fullURL, queryParams)
 
-	var req *http.Request
-	var err error
-
-	if body == nil {
-		c.log.Debug("body is nil")
-		req, err = http.NewRequest(http.MethodPut, fullURL.String(), nil)
-	} else {
-		req, err = http.NewRequest(http.MethodPut, fullURL.String(), bytes.NewBuffer(body))
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	if err != nil {
-		return fmt.Errorf("client: could not create request: %w", err)
-	}
-
-	c.log.Debug(fmt.Sprintf("attempting PUT %s", fullURL.String()))
-
-	res, err := c.pinotHttp.Do(req)
-	if err != nil {
-		c.logErrorResp(res)
-		return fmt.Errorf("client: could not send request: %w", err)
-	}
-
-	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
-
-		errRespMessage, err := c.extractErrorMessage(res)
-		if err != nil {
-			return fmt.Errorf("client: could not extract error message: %w", err)
-		}
+	return c.doJSON(c.operationName("DeleteObject"), http.MethodDelete, fullURL, nil, "", result)
+}
 
-		var errMsg string
-		// From client perspective, 409 isnt a failed request
-		if res.StatusCode == 404 {
-			errMsg = "client: object can not be found - "
-		} else {
-			errMsg = "client: "
-		}
+func (c *PinotAPIClient) UpdateObject(endpoint string, queryParams map[string]string, body []byte, result any) error {
 
-		return fmt.Errorf("%srequest failed: status %d\n%s", errMsg, res.StatusCode, errRespMessage)
-	}
+	fullURL := c.pinotControllerUrl.JoinPath(endpoint)
+	c.encodeParams(fullURL, queryParams)
 
-	err = json.NewDecoder(res.Body).Decode(&result)
-	if err != nil {
-		return fmt.Errorf("client: could not unmarshal JSON: %w", err)
+	var contentType string
+	if body != nil {
+		contentType = "application/json"
 	}
 
-	return nil
-
+	return c.doJSON(c.operationName("UpdateObject"), http.MethodPut, fullURL, body, contentType, result)
 }
 
 func (c *PinotAPIClient) GetUsers() (*model.GetUsersResponse, error) {
 	var result model.GetUsersResponse
-	err := c.FetchData("/users", &result)
+	err := c.named("GetUsers").FetchData("/users", &result)
 	return &result, err
 }
 
-func (c *PinotAPIClient) GetUser(username string, component string) (*model.User, error) {
+func (c *PinotAPIClient) GetUser(username string, component model.UserComponent) (*model.User, error) {
 	var result map[string]model.User
 	var resultUser model.User
 
 	endpoint := fmt.Sprintf("/users/%s?component=%s", username, component)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetUser").FetchData(endpoint, &result)
 
 	usernameWithComponent := fmt.Sprintf("%s_%s", username, component)
 
@@ -344,46 +303,52 @@ func (c *PinotAPIClient) GetUser(username string, component string) (*model.User
 	return &resultUser, err
 }
 
-func (c *PinotAPIClient) CreateUser(body []byte) (*model.UserActionResponse, error) {
+func (c *PinotAPIClient) CreateUser(user model.User) (*model.UserActionResponse, error) {
+
+	userBytes, err := json.Marshal(user)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal user %s: %w", user.Username, err)
+	}
+
 	var result model.UserActionResponse
-	err := c.CreateObject("/users", body, &result)
+	err = c.named("CreateUser").CreateObject("/users", userBytes, &result)
 	return &result, err
 }
 
-func (c *PinotAPIClient) DeleteUser(username string, component string) (*model.UserActionResponse, error) {
+func (c *PinotAPIClient) DeleteUser(username string, component model.UserComponent) (*model.UserActionResponse, error) {
 	deletionQueryParams := make(map[string]string)
-	deletionQueryParams["component"] = component
+	deletionQueryParams["component"] = string(component)
 
 	endpoint := fmt.Sprintf("/users/%s", username)
 
 	var result model.UserActionResponse
-	err := c.DeleteObject(endpoint, deletionQueryParams, &result)
+	err := c.named("DeleteUser").DeleteObject(endpoint, deletionQueryParams, &result)
 	return &result, err
 }
 
-func (c *PinotAPIClient) UpdateUser(username string, component string, passwordChanged bool, body []byte) (*model.UserActionResponse, error) {
+func (c *PinotAPIClient) UpdateUser(username string, component model.UserComponent, passwordChanged bool, body []byte) (*model.UserActionResponse, error) {
 	updateQueryParams := make(map[string]string)
-	updateQueryParams["component"] = component
+	updateQueryParams["component"] = string(component)
 	updateQueryParams["passwordChanged"] = strconv.FormatBool(passwordChanged)
 
 	var result model.UserActionResponse
 	endpoint := fmt.Sprintf("/users/%s", username)
 
-	err := c.UpdateObject(endpoint, updateQueryParams, body, &result)
+	err := c.named("UpdateUser").UpdateObject(endpoint, updateQueryParams, body, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTables() (*model.GetTablesResponse, error) {
 	var result model.GetTablesResponse
 
-	err := c.FetchData("/tables", &result)
+	err := c.named("GetTables").FetchData("/tables", &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTable(tableName string) (*model.GetTableResponse, error) {
 	var result model.GetTableResponse
 	endpoint := fmt.Sprintf("/tables/%s", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTable").FetchData(endpoint, &result)
 	return &result, err
 }
 
@@ -395,21 +360,21 @@ func (c *PinotAPIClient) GetTable(tableName string) (*model.GetTableResponse, er
 
 func (c *PinotAPIClient) CreateTable(body []byte) (*model.CreateTablesResponse, error) {
 	result := &model.CreateTablesResponse{}
-	err := c.CreateObject("/tables", body, result)
+	err := c.named("CreateTable").CreateObject("/tables", body, result)
 	return result, err
 }
 
 func (c *PinotAPIClient) UpdateTable(tableName string, body []byte) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
 	endpoint := fmt.Sprintf("/tables/%s", tableName)
-	err := c.UpdateObject(endpoint, nil, body, &result)
+	err := c.named("UpdateTable").UpdateObject(endpoint, nil, body, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) DeleteTable(tableName string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
 	endpoint := fmt.Sprintf("/tables/%s", tableName)
-	err := c.DeleteObject(endpoint, nil, &result)
+	err := c.named("DeleteTable").DeleteObject(endpoint, nil, &result)
 	return &result, err
 }
 
@@ -439,76 +404,76 @@ func (c *PinotAPIClient) CreateTableFromFile(tableConfigFile string) (*model.Cre
 func (c *PinotAPIClient) GetTableExternalView(tableName string) (*model.GetTableExternalViewResponse, error) {
 	var result model.GetTableExternalViewResponse
 	endpoint := fmt.Sprintf("/tables/%s/externalview", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableExternalView").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableIdealState(tableName string) (*model.GetTableIdealStateResponse, error) {
 	var result model.GetTableIdealStateResponse
 	endpoint := fmt.Sprintf("/tables/%s/idealstate", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableIdealState").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableIndexes(tableName string) (*model.GetTableIndexesResponse, error) {
 	var result model.GetTableIndexesResponse
 	endpoint := fmt.Sprintf("/tables/%s/indexes", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableIndexes").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableInstances(tableName string) (*model.GetTableInstancesResponse, error) {
 	var result model.GetTableInstancesResponse
 	endpoint := fmt.Sprintf("/tables/%s/instances", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableInstances").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetAllTableLiveBrokers() (*model.GetLiveBrokersResponse, error) {
 	var result model.GetLiveBrokersResponse
-	err := c.FetchData("/tables/livebrokers", &result)
+	err := c.named("GetAllTableLiveBrokers").FetchData("/tables/livebrokers", &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableLiveBrokers(tableName string) (*[]string, error) {
 	var result []string
 	endpoint := fmt.Sprintf("/tables/%s/livebrokers", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableLiveBrokers").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableMetadata(tableName string) (*model.GetTableMetadataResponse, error) {
 	var result model.GetTableMetadataResponse
 	endpoint := fmt.Sprintf("/tables/%s/metadata", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableMetadata").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) RebuildBrokerResourceFromHelixTags(tableName string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
 	endpoint := fmt.Sprintf("/tables/%s/rebuildBrokerResourceFromHelixTags", tableName)
-	err := c.CreateObject(endpoint, nil, &result)
+	err := c.named("RebuildBrokerResourceFromHelixTags").CreateObject(endpoint, nil, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableSchema(tableName string) (*model.Schema, error) {
 	var result model.Schema
 	endpoint := fmt.Sprintf("/tables/%s/schema", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableSchema").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableSize(tableName string) (*model.GetTableSizeResponse, error) {
 	var result model.GetTableSizeResponse
 	endpoint := fmt.Sprintf("/tables/%s/size", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableSize").FetchData(endpoint, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableState(tableName string, tableType string) (*model.GetTableStateResponse, error) {
 	var result model.GetTableStateResponse
 	endpoint := fmt.Sprintf("/tables/%s/state?type=%s", tableName, tableType)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableState").FetchData(endpoint, &result)
 	return &result, err
 }
 
@@ -520,28 +485,114 @@ func (c *PinotAPIClient) ChangeTableState(tableName string, tableType string, st
 
 	endpoint := fmt.Sprintf("/tables/%s/state", tableName)
 
-	err := c.UpdateObject(endpoint, queryParams, nil, &result)
+	err := c.named("ChangeTableState").UpdateObject(endpoint, queryParams, nil, &result)
+	return &result, err
+}
+
+// RebalanceTable rebalances the segments of a table across its servers, use options.DryRun to
+// preview the instance and segment assignment without applying it
+func (c *PinotAPIClient) RebalanceTable(tableName string, tableType string, options model.RebalanceTableOptions) (*model.RebalanceResult, error) {
+	queryParams := options.QueryParams()
+	queryParams["type"] = tableType
+
+	var result model.RebalanceResult
+	endpoint := withQueryParams(fmt.Sprintf("/tables/%s/rebalance", tableName), queryParams)
+	err := c.named("RebalanceTable").CreateObject(endpoint, nil, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetTableRebalanceStatus(jobId string) (*model.GetTableRebalanceStatusResponse, error) {
+	var result model.GetTableRebalanceStatusResponse
+	err := c.named("GetTableRebalanceStatus").FetchData(fmt.Sprintf("/rebalanceStatus/%s", jobId), &result)
+	return &result, err
+}
+
+// CancelTableRebalance cancels any running rebalance of the table and returns the ids of the cancelled jobs
+func (c *PinotAPIClient) CancelTableRebalance(tableName string, tableType string) (*[]string, error) {
+	queryParams := make(map[string]string)
+	queryParams["type"] = tableType
+
+	var result []string
+	err := c.named("CancelTableRebalance").DeleteObject(fmt.Sprintf("/tables/%s/rebalance", tableName), queryParams, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTableStats(tableName string) (*model.GetTableStatsResponse, error) {
 	var result model.GetTableStatsResponse
 	endpoint := fmt.Sprintf("/tables/%s/stats", tableName)
-	err := c.FetchData(endpoint, &result)
+	err := c.named("GetTableStats").FetchData(endpoint, &result)
+	return &result, err
+}
+
+// PauseConsumption stops a realtime table consuming from its stream, the consuming segments are committed first
+func (c *PinotAPIClient) PauseConsumption(tableName string, comment string) (*model.PauseStatus, error) {
+	queryParams := make(map[string]string)
+	if comment != "" {
+		queryParams["comment"] = comment
+	}
+
+	var result model.PauseStatus
+	endpoint := withQueryParams(fmt.Sprintf("/tables/%s/pauseConsumption", tableName), queryParams)
+	err := c.named("PauseConsumption").CreateObject(endpoint, nil, &result)
+	return &result, err
+}
+
+// ResumeConsumption resumes consumption of a paused realtime table, consumeFrom is either model.ConsumeFromSmallest
+// or model.ConsumeFromLargest to choose the offset new consuming segments start from, leave it empty to continue
+// from the last committed offset
+func (c *PinotAPIClient) ResumeConsumption(tableName string, consumeFrom string) (*model.PauseStatus, error) {
+	queryParams := make(map[string]string)
+	if consumeFrom != "" {
+		queryParams["consumeFrom"] = consumeFrom
+	}
+
+	var result model.PauseStatus
+	endpoint := withQueryParams(fmt.Sprintf("/tables/%s/resumeConsumption", tableName), queryParams)
+	err := c.named("ResumeConsumption").CreateObject(endpoint, nil, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetPauseStatus(tableName string) (*model.PauseStatus, error) {
+	var result model.PauseStatus
+	endpoint := fmt.Sprintf("/tables/%s/pauseStatus", tableName)
+	err := c.named("GetPauseStatus").FetchData(endpoint, &result)
+	return &result, err
+}
+
+// ForceCommit commits the consuming segments of a realtime table, the returned job id can be passed to GetForceCommitStatus
+func (c *PinotAPIClient) ForceCommit(tableName string, options model.ForceCommitOptions) (*model.ForceCommitResponse, error) {
+	var result model.ForceCommitResponse
+	endpoint := withQueryParams(fmt.Sprintf("/tables/%s/forceCommit", tableName), options.QueryParams())
+	err := c.named("ForceCommit").CreateObject(endpoint, nil, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetForceCommitStatus(jobId string) (*model.GetForceCommitStatusResponse, error) {
+	var result model.GetForceCommitStatusResponse
+	endpoint := fmt.Sprintf("/tables/forceCommitStatus/%s", jobId)
+	err := c.named("GetForceCommitStatus").FetchData(endpoint, &result)
+	return &result, err
+}
+
+// GetConsumingSegmentsInfo returns the offsets and lag of every consuming segment of a realtime table
+func (c *PinotAPIClient) GetConsumingSegmentsInfo(tableName string) (*model.GetConsumingSegmentsInfoResponse, error) {
+	var result model.GetConsumingSegmentsInfoResponse
+	endpoint := fmt.Sprintf("/tables/%s/consumingSegmentsInfo", tableName)
+	err := c.named("GetConsumingSegmentsInfo").FetchData(endpoint, &result)
 	return &result, err
 }
 
 // GetSchemas returns a list of schemas
 func (c *PinotAPIClient) GetSchemas() (*model.GetSchemaResponse, error) {
 	var result model.GetSchemaResponse
-	err := c.FetchData("/schemas", &result)
+	err := c.named("GetSchemas").FetchData("/schemas", &result)
 	return &result, err
 }
 
 // GetSchema returns a schema
 func (c *PinotAPIClient) GetSchema(schemaName string) (*model.Schema, error) {
 	var result model.Schema
-	err := c.FetchData(fmt.Sprintf("/schemas/%s", schemaName), &result)
+	err := c.named("GetSchema").FetchData(fmt.Sprintf("/schemas/%s", schemaName), &result)
 	return &result, err
 
 }
@@ -566,7 +617,7 @@ func (c *PinotAPIClient) CreateSchema(schema model.Schema) (*model.UserActionRes
 		return nil, fmt.Errorf("unable to marshal schema: %w", err)
 	}
 
-	err = c.CreateObject("/schemas", schemaBytes, result)
+	err = c.named("CreateSchema").CreateObject("/schemas", schemaBytes, &result)
 	return &result, err
 }
 
@@ -587,7 +638,7 @@ func (c *PinotAPIClient) CreateSchemaFromBytes(schemaBytes []byte) (*model.Creat
 	}
 
 	var result model.CreateSchemaResponse
-	err = c.CreateObject("/schemas?override=false&force=false", schemaBytes, &result)
+	err = c.named("CreateSchemaFromBytes").CreateObject("/schemas?override=false&force=false", schemaBytes, &result)
 
 	return &result, err
 
@@ -621,37 +672,31 @@ func (c *PinotAPIClient) ValidateSchema(schema model.Schema) (*model.ValidateSch
 		return nil, fmt.Errorf("unable to marshal schema: %w", err)
 	}
 
-	fullUrl := c.pinotControllerUrl.JoinPath("schemas", "validate").String()
-
-	req, err := http.NewRequest("POST", fullUrl, bytes.NewBuffer(schemaBytes))
-	if err != nil {
-		return nil, fmt.Errorf("client: could not create request: %w", err)
-	}
-
-	res, err := c.pinotHttp.httpClient.Do(req)
+	res, err := c.do("ValidateSchema", http.MethodPost, c.pinotControllerUrl.JoinPath("schemas", "validate"), schemaBytes, "application/json")
 	if err != nil {
-		return nil, fmt.Errorf("client: could not send request: %w", err)
-	}
 
-	if res.StatusCode == http.StatusInternalServerError {
-		return nil, fmt.Errorf("client: internal server error")
-	}
+		// Invalid schema in body
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) && reqErr.StatusCode == http.StatusBadRequest {
 
-	// Invalid schema in body
-	if res.StatusCode == http.StatusBadRequest {
+			var result struct {
+				Error string `json:"error"`
+			}
 
-		var result map[string]string
+			// the controller reports the reason as {"code": 400, "error": "..."}
+			if err := json.Unmarshal([]byte(reqErr.Body), &result); err != nil || result.Error == "" {
+				result.Error = strings.TrimSpace(reqErr.Body)
+			}
 
-		err = json.NewDecoder(res.Body).Decode(&result)
-		if err != nil {
-			return nil, fmt.Errorf("client: could not unmarshal JSON: %w", err)
+			return &model.ValidateSchemaResponse{
+				Ok:    false,
+				Error: result.Error,
+			}, nil
 		}
 
-		return &model.ValidateSchemaResponse{
-			Ok:    false,
-			Error: result["error"],
-		}, nil
+		return nil, err
 	}
+	res.Body.Close()
 
 	return &model.ValidateSchemaResponse{Ok: true}, nil
 }
@@ -673,7 +718,8 @@ func (c *PinotAPIClient) UpdateSchemaFromBytes(schemaBytes []byte) (*model.UserA
 	}
 
 	var result model.UserActionResponse
-	err = c.CreateObject("/schemas", schemaBytes, &result)
+	// the controller only replaces an existing schema when override is requested
+	err = c.named("UpdateSchemaFromBytes").CreateObject("/schemas?override=true", schemaBytes, &result)
 	return &result, err
 }
 
@@ -686,7 +732,7 @@ func (c *PinotAPIClient) UpdateSchema(schema model.Schema) (*model.UserActionRes
 		return nil, fmt.Errorf("unable to marshal schema: %w", err)
 	}
 
-	err = c.CreateObject("/schemas", schemaBytes, result) // Should be PUT?
+	err = c.named("UpdateSchema").CreateObject("/schemas?override=true", schemaBytes, &result)
 	return &result, err
 
 }
@@ -706,14 +752,14 @@ func (c *PinotAPIClient) DeleteSchema(schemaName string) (*model.UserActionRespo
 
 	// proceed with deletion
 	var result model.UserActionResponse
-	err = c.DeleteObject(fmt.Sprintf("/schemas/%s", schemaName), nil, &result)
+	err = c.named("DeleteSchema").DeleteObject(fmt.Sprintf("/schemas/%s", schemaName), nil, &result)
 
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetSchemaFieldSpecs() (*model.GetSchemaFieldSpecsResponse, error) {
 	var result model.GetSchemaFieldSpecsResponse
-	err := c.FetchData(fmt.Sprintf("/schemas/fieldSpec"), &result)
+	err := c.named("GetSchemaFieldSpecs").FetchData(fmt.Sprintf("/schemas/fieldSpec"), &result)
 	return &result, err
 }
 
@@ -721,79 +767,102 @@ func (c *PinotAPIClient) GetSchemaFieldSpecs() (*model.GetSchemaFieldSpecsRespon
 // TODO: Implement Create, Get, GetMetadata, Delete
 // func (c *PinotAPIClient) CreateSegment(body []byte) (*model.UserActionResponse, error) {
 // 	var result model.UserActionResponse
-// 	err := c.CreateFormDataObject("/v2/segments", body, &result)
+// 	err := c.named("GetSchemaFieldSpecs").CreateFormDataObject("/v2/segments", body, &result)
 // 	return &result, err
 // }
 
 func (c *PinotAPIClient) GetSegments(tableName string) (model.GetSegmentsResponse, error) {
 	var result model.GetSegmentsResponse
-	err := c.FetchData(fmt.Sprintf("/segments/%s", tableName), &result)
+	err := c.named("GetSegments").FetchData(fmt.Sprintf("/segments/%s", tableName), &result)
 	return result, err
 }
 
 // func (c *PinotAPIClient) GetSegmentMetadata(tableName string, segmentName string) (*model.GetSegmentMetadataResponse, error) {
 // 	var result model.GetSegmentMetadataResponse
-// 	err := c.FetchData(fmt.Sprintf("/segments/%s/%s/metadata", tableName, segmentName), &result)
+// 	err := c.named("GetSegments").FetchData(fmt.Sprintf("/segments/%s/%s/metadata", tableName, segmentName), &result)
 // 	return &result, err
 // }
 
 // func (c *PinotAPIClient) DeleteSegment(tableName string, segmentName string) (*model.UserActionResponse, error) {
 // 	var result model.UserActionResponse
-// 	err := c.DeleteObject(fmt.Sprintf("/segments/%s/choose", tableName, segmentName), nil, &result)
+// 	err := c.named("GetSegments").DeleteObject(fmt.Sprintf("/segments/%s/choose", tableName, segmentName), nil, &result)
 // 	return &result, err
 // }
 
 func (c *PinotAPIClient) ReloadTableSegments(tableName string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject(fmt.Sprintf("/segments/%s/reload", tableName), nil, &result)
+	err := c.named("ReloadTableSegments").CreateObject(fmt.Sprintf("/segments/%s/reload", tableName), nil, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) ReloadSegment(tableName string, segmentName string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject(fmt.Sprintf("/segments/%s/%s/reload", tableName, segmentName), nil, &result)
+	err := c.named("ReloadSegment").CreateObject(fmt.Sprintf("/segments/%s/%s/reload", tableName, segmentName), nil, &result)
+	return &result, err
+}
+
+// GetReloadJobStatus returns the progress of a reload job started by ReloadTableSegments or ReloadSegment
+func (c *PinotAPIClient) GetReloadJobStatus(jobId string) (*model.GetReloadJobStatusResponse, error) {
+	var result model.GetReloadJobStatusResponse
+	err := c.named("GetReloadJobStatus").FetchData(fmt.Sprintf("/segments/segmentReloadStatus/%s", jobId), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) ResetTableSegments(tableNameWithType string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject(fmt.Sprintf("/segments/%s/reset", tableNameWithType), nil, &result) // you must provide type in the tableName here e.g. airlineStats_OFFLINE
+	err := c.named("ResetTableSegments").CreateObject(fmt.Sprintf("/segments/%s/reset", tableNameWithType), nil, &result) // you must provide type in the tableName here e.g. airlineStats_OFFLINE
 	return &result, err
 }
 
 func (c *PinotAPIClient) ResetTableSegment(tableName string, segmentName string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject(fmt.Sprintf("/segments/%s/%s/reset", tableName, segmentName), nil, &result)
+	err := c.named("ResetTableSegment").CreateObject(fmt.Sprintf("/segments/%s/%s/reset", tableName, segmentName), nil, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetSegmentTiers(tableName string, tableType string) (*model.GetSegmentTiersResponse, error) {
 	var result model.GetSegmentTiersResponse
-	err := c.FetchData(fmt.Sprintf("/segments/%s/tiers?type=%s", tableName, tableType), &result)
+	err := c.named("GetSegmentTiers").FetchData(fmt.Sprintf("/segments/%s/tiers?type=%s", tableName, tableType), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetSegmentCRC(tableName string) (*model.GetSegmentCRCResponse, error) {
 	var result model.GetSegmentCRCResponse
-	err := c.FetchData(fmt.Sprintf("/segments/%s/crc", tableName), &result)
+	err := c.named("GetSegmentCRC").FetchData(fmt.Sprintf("/segments/%s/crc", tableName), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetSegmentMetadata(tableName string) (*model.GetSegmentMetadataResponse, error) {
 	var result model.GetSegmentMetadataResponse
-	err := c.FetchData(fmt.Sprintf("/segments/%s/metadata", tableName), &result)
+	err := c.named("GetSegmentMetadata").FetchData(fmt.Sprintf("/segments/%s/metadata", tableName), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetSegmentZKMetadata(tableName string) (*model.GetSegmentZKMetadataResponse, error) {
 	var result model.GetSegmentZKMetadataResponse
-	err := c.FetchData(fmt.Sprintf("/segments/%s/zkmetadata", tableName), &result)
+	err := c.named("GetSegmentZKMetadata").FetchData(fmt.Sprintf("/segments/%s/zkmetadata", tableName), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) UpdateSegmentZKTimeInterval(tableNameWithType string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject(fmt.Sprintf("/segments/%s/updateZkTimeInterval", tableNameWithType), nil, &result)
+	err := c.named("UpdateSegmentZKTimeInterval").CreateObject(fmt.Sprintf("/segments/%s/updateZkTimeInterval", tableNameWithType), nil, &result)
+	return &result, err
+}
+
+// Leader
+
+// GetLeadersForAllTables returns the lead controller of every table
+func (c *PinotAPIClient) GetLeadersForAllTables() (*model.LeadControllerResponse, error) {
+	var result model.LeadControllerResponse
+	err := c.named("GetLeadersForAllTables").FetchData("/leader/tables", &result)
+	return &result, err
+}
+
+// GetLeaderForTable returns the lead controller of a table
+func (c *PinotAPIClient) GetLeaderForTable(tableName string) (*model.LeadControllerResponse, error) {
+	var result model.LeadControllerResponse
+	err := c.named("GetLeaderForTable").FetchData(fmt.Sprintf("/leader/tables/%s", tableName), &result)
 	return &result, err
 }
 
@@ -801,27 +870,27 @@ func (c *PinotAPIClient) UpdateSegmentZKTimeInterval(tableNameWithType string) (
 
 func (c *PinotAPIClient) GetClusterInfo() (*model.GetClusterResponse, error) {
 	var result model.GetClusterResponse
-	err := c.FetchData("/cluster/info", &result)
+	err := c.named("GetClusterInfo").FetchData("/cluster/info", &result)
 
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetClusterConfigs() (*model.GetClusterConfigResponse, error) {
 	var result model.GetClusterConfigResponse
-	err := c.FetchData("/cluster/configs", &result)
+	err := c.named("GetClusterConfigs").FetchData("/cluster/configs", &result)
 
 	return &result, err
 }
 
 func (c *PinotAPIClient) UpdateClusterConfigs(body []byte) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject("/cluster/configs", body, &result)
+	err := c.named("UpdateClusterConfigs").CreateObject("/cluster/configs", body, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) DeleteClusterConfig(configName string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.DeleteObject(fmt.Sprintf("/cluster/configs/%s", configName), nil, &result)
+	err := c.named("DeleteClusterConfig").DeleteObject(fmt.Sprintf("/cluster/configs/%s", configName), nil, &result)
 	return &result, err
 }
 
@@ -829,118 +898,377 @@ func (c *PinotAPIClient) DeleteClusterConfig(configName string) (*model.UserActi
 
 func (c *PinotAPIClient) GetTenants() (*model.GetTenantsResponse, error) {
 	var result model.GetTenantsResponse
-	err := c.FetchData("/tenants", &result)
+	err := c.named("GetTenants").FetchData("/tenants", &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTenantInstances(tenantName string) (*model.GetTenantResponse, error) {
 	var result model.GetTenantResponse
-	err := c.FetchData(fmt.Sprintf("/tenants/%s", tenantName), &result)
+	err := c.named("GetTenantInstances").FetchData(fmt.Sprintf("/tenants/%s", tenantName), &result)
 	return &result, err
 
 }
 
 func (c *PinotAPIClient) GetTenantTables(tenantName string) (*model.GetTablesResponse, error) {
 	var result model.GetTablesResponse
-	err := c.FetchData(fmt.Sprintf("/tenants/%s/tables", tenantName), &result)
+	err := c.named("GetTenantTables").FetchData(fmt.Sprintf("/tenants/%s/tables", tenantName), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetTenantMetadata(tenantName string) (*model.GetTenantMetadataResponse, error) {
 	var result model.GetTenantMetadataResponse
-	err := c.FetchData(fmt.Sprintf("/tenants/%s/metadata", tenantName), &result)
+	err := c.named("GetTenantMetadata").FetchData(fmt.Sprintf("/tenants/%s/metadata", tenantName), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) CreateTenant(body []byte) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject("/tenants", body, &result)
+	err := c.named("CreateTenant").CreateObject("/tenants", body, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) UpdateTenant(body []byte) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.UpdateObject("/tenants", nil, body, &result)
+	err := c.named("UpdateTenant").UpdateObject("/tenants", nil, body, &result)
+	return &result, err
+}
+
+// CreateTenantFromRequest validates and creates a tenant
+func (c *PinotAPIClient) CreateTenantFromRequest(tenant model.TenantRequest) (*model.UserActionResponse, error) {
+
+	if err := tenant.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tenant: %w", err)
+	}
+
+	tenantBytes, err := json.Marshal(tenant)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal tenant %s: %w", tenant.TenantName, err)
+	}
+
+	return c.CreateTenant(tenantBytes)
+}
+
+// UpdateTenantFromRequest validates and updates a tenant
+func (c *PinotAPIClient) UpdateTenantFromRequest(tenant model.TenantRequest) (*model.UserActionResponse, error) {
+
+	if err := tenant.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tenant: %w", err)
+	}
+
+	tenantBytes, err := json.Marshal(tenant)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal tenant %s: %w", tenant.TenantName, err)
+	}
+
+	return c.UpdateTenant(tenantBytes)
+}
+
+// GetTenantInstancesByRole returns the brokers or servers of a tenant, tableType optionally limits servers
+// to those tagged for OFFLINE or REALTIME tables
+func (c *PinotAPIClient) GetTenantInstancesByRole(tenantName string, tenantRole string, tableType string) (*model.GetTenantResponse, error) {
+	var result model.GetTenantResponse
+	queryParams := make(map[string]string)
+	queryParams["type"] = tenantRole
+	if tableType != "" {
+		queryParams["tableType"] = tableType
+	}
+
+	err := c.named("GetTenantInstancesByRole").FetchData(withQueryParams(fmt.Sprintf("/tenants/%s", tenantName), queryParams), &result)
+	return &result, err
+}
+
+// GetTenantTablesByRole returns the tables assigned to a tenant as either its broker or server tenant
+func (c *PinotAPIClient) GetTenantTablesByRole(tenantName string, tenantRole string) (*model.GetTablesResponse, error) {
+	var result model.GetTablesResponse
+	queryParams := make(map[string]string)
+	queryParams["type"] = strings.ToLower(tenantRole)
+
+	err := c.named("GetTenantTablesByRole").FetchData(withQueryParams(fmt.Sprintf("/tenants/%s/tables", tenantName), queryParams), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) DeleteTenant(tenantName string, tenantType string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.DeleteObject(fmt.Sprintf("/tenants/%s?type=%s", tenantName, tenantType), nil, &result)
+	queryParams := make(map[string]string)
+	queryParams["type"] = tenantType
+
+	err := c.named("DeleteTenant").DeleteObject(fmt.Sprintf("/tenants/%s", tenantName), queryParams, &result)
 	return &result, err
 }
 
-func (c *PinotAPIClient) RebalanceTenant(tenantName string) (*model.UserActionResponse, error) {
-	var result model.UserActionResponse
-	err := c.CreateObject(fmt.Sprintf("/tenants/%s/rebalance", tenantName), nil, &result)
+// SafeDeleteTenant deletes a tenant only if no tables are assigned to it
+func (c *PinotAPIClient) SafeDeleteTenant(tenantName string, tenantType string) (*model.UserActionResponse, error) {
+
+	tables, err := c.GetTenantTablesByRole(tenantName, tenantType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tables of tenant %s: %w", tenantName, err)
+	}
+
+	if len(tables.Tables) > 0 {
+		return nil, fmt.Errorf("tenant %s still has tables assigned: %s", tenantName, strings.Join(tables.Tables, ", "))
+	}
+
+	return c.DeleteTenant(tenantName, tenantType)
+}
+
+// RebalanceTenant starts a rebalance of every table of a tenant, poll the returned job id with GetTenantRebalanceStatus
+func (c *PinotAPIClient) RebalanceTenant(tenantName string, config model.TenantRebalanceConfig) (*model.RebalanceTenantResponse, error) {
+	var result model.RebalanceTenantResponse
+
+	if config.TenantName == "" {
+		config.TenantName = tenantName
+	}
+
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal tenant rebalance config: %w", err)
+	}
+
+	err = c.named("RebalanceTenant").CreateObject(fmt.Sprintf("/tenants/%s/rebalance", tenantName), configBytes, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetTenantRebalanceStatus(jobId string) (*model.GetTenantRebalanceStatusResponse, error) {
+	var result model.GetTenantRebalanceStatusResponse
+	err := c.named("GetTenantRebalanceStatus").FetchData(fmt.Sprintf("/tenants/rebalanceStatus/%s", jobId), &result)
 	return &result, err
 }
 
 // Instances
 func (c *PinotAPIClient) GetInstances() (*model.GetInstancesResponse, error) {
 	var result model.GetInstancesResponse
-	err := c.FetchData("/instances", &result)
+	err := c.named("GetInstances").FetchData("/instances", &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) GetInstance(instanceName string) (*model.GetInstanceResponse, error) {
 	var result model.GetInstanceResponse
-	err := c.FetchData(fmt.Sprintf("/instances/%s", instanceName), &result)
+	err := c.named("GetInstance").FetchData(fmt.Sprintf("/instances/%s", instanceName), &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) CreateInstance(body []byte) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.CreateObject("/instances", body, &result)
+	err := c.named("CreateInstance").CreateObject("/instances", body, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) UpdateInstance(instanceName string, body []byte) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.UpdateObject(fmt.Sprintf("/instances/%s", instanceName), nil, body, &result)
+	err := c.named("UpdateInstance").UpdateObject(fmt.Sprintf("/instances/%s", instanceName), nil, body, &result)
 	return &result, err
 }
 
 func (c *PinotAPIClient) DeleteInstance(instanceName string) (*model.UserActionResponse, error) {
 	var result model.UserActionResponse
-	err := c.DeleteObject(fmt.Sprintf("/instances/%s", instanceName), nil, &result)
+	err := c.named("DeleteInstance").DeleteObject(fmt.Sprintf("/instances/%s", instanceName), nil, &result)
 	return &result, err
 }
 
-func (c *PinotAPIClient) CheckPinotControllerAdminHealth() (*model.PlainTextAPIResponse, error) {
-	// Returns text/plain
-	var result model.PlainTextAPIResponse
-	err := c.FetchPlainText("/pinot-controller/admin", &result)
+// SetInstanceState enables or disables an instance, state is model.InstanceStateEnable or model.InstanceStateDisable
+func (c *PinotAPIClient) SetInstanceState(instanceName string, state string) (*model.UserActionResponse, error) {
+	var result model.UserActionResponse
+	queryParams := make(map[string]string)
+	queryParams["state"] = state
+
+	err := c.named("SetInstanceState").UpdateObject(fmt.Sprintf("/instances/%s/state", instanceName), queryParams, nil, &result)
 	return &result, err
 }
 
-func (c *PinotAPIClient) CheckPinotControllerHealth() (*model.PlainTextAPIResponse, error) {
-	// Returns text/plain
-	var result model.PlainTextAPIResponse
-	err := c.FetchPlainText("/health", &result)
+func (c *PinotAPIClient) EnableInstance(instanceName string) (*model.UserActionResponse, error) {
+	return c.SetInstanceState(instanceName, model.InstanceStateEnable)
+}
+
+func (c *PinotAPIClient) DisableInstance(instanceName string) (*model.UserActionResponse, error) {
+	return c.SetInstanceState(instanceName, model.InstanceStateDisable)
+}
+
+// UpdateInstanceTags replaces the tags of an instance, set updateBrokerResource when changing the tenant of a broker
+func (c *PinotAPIClient) UpdateInstanceTags(instanceName string, tags []string, updateBrokerResource bool) (*model.UserActionResponse, error) {
+	var result model.UserActionResponse
+	queryParams := make(map[string]string)
+	queryParams["tags"] = strings.Join(tags, ",")
+	queryParams["updateBrokerResource"] = strconv.FormatBool(updateBrokerResource)
+
+	err := c.named("UpdateInstanceTags").UpdateObject(fmt.Sprintf("/instances/%s/updateTags", instanceName), queryParams, nil, &result)
 	return &result, err
 }
 
-func (c *PinotAPIClient) extractErrorMessage(resp *http.Response) (string, error) {
-	resultBytes, err := io.ReadAll(resp.Body)
+// UpdateInstancePools replaces the pools of an instance, pools maps a tag to the pool number of the instance for that tag
+func (c *PinotAPIClient) UpdateInstancePools(instanceName string, pools map[string]int) (*model.UserActionResponse, error) {
+
+	instance, err := c.GetInstance(instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instance %s: %w", instanceName, err)
+	}
+
+	port, err := strconv.Atoi(instance.Port)
 	if err != nil {
-		return "", fmt.Errorf("unable to decode response from failed request: %s", err)
+		return nil, fmt.Errorf("instance %s has invalid port %s: %w", instanceName, instance.Port, err)
 	}
-	return string(resultBytes), nil
+
+	body, err := json.Marshal(model.Instance{
+		Host:             instance.Hostname,
+		Port:             port,
+		Type:             string(model.InstanceTypeOf(instanceName)),
+		Tags:             instance.Tags,
+		Pools:            pools,
+		GrpcPort:         instance.GRPCPort,
+		AdminPort:        instance.AdminPort,
+		QueryServicePort: instance.QueryServicePort,
+		QueryMailboxPort: instance.QueryMailboxPort,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal instance %s: %w", instanceName, err)
+	}
+
+	return c.UpdateInstance(instanceName, body)
 }
 
-func (c *PinotAPIClient) logErrorResp(r *http.Response) {
+// UpdateBrokerResource updates the tables a broker routes queries for to match its tags
+func (c *PinotAPIClient) UpdateBrokerResource(instanceName string) (*model.UserActionResponse, error) {
+	var result model.UserActionResponse
+	err := c.named("UpdateBrokerResource").CreateObject(fmt.Sprintf("/instances/%s/updateBrokerResource", instanceName), nil, &result)
+	return &result, err
+}
 
-	var responseContent map[string]any
+// Tasks
 
-	err := json.NewDecoder(r.Body).Decode(&responseContent)
-	if err != nil {
-		c.log.Debug(fmt.Sprintf("unable to decode response from failed request: %s", err))
-		return
+func (c *PinotAPIClient) GetTaskTypes() (*model.GetTaskTypesResponse, error) {
+	var result model.GetTaskTypesResponse
+	err := c.named("GetTaskTypes").FetchData("/tasks/tasktypes", &result)
+	return &result, err
+}
+
+// ScheduleTasks schedules tasks for the given task type and table, leave either empty to schedule for all
+func (c *PinotAPIClient) ScheduleTasks(taskType string, tableNameWithType string) (*model.ScheduleTasksResponse, error) {
+	queryParams := make(map[string]string)
+	if taskType != "" {
+		queryParams["taskType"] = taskType
+	}
+	if tableNameWithType != "" {
+		queryParams["tableName"] = tableNameWithType
 	}
 
-	c.log.Debug(fmt.Sprintf("response from failed request: %s", responseContent))
+	endpoint := withQueryParams("/tasks/schedule", queryParams)
+
+	var result model.ScheduleTasksResponse
+	err := c.named("ScheduleTasks").CreateObject(endpoint, nil, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetTasks(taskType string) (*model.GetTasksResponse, error) {
+	var result model.GetTasksResponse
+	err := c.named("GetTasks").FetchData(fmt.Sprintf("/tasks/%s/tasks", taskType), &result)
+	return &result, err
+}
 
+func (c *PinotAPIClient) GetTaskStates(taskType string) (*model.GetTaskStatesResponse, error) {
+	var result model.GetTaskStatesResponse
+	err := c.named("GetTaskStates").FetchData(fmt.Sprintf("/tasks/%s/taskstates", taskType), &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetTableTaskStates(taskType string, tableNameWithType string) (*model.GetTaskStatesResponse, error) {
+	var result model.GetTaskStatesResponse
+	err := c.named("GetTableTaskStates").FetchData(fmt.Sprintf("/tasks/%s/%s/state", taskType, tableNameWithType), &result)
+	return &result, err
+}
+
+// GetTaskQueueState returns the state of the task queue for a task type
+func (c *PinotAPIClient) GetTaskQueueState(taskType string) (model.TaskState, error) {
+	var result model.TaskState
+	err := c.named("GetTaskQueueState").FetchData(fmt.Sprintf("/tasks/%s/state", taskType), &result)
+	return result, err
+}
+
+func (c *PinotAPIClient) GetTaskState(taskName string) (model.TaskState, error) {
+	var result model.TaskState
+	err := c.named("GetTaskState").FetchData(fmt.Sprintf("/tasks/task/%s/state", taskName), &result)
+	return result, err
+}
+
+func (c *PinotAPIClient) GetSubtaskConfigs(taskName string) (*model.GetSubtaskConfigsResponse, error) {
+	var result model.GetSubtaskConfigsResponse
+	err := c.named("GetSubtaskConfigs").FetchData(fmt.Sprintf("/tasks/subtask/%s/config", taskName), &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetSubtaskProgress(taskName string) (*model.GetSubtaskProgressResponse, error) {
+	var result model.GetSubtaskProgressResponse
+	err := c.named("GetSubtaskProgress").FetchData(fmt.Sprintf("/tasks/subtask/%s/progress", taskName), &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) StopTasks(taskType string) (*model.UserActionResponse, error) {
+	var result model.UserActionResponse
+	err := c.named("StopTasks").UpdateObject(fmt.Sprintf("/tasks/%s/stop", taskType), nil, nil, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) ResumeTasks(taskType string) (*model.UserActionResponse, error) {
+	var result model.UserActionResponse
+	err := c.named("ResumeTasks").UpdateObject(fmt.Sprintf("/tasks/%s/resume", taskType), nil, nil, &result)
+	return &result, err
+}
+
+// CleanupTasks removes finished tasks of the task type from the queue
+func (c *PinotAPIClient) CleanupTasks(taskType string) (*model.UserActionResponse, error) {
+	var result model.UserActionResponse
+	err := c.named("CleanupTasks").UpdateObject(fmt.Sprintf("/tasks/%s/cleanup", taskType), nil, nil, &result)
+	return &result, err
+}
+
+// DeleteTasks deletes the task queue for a task type, forceDelete is required if tasks are still running
+func (c *PinotAPIClient) DeleteTasks(taskType string, forceDelete bool) (*model.UserActionResponse, error) {
+	deletionQueryParams := make(map[string]string)
+	deletionQueryParams["forceDelete"] = strconv.FormatBool(forceDelete)
+
+	var result model.UserActionResponse
+	err := c.named("DeleteTasks").DeleteObject(fmt.Sprintf("/tasks/%s", taskType), deletionQueryParams, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) DeleteTask(taskName string, forceDelete bool) (*model.UserActionResponse, error) {
+	deletionQueryParams := make(map[string]string)
+	deletionQueryParams["forceDelete"] = strconv.FormatBool(forceDelete)
+
+	var result model.UserActionResponse
+	err := c.named("DeleteTask").DeleteObject(fmt.Sprintf("/tasks/task/%s", taskName), deletionQueryParams, &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetTaskDebugInfo(taskName string) (*model.TaskDebugInfo, error) {
+	var result model.TaskDebugInfo
+	err := c.named("GetTaskDebugInfo").FetchData(fmt.Sprintf("/tasks/task/%s/debug", taskName), &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) GetTasksDebugInfo(taskType string) (*model.GetTasksDebugInfoResponse, error) {
+	var result model.GetTasksDebugInfoResponse
+	err := c.named("GetTasksDebugInfo").FetchData(fmt.Sprintf("/tasks/%s/debug", taskType), &result)
+	return &result, err
+}
+
+// GetTaskGeneratorDebugInfo returns the most recent task generation runs for a table and task type
+func (c *PinotAPIClient) GetTaskGeneratorDebugInfo(tableNameWithType string, taskType string) (*model.GetTaskGeneratorDebugInfoResponse, error) {
+	var result model.GetTaskGeneratorDebugInfoResponse
+	err := c.named("GetTaskGeneratorDebugInfo").FetchData(fmt.Sprintf("/tasks/generator/%s/%s/debug", tableNameWithType, taskType), &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) CheckPinotControllerAdminHealth() (*model.PlainTextAPIResponse, error) {
+	// Returns text/plain
+	var result model.PlainTextAPIResponse
+	err := c.named("CheckPinotControllerAdminHealth").FetchPlainText("/pinot-controller/admin", &result)
+	return &result, err
+}
+
+func (c *PinotAPIClient) CheckPinotControllerHealth() (*model.PlainTextAPIResponse, error) {
+	// Returns text/plain
+	var result model.PlainTextAPIResponse
+	err := c.named("CheckPinotControllerHealth").FetchPlainText("/health", &result)
+	return &result, err
 }
 
 func (c *PinotAPIClient) encodeParams(fullUrl *url.URL, params map[string]string) {
@@ -952,16 +1280,29 @@ func (c *PinotAPIClient) encodeParams(fullUrl *url.URL, params map[string]string
 }
 
 func (c *PinotAPIClient) generateQueryParams(queryString string) map[string]string {
-	parts := strings.SplitN(queryString, "=", 2)
-
-	// Create a map and add the key-value pair
+	// Create a map and add each key-value pair, unescaping values encoded by withQueryParams
 	m := make(map[string]string)
-	if len(parts) == 2 {
-		m[parts[0]] = parts[1]
+	values, _ := url.ParseQuery(queryString)
+	for key := range values {
+		m[key] = values.Get(key)
 	}
 	return m
 }
 
+// withQueryParams appends escaped params to an endpoint for requests that take their query from the endpoint
+func withQueryParams(endpoint string, params map[string]string) string {
+	if len(params) == 0 {
+		return endpoint
+	}
+
+	query := url.Values{}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+
+	return endpoint + "?" + query.Encode()
+}
+
 func prepareRequestURL(c *PinotAPIClient, endpoint string) *url.URL {
 	pathAndQuery := strings.SplitN(endpoint, "?", 2)
 	var path string
@@ -12,451 +12,62 @@ import (
 
 	goPinotAPI "github.com/azaurus1/go-pinot-api"
 	model "github.com/azaurus1/go-pinot-api/model"
+	"github.com/azaurus1/go-pinot-api/pinottest"
 	"github.com/stretchr/testify/assert"
 )
 
 const (
-	RouteUsers                                        = "/users"
-	RouteUser                                         = "/users/test"
-	RouteInstances                                    = "/instances"
-	RouteInstance                                     = "/instances/Minion_172.19.0.2_9514"
 	RouteClusterInfo                                  = "/cluster/info"
-	RouteClusterConfigs                               = "/cluster/configs"
-	RouteClusterConfigsDelete                         = "/cluster/configs/allowParticipantAutoJoin"
-	RouteTenants                                      = "/tenants"
-	RouteTenantsInstances                             = "/tenants/DefaultTenant"
-	RouteTenantsTables                                = "/tenants/DefaultTenant/tables"
-	RouteTenantsMetadata                              = "/tenants/DefaultTenant/metadata"
-	RouteSegmentsTest                                 = "/segments/test"
-	RouteSegmentsTestReload                           = "/segments/test/reload"
-	RouteSegmentTestReload                            = "/segments/test/test_1/reload"
 	RouteSegmentTestResetAll                          = "/segments/test_OFFLINE/reset"
 	RouteSegmentTestReset                             = "/segments/test_OFFLINE/test_OFFLINE_16071_16071_0/reset"
 	RouteSegmentTestTiers                             = "/segments/test/tiers"
 	RouteSegmentTestCRC                               = "/segments/test/crc"
 	RouteSegmentTestMetadata                          = "/segments/test/metadata"
 	RouteSegmentTestZKMetadata                        = "/segments/test/zkmetadata"
-	RouteV2Segments                                   = "/v2/segments"
 	RouteSchemas                                      = "/schemas"
-	RouteSchemasTest                                  = "/schemas/test"
 	RouteSchemasFieldSpec                             = "/schemas/fieldSpec"
-	RouteTables                                       = "/tables"
-	RouteTablesTest                                   = "/tables/test"
 	RouteTablesTestExternalView                       = "/tables/test/externalview"
 	RouteTablesTestIdealState                         = "/tables/test/idealstate"
 	RouteTablesTestIndexes                            = "/tables/test/indexes"
-	RouteTablesTestInstances                          = "/tables/test/instances"
 	RouteTablesLiveBrokers                            = "/tables/livebrokers"
 	RouteTablesTestLiveBrokers                        = "/tables/test/livebrokers"
 	RouteTablesTestMetadata                           = "/tables/test/metadata"
 	RouteTablesTestRebuildBrokerResourceFromHelixTags = "/tables/test/rebuildBrokerResourceFromHelixTags"
-	RouteTablesTestSchema                             = "/tables/test/schema"
 	RouteTablesTestSize                               = "/tables/test/size"
-	RouteTablesTestState                              = "/tables/test/state"
 	RouteTablesTestStats                              = "/tables/test/stats"
-	RoutePinotControllerAdmin                         = "/pinot-controller/admin"
-	RouteHealth                                       = "/health"
+	RouteTasksTaskTypes                               = "/tasks/tasktypes"
+	RouteTasksSchedule                                = "/tasks/schedule"
+	RouteTasksMergeRollupTasks                        = "/tasks/MergeRollupTask/tasks"
+	RouteTasksMergeRollupTaskStates                   = "/tasks/MergeRollupTask/taskstates"
+	RouteTasksMergeRollupTableState                   = "/tasks/MergeRollupTask/test_OFFLINE/state"
+	RouteTasksMergeRollupState                        = "/tasks/MergeRollupTask/state"
+	RouteTasksMergeRollupStop                         = "/tasks/MergeRollupTask/stop"
+	RouteTasksMergeRollupResume                       = "/tasks/MergeRollupTask/resume"
+	RouteTasksMergeRollupCleanup                      = "/tasks/MergeRollupTask/cleanup"
+	RouteTasksMergeRollup                             = "/tasks/MergeRollupTask"
+	RouteTasksMergeRollupDebug                        = "/tasks/MergeRollupTask/debug"
+	RouteTasksTask                                    = "/tasks/task/Task_MergeRollupTask_1712959630094"
+	RouteTasksTaskState                               = "/tasks/task/Task_MergeRollupTask_1712959630094/state"
+	RouteTasksSubtaskConfig                           = "/tasks/subtask/Task_MergeRollupTask_1712959630094/config"
+	RouteTasksTaskDebug                               = "/tasks/task/Task_MergeRollupTask_1712959630094/debug"
+	RouteTasksSubtaskProgress                         = "/tasks/subtask/Task_MergeRollupTask_1712959630094/progress"
+	RouteTasksGeneratorDebug                          = "/tasks/generator/test_OFFLINE/MergeRollupTask/debug"
+	RouteTenantsRebalance                             = "/tenants/DefaultTenant/rebalance"
+	RouteTenantsRebalanceStatus                       = "/tenants/rebalanceStatus/4b7a7bd6-b4d4-4d3b-98a4-3e3b8a7e3f2d"
+	RouteTablesTestPauseConsumption                   = "/tables/test/pauseConsumption"
+	RouteTablesTestResumeConsumption                  = "/tables/test/resumeConsumption"
+	RouteTablesTestPauseStatus                        = "/tables/test/pauseStatus"
+	RouteTablesTestForceCommit                        = "/tables/test/forceCommit"
+	RouteTablesForceCommitStatus                      = "/tables/forceCommitStatus/6c1d3f0a-8e2b-4b7d-9a5c-0f4e2d7b8a11"
+	RouteTablesTestConsumingSegmentsInfo              = "/tables/test/consumingSegmentsInfo"
+	RouteTablesAirlineStatsConsumingSegmentsInfo      = "/tables/airlineStats/consumingSegmentsInfo"
+	RouteSegmentAirlineStatsZKMetadata                = "/segments/airlineStats/zkmetadata"
+	RouteTablesAirlineStatsExternalView               = "/tables/airlineStats/externalview"
+	RouteTablesAirlineStatsSize                       = "/tables/airlineStats/size"
+	RouteLeaderTables                                 = "/leader/tables"
+	RouteLeaderTablesTest                             = "/leader/tables/test"
 )
 
-func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("Authorization") != "Basic YWRtaW46YWRtaW4K" {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
-		next(w, r)
-	}
-
-}
-
-func handleGetUsers(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"users": {"test_BROKER": {"username": "test","password": "$2a$10$3KYPvIy4fBM3CWfdKSc54u/BOn1rPlgb7u4P66s4upqAct30C/q6a","component": "BROKER","role": "ADMIN","usernameWithComponent": "test_BROKER"}}}`)
-}
-
-func handleGetUser(w http.ResponseWriter, r *http.Request) {
-	component := r.URL.Query().Get("component")
-
-	if component == "BROKER" {
-		fmt.Fprint(w, `{"test_BROKER": {"username": "test","password": "$2a$10$3KYPvIy4fBM3CWfdKSc54u/BOn1rPlgb7u4P66s4upqAct30C/q6a","component": "BROKER","role": "ADMIN","usernameWithComponent": "test_BROKER"}}`)
-	}
-}
-
-func handlePutUser(w http.ResponseWriter, r *http.Request) {
-	component := r.URL.Query().Get("component")
-
-	if component == "BROKER" {
-		fmt.Fprint(w, `{"status": "User config update for test_BROKER"}`)
-	}
-
-}
-
-func handleDeleteUser(w http.ResponseWriter, r *http.Request) {
-
-	component := r.URL.Query().Get("component")
-
-	if component == "BROKER" {
-		fmt.Fprint(w, `{"status": "User: test_BROKER has been successfully deleted"}`)
-	} else if component == "" {
-		http.Error(w, `{"code": 400,"error": "Name is null"}`, http.StatusBadRequest)
-	}
-}
-
-func handlePostUsers(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "User testUser_BROKER has been successfully added!"}`)
-}
-
-func handleGetInstances(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"instances": ["Minion_172.19.0.2_9514","Server_172.19.0.7_8098","Broker_cdba1ba98e74_8099","Controller_8684b6757488_9000"]}`)
-}
-
-func handlePostInstances(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Added instance: Broker_localhost_1234"}`)
-}
-
-func handleGetInstance(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, `{"instanceName": "Minion_172.19.0.2_9514","hostName": "172.19.0.2","enabled": true,"port": "9514","tags": ["minion_untagged"],"pools": null,"grpcPort": -1,"adminPort": -1,"queryServicePort": -1,"queryMailboxPort": -1,"systemResourceInfo": null}`)
-
-}
-
-func handleGetClusterInfo(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
-}
-
-func handleGetClusterConfigs(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, `{"allowParticipantAutoJoin": "true","enable.case.insensitive": "true","pinot.broker.enable.query.limit.override": "false","default.hyperloglog.log2m": "8"}`)
-}
-
-func handlePostClusterConfigs(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Updated cluster config."}`)
-}
-
-func handleDeleteClusterConfigs(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Deleted cluster config: allowParticipantAutoJoin"}`)
-}
-
-func handleGetTenants(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"SERVER_TENANTS": ["DefaultTenant"],"BROKER_TENANTS": ["DefaultTenant"]}`)
-}
-
-func handleCreateTenant(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Successfully created tenant"}`)
-}
-
-func handleUpdateTenant(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Updated tenant"}`)
-}
-
-func handleDeleteTenant(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Successfully deleted tenant DefaultTenant"}`)
-}
-
-func handleGetTenantInstances(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"ServerInstances": ["Server_172.19.0.7_8098"],"BrokerInstances": ["Broker_91e4732e326d_8099"],"tenantName": "DefaultTenant"}`)
-}
-
-func handleGetTenantTables(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"tables": []}`)
-}
-
-func handleGetTenantMetadata(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"ServerInstances": ["Server_172.19.0.7_8098"],"OfflineServerInstances": null,"RealtimeServerInstances": null,"BrokerInstances": ["Broker_91e4732e326d_8099"],"tenantName": "DefaultTenant"}`)
-}
-
-func handleGetSegments(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `[{"OFFLINE": ["test_1"],"REALTIME": ["test_1"]}]`)
-}
-
-func handleReloadTableSegments(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "{\"test_OFFLINE\":{\"reloadJobId\":\"f9db13c7-3ad6-45a8-a08f-75cd03c42fb5\",\"reloadJobMetaZKStorageStatus\":\"SUCCESS\",\"numMessagesSent\":\"1\"}}"}`)
-}
-
-func handleReloadTableSegment(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Submitted reload job id: ce4650a9-774a-4b22-919b-4cb22b5c8129, sent 1 reload messages. Job meta ZK storage status: SUCCESS"}`)
-}
-
-func handleGetSchemas(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `["test"]`)
-}
-
-func handleGetSchema(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"schemaName": "test","enableColumnBasedNullHandling": false,"dimensionFieldSpecs": [{"name": "id","dataType": "STRING","notNull": false},{"name": "type","dataType": "STRING","notNull": false},{"name": "actor","dataType": "JSON","notNull": false},{"name": "repo","dataType": "JSON","notNull": false},{"name": "payload","dataType": "JSON","notNull": false},{"name": "public","dataType": "BOOLEAN","notNull": false}],"dateTimeFieldSpecs": [{"name": "created_at","dataType": "STRING","notNull": false,"format": "1:SECONDS:SIMPLE_DATE_FORMAT:yyyy-MM-dd'T'HH:mm:ss'Z'","granularity": "1:SECONDS"},{"name": "created_at_timestamp","dataType": "TIMESTAMP","notNull": false,"format": "1:MILLISECONDS:TIMESTAMP","granularity": "1:SECONDS"}]}`)
-}
-
-func handleCreateSchema(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"unrecognizedProperties": {},"status": "test successfully added"}`)
-}
-
-func handleUpdateSchema(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"unrecognizedProperties": {},"status": "test successfully added"}`)
-}
-
-func handleDeleteSchema(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Schema test deleted"}`)
-}
-
-func handleValidateSchema(w http.ResponseWriter, r *http.Request) {
-	// return 200
-	fmt.Fprint(w, `{"ok": "true"}`)
-}
-
-func handleGetTables(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"tables": ["test"]}`)
-}
-
-func handleGetTableConfig(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, `{
-		"OFFLINE": {
-		  "tableName": "test_OFFLINE",
-		  "tableType": "OFFLINE",
-		  "segmentsConfig": {
-			"timeColumnName": "DaysSinceEpoch",
-			"replication": "1",
-			"timeType": "DAYS",
-			"minimizeDataMovement": false,
-			"segmentAssignmentStrategy": "BalanceNumSegmentAssignmentStrategy",
-			"segmentPushType": "APPEND"
-		  },
-		  "tenants": {
-			"broker": "DefaultTenant",
-			"server": "DefaultTenant"
-		  },
-		  "tableIndexConfig": {
-			"enableDefaultStarTree": false,
-			"starTreeIndexConfigs": [
-			  {
-				"dimensionsSplitOrder": [
-				  "AirlineID",
-				  "Origin",
-				  "Dest"
-				],
-				"functionColumnPairs": [
-				  "COUNT__*",
-				  "MAX__ArrDelay"
-				],
-				"maxLeafRecords": 10
-			  }
-			],
-			"tierOverwrites": {
-			  "hotTier": {
-				"starTreeIndexConfigs": [
-				  {
-					"dimensionsSplitOrder": [
-					  "Carrier",
-					  "CancellationCode",
-					  "Origin",
-					  "Dest"
-					],
-					"skipStarNodeCreationForDimensions": [],
-					"functionColumnPairs": [
-					  "MAX__CarrierDelay",
-					  "AVG__CarrierDelay"
-					],
-					"maxLeafRecords": 10
-				  }
-				]
-			  },
-			  "coldTier": {
-				"starTreeIndexConfigs": []
-			  }
-			},
-			"enableDynamicStarTreeCreation": true,
-			"aggregateMetrics": false,
-			"nullHandlingEnabled": false,
-			"columnMajorSegmentBuilderEnabled": false,
-			"optimizeDictionary": false,
-			"optimizeDictionaryForMetrics": false,
-			"noDictionarySizeRatioThreshold": 0.85,
-			"rangeIndexVersion": 2,
-			"autoGeneratedInvertedIndex": false,
-			"createInvertedIndexDuringSegmentGeneration": false,
-			"loadMode": "MMAP"
-		  },
-		  "metadata": {
-			"customConfigs": {}
-		  },
-		  "fieldConfigList": [
-			{
-			  "name": "ts",
-			  "encodingType": "DICTIONARY",
-			  "indexType": "TIMESTAMP",
-			  "indexTypes": [
-				"TIMESTAMP"
-			  ],
-			  "timestampConfig": {
-				"granularities": [
-				  "DAY",
-				  "WEEK",
-				  "MONTH"
-				]
-			  },
-			  "indexes": null,
-			  "tierOverwrites": null
-			},
-			{
-			  "name": "ArrTimeBlk",
-			  "encodingType": "DICTIONARY",
-			  "indexTypes": [],
-			  "indexes": {
-				"inverted": {
-				  "enabled": "true"
-				}
-			  },
-			  "tierOverwrites": {
-				"hotTier": {
-				  "encodingType": "DICTIONARY",
-				  "indexes": {
-					"bloom": {
-					  "enabled": "true"
-					}
-				  }
-				},
-				"coldTier": {
-				  "encodingType": "RAW",
-				  "indexes": {
-					"text": {
-					  "enabled": "true"
-					}
-				  }
-				}
-			  }
-			}
-		  ],
-		  "ingestionConfig": {
-			"segmentTimeValueCheck": true,
-			"transformConfigs": [
-			  {
-				"columnName": "ts",
-				"transformFunction": "fromEpochDays(DaysSinceEpoch)"
-			  },
-			  {
-				"columnName": "tsRaw",
-				"transformFunction": "fromEpochDays(DaysSinceEpoch)"
-			  }
-			],
-			"continueOnError": false,
-			"rowTimeValueCheck": false
-		  },
-		  "tierConfigs": [
-			{
-			  "name": "hotTier",
-			  "segmentSelectorType": "time",
-			  "segmentAge": "3130d",
-			  "storageType": "pinot_server",
-			  "serverTag": "DefaultTenant_OFFLINE"
-			},
-			{
-			  "name": "coldTier",
-			  "segmentSelectorType": "time",
-			  "segmentAge": "3140d",
-			  "storageType": "pinot_server",
-			  "serverTag": "DefaultTenant_OFFLINE"
-			}
-		  ],
-		  "isDimTable": false
-		},
-		"REALTIME":{
-			"tableName":"realtime_ethereum_mainnet_block_headers_REALTIME",
-			"tableType":"REALTIME",
-			"segmentsConfig":{
-				"replication":"1",
-				"retentionTimeUnit":"DAYS",
-				"retentionTimeValue":"7",
-				"timeType":"MILLISECONDS",
-				"replicasPerPartition":"1",
-				"timeColumnName":"timestamp",
-				"deletedSegmentsRetentionPeriod":"1d",
-				"minimizeDataMovement":false
-			},
-			"tenants":{
-				"broker":"DefaultTenant",
-				"server":"DefaultTenant"
-			},
-			"tableIndexConfig":{
-				"segmentNameGeneratorType":"",
-				"columnMajorSegmentBuilderEnabled":false,
-				"optimizeDictionary":false,
-				"optimizeDictionaryForMetrics":false,
-				"noDictionarySizeRatioThreshold":0.85,
-				"noDictionaryColumns":["hash"],
-				"rangeIndexVersion":2,
-				"autoGeneratedInvertedIndex":false,
-				"createInvertedIndexDuringSegmentGeneration":false,
-				"sortedColumn":["number"],
-				"loadMode":"MMAP",
-				"varLengthDictionaryColumns":["parent_hash"],
-				"enableDefaultStarTree":false,
-				"enableDynamicStarTreeCreation":false,
-				"aggregateMetrics":false,
-				"nullHandlingEnabled":false
-			},
-			"metadata":{
-				"customConfigs":{
-					"customKey":"customValue"
-				}
-			},
-			"routing":{
-				"segmentPrunerTypes":["partition"],
-				"instanceSelectorType":"strictReplicaGroup"
-			},
-			"upsertConfig":{
-				"mode":"FULL",
-				"dropOutOfOrderRecord":false,
-				"hashFunction":"NONE",
-				"defaultPartialUpsertStrategy":"OVERWRITE",
-				"metadataTTL":84600.0,
-				"deletedKeysTTL":0.0,
-				"enablePreload":false,
-				"enableSnapshot":true
-			},
-			"ingestionConfig":{
-				"streamIngestionConfig":{
-					"streamConfigMaps":[{
-						"stream.kafka.broker.list":"kafka:9092",
-						"stream.kafka.consumer.factory.class.name":"org.apache.pinot.plugin.stream.kafka20.KafkaConsumerFactory",
-						"stream.kafka.consumer.prop.auto.offset.reset":"smallest",
-						"stream.kafka.consumer.type":"high-level",
-						"stream.kafka.decoder.class.name":"org.apache.pinot.plugin.stream.kafka.KafkaJSONMessageDecoder",
-						"stream.kafka.decoder.prop.schema.registry.rest.url":"http://schema-registry:8081",
-						"stream.kafka.decoder.prop.schema.registry.schema.name":"ethereum_mainnet_block_headers-value",
-						"stream.kafka.decoder.prop.schema.registry.schema.version":"latest",
-						"stream.kafka.topic.name":"ethereum_mainnet_block_headers",
-						"stream.kafka.zk.broker.url":"kafka:2181",
-						"streamType":"kafka"
-					}],
-					"columnMajorSegmentBuilderEnabled":false
-				},
-				"transformConfigs":[{
-					"columnName":"hash_json",
-					"transformFunction":"json_format(hash)"
-				}],
-				"continueOnError":true,
-				"rowTimeValueCheck":true,
-				"segmentTimeValueCheck":true
-			},
-			"isDimTable":false}}
-	}`)
-}
-
-func handleCreateTable(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"unrecognizedProperties": {"/fieldConfigList/0/indexTypes": null},"status": "Table test_OFFLINE successfully added"}`)
-}
-
-func handleUpdateTable(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"unrecognizedProperties": {"/fieldConfigList/0/indexTypes": null},"status": "Table config updated for test_OFFLINE"}`)
-}
-
-func handleDeleteTable(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, `{"status": "Tables: [test_OFFLINE] deleted"}`)
-}
-
-func handlePinotControllerAdmin(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, "GOOD")
-}
-
-func handleHealth(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprint(w, "OK")
-}
-
 func handleGetFieldSpecs(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, `
 	{
@@ -1849,21 +1460,6 @@ func handleTableIndexes(w http.ResponseWriter, r *http.Request) {
 	  }`)
 }
 
-func handleGetTableInstances(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, `{
-		"tableName": "test",
-		"brokers": [
-		  {
-			"tableType": "offline",
-			"instances": [
-			  "Broker_172.17.0.3_8000"
-			]
-		  }
-		],
-		"server": []
-	  }`)
-}
-
 func handleGetTableLiveBrokers(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, `{
 		"test_OFFLINE": [
@@ -1951,438 +1547,7 @@ func handleTableRebuildBrokerResourceFromHelixTags(w http.ResponseWriter, r *htt
 	  }`)
 }
 
-func handleGetTableSchema(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, `{
-		"schemaName": "test",
-		"enableColumnBasedNullHandling": false,
-		"dimensionFieldSpecs": [
-		  {
-			"name": "ActualElapsedTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "AirTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "AirlineID",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "ArrDel15",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "ArrDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "ArrDelayMinutes",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "ArrTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "ArrTimeBlk",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "ArrivalDelayGroups",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "CRSArrTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "CRSDepTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "CRSElapsedTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "CancellationCode",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "Cancelled",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Carrier",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "CarrierDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DayOfWeek",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DayofMonth",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DepDel15",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DepDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DepDelayMinutes",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DepTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DepTimeBlk",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "DepartureDelayGroups",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Dest",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "DestAirportID",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DestAirportSeqID",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DestCityMarketID",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DestCityName",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "DestState",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "DestStateFips",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DestStateName",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "DestWac",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Distance",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DistanceGroup",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DivActualElapsedTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DivAirportIDs",
-			"dataType": "INT",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "DivAirportLandings",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DivAirportSeqIDs",
-			"dataType": "INT",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "DivAirports",
-			"dataType": "STRING",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "DivArrDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DivDistance",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DivLongestGTimes",
-			"dataType": "INT",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "DivReachedDest",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "DivTailNums",
-			"dataType": "STRING",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "DivTotalGTimes",
-			"dataType": "INT",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "DivWheelsOffs",
-			"dataType": "INT",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "DivWheelsOns",
-			"dataType": "INT",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "Diverted",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "FirstDepTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "FlightDate",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "FlightNum",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Flights",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "LateAircraftDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "LongestAddGTime",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Month",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "NASDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Origin",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginAirportID",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginAirportSeqID",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginCityMarketID",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginCityName",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginState",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginStateFips",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginStateName",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "OriginWac",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Quarter",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "RandomAirports",
-			"dataType": "STRING",
-			"singleValueField": false,
-			"notNull": false
-		  },
-		  {
-			"name": "SecurityDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "TailNum",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "TaxiIn",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "TaxiOut",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "Year",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "WheelsOn",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "WheelsOff",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "WeatherDelay",
-			"dataType": "INT",
-			"notNull": false
-		  },
-		  {
-			"name": "UniqueCarrier",
-			"dataType": "STRING",
-			"notNull": false
-		  },
-		  {
-			"name": "TotalAddGTime",
-			"dataType": "INT",
-			"notNull": false
-		  }
-		],
-		"dateTimeFieldSpecs": [
-		  {
-			"name": "DaysSinceEpoch",
-			"dataType": "INT",
-			"notNull": false,
-			"format": "1:DAYS:EPOCH",
-			"granularity": "1:DAYS"
-		  },
-		  {
-			"name": "ts",
-			"dataType": "TIMESTAMP",
-			"notNull": false,
-			"format": "1:MILLISECONDS:TIMESTAMP",
-			"granularity": "1:SECONDS"
-		  },
-		  {
-			"name": "tsRaw",
-			"dataType": "TIMESTAMP",
-			"notNull": false,
-			"format": "1:MILLISECONDS:TIMESTAMP",
-			"granularity": "1:SECONDS"
-		  }
-		]
-	  }`)
-}
-
-func handleGetTableSize(w http.ResponseWriter, r *http.Request) {
+func handleGetTableSize(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, `{
 		"tableName": "test",
 		"reportedSizeInBytes": 4723495,
@@ -2741,18 +1906,6 @@ func handleGetTableSize(w http.ResponseWriter, r *http.Request) {
 	  }`)
 }
 
-func handleGetTableState(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, `{
-		"state": "enabled"
-	  }`)
-}
-
-func handleChangeTableState(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, `{
-		"status": "Request to enable table 'test_OFFLINE' is successful"
-	  }`)
-}
-
 func handleGetTableStats(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, `{
 		"OFFLINE": {
@@ -3464,508 +2617,766 @@ func handleGetSegmentZKMetadata(w http.ResponseWriter, r *http.Request) {
 	  }`)
 }
 
-func createMockControllerServer() *httptest.Server {
-
-	mux := http.NewServeMux()
+func handleGetTaskTypes(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `["MergeRollupTask","SegmentGenerationAndPushTask"]`)
+}
 
-	mux.HandleFunc(RouteUsers, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetUsers(w, r)
-		case "POST":
-			handlePostUsers(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteUser, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetUser(w, r)
-		case "PUT":
-			handlePutUser(w, r)
-		case "DELETE":
-			handleDeleteUser(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteInstances, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetInstances(w, r)
-		case "POST":
-			handlePostInstances(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteInstance, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetInstance(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteClusterInfo, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetClusterInfo(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteClusterConfigs, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetClusterConfigs(w, r)
-		case "POST":
-			handlePostClusterConfigs(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteClusterConfigsDelete, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "DELETE":
-			handleDeleteClusterConfigs(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTenants, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTenants(w, r)
-		case "POST":
-			handleCreateTenant(w, r)
-		case "PUT":
-			handleUpdateTenant(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTenantsInstances, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTenantInstances(w, r)
-		case "DELETE":
-			handleDeleteTenant(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTenantsTables, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTenantTables(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTenantsMetadata, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTenantMetadata(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentsTest, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetSegments(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentsTestReload, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "POST":
-			handleReloadTableSegments(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentTestReload, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "POST":
-			handleReloadTableSegment(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSchemas, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetSchemas(w, r)
-		case "POST":
-			handleCreateSchema(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSchemasTest, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetSchema(w, r)
-		case "PUT":
-			handleUpdateSchema(w, r)
-		case "DELETE":
-			handleDeleteSchema(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTables, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTables(w, r)
-		case "POST":
-			handleCreateTable(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTest, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableConfig(w, r)
-		case "PUT":
-			handleUpdateTable(w, r)
-		case "DELETE":
-			handleDeleteTable(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestExternalView, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleTableExternalView(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RoutePinotControllerAdmin, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handlePinotControllerAdmin(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteHealth, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleHealth(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSchemasFieldSpec, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetFieldSpecs(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestIdealState, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleTableIdealState(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestIndexes, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleTableIndexes(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestInstances, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableInstances(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesLiveBrokers, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableLiveBrokers(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestLiveBrokers, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableTestLiveBrokers(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestMetadata, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableTestMetadata(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestRebuildBrokerResourceFromHelixTags, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "POST":
-			handleTableRebuildBrokerResourceFromHelixTags(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestSchema, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableSchema(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestSize, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableSize(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestState, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableState(w, r)
-		case "PUT":
-			handleChangeTableState(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteTablesTestStats, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetTableStats(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentTestReset, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "POST":
-			handleResetTableSegment(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentTestResetAll, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "POST":
-			handleResetTableSegments(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentTestTiers, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetSegmentTiers(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentTestCRC, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetSegmentCRC(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentTestMetadata, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetSegmentMetadata(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	mux.HandleFunc(RouteSegmentTestZKMetadata, authMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			handleGetSegmentZKMetadata(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
+func handleScheduleTasks(w http.ResponseWriter, r *http.Request) {
+	taskType := r.URL.Query().Get("taskType")
+	tableName := r.URL.Query().Get("tableName")
 
-	return httptest.NewServer(mux)
+	if taskType != "MergeRollupTask" || tableName != "test_OFFLINE" {
+		http.Error(w, `{"code": 400,"error": "Unknown task type or table"}`, http.StatusBadRequest)
+		return
+	}
 
+	fmt.Fprint(w, `{"MergeRollupTask": "Task_MergeRollupTask_1712959630094"}`)
 }
 
-func createPinotClient(server *httptest.Server) *goPinotAPI.PinotAPIClient {
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-
-	return goPinotAPI.NewPinotAPIClient(
-		goPinotAPI.ControllerUrl(server.URL),
-		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
-		goPinotAPI.Logger(logger),
-	)
+func handleGetTasks(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `["Task_MergeRollupTask_1712959630094"]`)
 }
 
-func getSchema() model.Schema {
+func handleGetTaskStates(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"Task_MergeRollupTask_1712959630094": "COMPLETED"}`)
+}
 
-	schemaFilePath := "./example/data-gen/block_header_schema.json"
+func handleGetTaskQueueState(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `"IN_PROGRESS"`)
+}
 
-	f, err := os.Open(schemaFilePath)
-	if err != nil {
-		log.Panic(err)
-	}
+func handleGetTaskState(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `"COMPLETED"`)
+}
 
-	defer f.Close()
+func handleGetSubtaskConfigs(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"Task_MergeRollupTask_1712959630094_0": {
+		  "taskType": "MergeRollupTask",
+		  "configs": {
+			"tableName": "test_OFFLINE",
+			"segmentName": "test_OFFLINE_16071_16071_0,test_OFFLINE_16072_16072_0",
+			"mergeType": "concat"
+		  }
+		}
+	  }`)
+}
 
-	var schema model.Schema
-	err = json.NewDecoder(f).Decode(&schema)
-	if err != nil {
-		log.Panic(err)
-	}
+func handleGetSubtaskProgress(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"Task_MergeRollupTask_1712959630094_0": "No status from worker: Minion_172.19.0.2_9514"}`)
+}
 
-	return schema
+func handleStopTasks(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"status": "Successfully stopped tasks for task type: MergeRollupTask"}`)
 }
 
-func boolPtr(b bool) *bool {
-	return &b
+func handleResumeTasks(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"status": "Successfully resumed tasks for task type: MergeRollupTask"}`)
 }
 
-// TestFetchData
-// func TestFetchData(t *testing.T) {
+func handleCleanupTasks(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"status": "Successfully cleaned up tasks for task type: MergeRollupTask"}`)
+}
 
-// }
+func handleDeleteTasks(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("forceDelete") != "true" {
+		http.Error(w, `{"code": 400,"error": "There are running tasks for task type: MergeRollupTask"}`, http.StatusBadRequest)
+		return
+	}
+	fmt.Fprint(w, `{"status": "Successfully deleted tasks for task type: MergeRollupTask"}`)
+}
 
-// test FetchData unauthorized
+func handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"status": "Successfully deleted task: Task_MergeRollupTask_1712959630094"}`)
+}
 
-// Test GetUsers
-func TestGetUsers(t *testing.T) {
+func handleGetTaskDebugInfo(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"taskState": "COMPLETED",
+		"subtaskCount": {"waiting": 0, "error": 0, "running": 0, "completed": 1, "unknown": 0, "total": 1},
+		"startTime": "2024-04-12 22:07:10 UTC",
+		"finishTime": "2024-04-12 22:07:14 UTC",
+		"subtaskInfos": [
+		  {
+			"taskId": "Task_MergeRollupTask_1712959630094_0",
+			"state": "COMPLETED",
+			"startTime": "2024-04-12 22:07:10 UTC",
+			"finishTime": "2024-04-12 22:07:14 UTC",
+			"participant": "Minion_172.19.0.2_9514",
+			"info": "Succeeded"
+		  }
+		]
+	  }`)
+}
 
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+func handleGetTasksDebugInfo(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"Task_MergeRollupTask_1712959630094": {
+		  "taskState": "COMPLETED",
+		  "subtaskCount": {"waiting": 0, "error": 0, "running": 0, "completed": 1, "unknown": 0, "total": 1}
+		}
+	  }`)
+}
 
-	res, err := client.GetUsers()
+func handleGetTaskGeneratorDebugInfo(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `[
+		{
+		  "tableNameWithType": "test_OFFLINE",
+		  "taskType": "MergeRollupTask",
+		  "mostRecentSuccessRunTS": ["2024-04-12 22:07:10 UTC"],
+		  "mostRecentErrorRunMessages": {}
+		}
+	  ]`)
+}
 
-	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+func handleRebalanceTenant(w http.ResponseWriter, r *http.Request) {
+	var config model.TenantRebalanceConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, "Tenant rebalance config is required", http.StatusBadRequest)
+		return
 	}
 
-	// expect 1 user in the response username test
-	assert.Equal(t, len(res.Users), 1, "Expected 1 user in the response")
-	assert.Equal(t, res.Users["test_BROKER"].Username, "test", "Expected username to be test")
-
+	fmt.Fprint(w, `{
+		"jobId": "4b7a7bd6-b4d4-4d3b-98a4-3e3b8a7e3f2d",
+		"rebalanceTableResults": {
+		  "test_OFFLINE": {
+			"jobId": "4b7a7bd6-b4d4-4d3b-98a4-3e3b8a7e3f2d",
+			"status": "IN_PROGRESS",
+			"description": "In progress, check controller task status for the progress"
+		  }
+		}
+	  }`)
 }
 
-// Test GetUser
-func TestGetUser(t *testing.T) {
-
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+func handleGetTenantRebalanceStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"tenantRebalanceProgressStats": {
+		  "startTimeMs": 1712959630094,
+		  "timeToFinishInSeconds": 2,
+		  "completionStatusMsg": "Successfully rebalanced tenant DefaultTenant.",
+		  "tableStatusMap": {"test_OFFLINE": "DONE"},
+		  "totalTables": 1,
+		  "remainingTables": 0,
+		  "tableRebalanceJobIdMap": {"test_OFFLINE": "4b7a7bd6-b4d4-4d3b-98a4-3e3b8a7e3f2d"}
+		},
+		"timeElapsedSinceStartInSeconds": 2
+	  }`)
+}
 
-	res, err := client.GetUser("test", "BROKER")
+func handlePauseConsumption(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, `{
+		"pauseFlag": true,
+		"consumingSegments": ["test__0__1__20240412T2107Z"],
+		"reasonCode": "ADMINISTRATIVE",
+		"comment": "%s"
+	}`, r.URL.Query().Get("comment"))
+}
 
-	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+func handleResumeConsumption(w http.ResponseWriter, r *http.Request) {
+	if consumeFrom := r.URL.Query().Get("consumeFrom"); consumeFrom != "" && consumeFrom != "smallest" && consumeFrom != "largest" {
+		http.Error(w, "Invalid consumeFrom", http.StatusBadRequest)
+		return
 	}
 
-	// expect username test
-	// expect component BROKER
-	assert.Equal(t, res.Username, "test", "Expected username to be test")
-	assert.Equal(t, res.Component, "BROKER", "Expected component to be BROKER")
-
+	fmt.Fprint(w, `{
+		"pauseFlag": false,
+		"consumingSegments": [],
+		"reasonCode": "ADMINISTRATIVE"
+	}`)
 }
 
-// Test CreateUser
-func TestCreateUser(t *testing.T) {
-
-	server := createMockControllerServer()
-	client := createPinotClient(server)
-
-	user := model.User{
-		Username:    "testUser",
-		Password:    "test",
-		Component:   "BROKER",
-		Role:        "ADMIN",
-		Permissions: &[]string{"READ"},
-		Tables:      &[]string{"my_table_OFFLINE"},
-	}
+func handlePauseStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"pauseFlag": true,
+		"consumingSegments": [],
+		"reasonCode": "ADMINISTRATIVE",
+		"comment": "maintenance",
+		"timestamp": "2024-04-12T21:07:10.094Z"
+	}`)
+}
 
-	userBytes, err := json.Marshal(user)
-	if err != nil {
-		t.Errorf("Couldn't marshal user: %v", err)
+func handleForceCommit(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("partitions") != "0,1" {
+		http.Error(w, "Invalid partitions", http.StatusBadRequest)
+		return
 	}
 
-	res, err := client.CreateUser(userBytes)
-	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
-	}
+	fmt.Fprint(w, `{
+		"forceCommitStatus": "SUCCESS",
+		"jobMetaZKWriteStatus": "SUCCESS",
+		"forceCommitJobId": "6c1d3f0a-8e2b-4b7d-9a5c-0f4e2d7b8a11"
+	}`)
+}
 
-	// expect User testUser_BROKER has been successfully added!
-	assert.Equal(t, res.Status, "User testUser_BROKER has been successfully added!", "Expected response to be User testUser_BROKER has been successfully added!")
+func handleForceCommitStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"jobId": "6c1d3f0a-8e2b-4b7d-9a5c-0f4e2d7b8a11",
+		"jobType": "FORCE_COMMIT",
+		"tableName": "test_REALTIME",
+		"submissionTimeMs": "1712959630094",
+		"segmentsForceCommitted": "[\"test__0__1__20240412T2107Z\",\"test__1__1__20240412T2107Z\"]",
+		"segmentsYetToBeCommitted": [],
+		"numberOfSegmentsYetToBeCommitted": 0
+	}`)
 }
 
-// Test UpdateUser
-func TestUpdateUser(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+func handleConsumingSegmentsInfo(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"serversFailingToRespond": 0,
+		"serversUnparsableRespond": 0,
+		"_segmentToConsumingInfoMap": {
+			"test__0__1__20240412T2107Z": [
+				{
+					"serverName": "Server_172.17.0.3_7050",
+					"consumerState": "CONSUMING",
+					"lastConsumedTimestamp": 1712959630094,
+					"partitionToOffsetMap": {"0": "150"},
+					"partitionOffsetInfo": {
+						"currentOffsetsMap": {"0": "150"},
+						"latestUpstreamOffsetMap": {"0": "200"},
+						"recordsLagMap": {"0": "50"},
+						"availabilityLagMsMap": {"0": "1200"}
+					}
+				}
+			]
+		}
+	}`)
+}
 
-	user := model.User{
+func handleAirlineStatsConsumingSegmentsInfo(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"serversFailingToRespond": 0,
+		"serversUnparsableRespond": 0,
+		"_segmentToConsumingInfoMap": {
+			"airlineStats__0__3__20240412T2107Z": [
+				{
+					"serverName": "Server_172.17.0.3_7050",
+					"consumerState": "CONSUMING",
+					"lastConsumedTimestamp": 1712959630094,
+					"partitionToOffsetMap": {"0": "9900"},
+					"partitionOffsetInfo": {
+						"currentOffsetsMap": {"0": "9900"},
+						"latestUpstreamOffsetMap": {"0": "10000"},
+						"recordsLagMap": {"0": "100"},
+						"availabilityLagMsMap": {"0": "2500"}
+					}
+				},
+				{
+					"serverName": "Server_172.17.0.4_7050",
+					"consumerState": "CONSUMING",
+					"lastConsumedTimestamp": 1712959570094,
+					"partitionToOffsetMap": {"0": "4000"},
+					"partitionOffsetInfo": {
+						"currentOffsetsMap": {"0": "4000"},
+						"latestUpstreamOffsetMap": {"0": "10000"},
+						"recordsLagMap": {"0": "6000"},
+						"availabilityLagMsMap": {"0": "62500"}
+					}
+				}
+			],
+			"airlineStats__1__3__20240412T2107Z": [
+				{
+					"serverName": "Server_172.17.0.3_7050",
+					"consumerState": "NOT_CONSUMING",
+					"lastConsumedTimestamp": 1712959630094,
+					"partitionToOffsetMap": {"1": "500"},
+					"partitionOffsetInfo": {
+						"currentOffsetsMap": {"1": "500"},
+						"latestUpstreamOffsetMap": {"1": "520"},
+						"recordsLagMap": {},
+						"availabilityLagMsMap": {}
+					}
+				}
+			]
+		}
+	}`)
+}
+
+func handleAirlineStatsZKMetadata(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"airlineStats__0__3__20240412T2107Z": {
+			"segment.creation.time": "1712959000000",
+			"segment.realtime.startOffset": "8000",
+			"segment.realtime.status": "IN_PROGRESS",
+			"segment.realtime.numReplicas": "2"
+		},
+		"airlineStats__1__3__20240412T2107Z": {
+			"segment.creation.time": "1712959000000",
+			"segment.realtime.startOffset": "400",
+			"segment.realtime.status": "IN_PROGRESS",
+			"segment.realtime.numReplicas": "1"
+		}
+	}`)
+}
+
+func handleAirlineStatsExternalView(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"OFFLINE": null,
+		"REALTIME": {
+			"airlineStats__0__1__20240412T2107Z": {
+				"Server_172.17.0.3_7050": "ONLINE",
+				"Server_172.17.0.4_7050": "ERROR"
+			},
+			"airlineStats__0__2__20240412T2207Z": {
+				"Server_172.17.0.3_7050": "CONSUMING",
+				"Server_172.17.0.4_7050": "CONSUMING"
+			}
+		}
+	}`)
+}
+
+func handleGetAirlineStatsSize(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{
+		"tableName": "airlineStats",
+		"reportedSizeInBytes": 4000,
+		"estimatedSizeInBytes": 4000,
+		"reportedSizePerReplicaInBytes": 2000,
+		"offlineSegments": null,
+		"realtimeSegments": {
+			"reportedSizeInBytes": 4000,
+			"estimatedSizeInBytes": 4000,
+			"missingSegments": 0,
+			"reportedSizePerReplicaInBytes": 2000,
+			"segments": {
+				"airlineStats__0__1__20240412T2107Z": {
+					"reportedSizeInBytes": 2000,
+					"estimatedSizeInBytes": 2000,
+					"maxReportedSizePerReplicaInBytes": 1000,
+					"serverInfo": {
+						"Server_172.17.0.3_7050": {"segmentName": "airlineStats__0__1__20240412T2107Z", "diskSizeInBytes": 1000},
+						"Server_172.17.0.4_7050": {"segmentName": "airlineStats__0__1__20240412T2107Z", "diskSizeInBytes": 1000}
+					}
+				},
+				"airlineStats__1__1__20240412T2107Z": {
+					"reportedSizeInBytes": 2000,
+					"estimatedSizeInBytes": 2000,
+					"maxReportedSizePerReplicaInBytes": 2000,
+					"serverInfo": {
+						"Server_172.17.0.3_7050": {"segmentName": "airlineStats__1__1__20240412T2107Z", "diskSizeInBytes": 2000}
+					}
+				}
+			}
+		}
+	}`)
+}
+
+func handleGetLeadersForAllTables(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"leadControllerResourceEnabled": true, "leadControllerEntryMap": {"leadControllerResource_0": {"tableNames": ["airlineStats"], "leadControllerId": "Controller_172.17.0.2_9000"}, "leadControllerResource_7": {"tableNames": ["test"], "leadControllerId": "Controller_172.17.0.2_9000"}}}`)
+}
+
+func handleGetLeaderForTable(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"leadControllerResourceEnabled": true, "leadControllerEntryMap": {"leadControllerResource_7": {"tableNames": ["test"], "leadControllerId": "Controller_172.17.0.2_9000"}}}`)
+}
+
+// testSchema is the schema of the test table the mock controller starts with
+const testSchema = `{"schemaName": "test","enableColumnBasedNullHandling": false,"dimensionFieldSpecs": [{"name": "id","dataType": "STRING","notNull": false},{"name": "type","dataType": "STRING","notNull": false},{"name": "actor","dataType": "JSON","notNull": false},{"name": "repo","dataType": "JSON","notNull": false},{"name": "payload","dataType": "JSON","notNull": false},{"name": "public","dataType": "BOOLEAN","notNull": false}],"dateTimeFieldSpecs": [{"name": "created_at","dataType": "STRING","notNull": false,"format": "1:SECONDS:SIMPLE_DATE_FORMAT:yyyy-MM-dd'T'HH:mm:ss'Z'","granularity": "1:SECONDS"},{"name": "created_at_timestamp","dataType": "TIMESTAMP","notNull": false,"format": "1:MILLISECONDS:TIMESTAMP","granularity": "1:SECONDS"}]}`
+
+// fixtures serve the endpoints the fake controller doesn't model
+var fixtures = []struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}{
+	{http.MethodGet, RouteSchemasFieldSpec, handleGetFieldSpecs},
+	{http.MethodGet, RouteTablesTestIndexes, handleTableIndexes},
+	{http.MethodGet, RouteTablesLiveBrokers, handleGetTableLiveBrokers},
+	{http.MethodGet, RouteTablesTestLiveBrokers, handleGetTableTestLiveBrokers},
+	{http.MethodGet, RouteTablesTestMetadata, handleGetTableTestMetadata},
+	{http.MethodPost, RouteTablesTestRebuildBrokerResourceFromHelixTags, handleTableRebuildBrokerResourceFromHelixTags},
+	{http.MethodGet, RouteTablesTestSize, handleGetTableSize},
+	{http.MethodGet, RouteTablesTestStats, handleGetTableStats},
+	{http.MethodPost, RouteSegmentTestReset, handleResetTableSegment},
+	{http.MethodPost, RouteSegmentTestResetAll, handleResetTableSegments},
+	{http.MethodGet, RouteSegmentTestTiers, handleGetSegmentTiers},
+	{http.MethodGet, RouteSegmentTestCRC, handleGetSegmentCRC},
+	{http.MethodGet, RouteSegmentTestMetadata, handleGetSegmentMetadata},
+	{http.MethodGet, RouteSegmentTestZKMetadata, handleGetSegmentZKMetadata},
+	{http.MethodGet, RouteTasksTaskTypes, handleGetTaskTypes},
+	{http.MethodPost, RouteTasksSchedule, handleScheduleTasks},
+	{http.MethodGet, RouteTasksMergeRollupTasks, handleGetTasks},
+	{http.MethodGet, RouteTasksMergeRollupTaskStates, handleGetTaskStates},
+	{http.MethodGet, RouteTasksMergeRollupTableState, handleGetTaskStates},
+	{http.MethodGet, RouteTasksMergeRollupState, handleGetTaskQueueState},
+	{http.MethodPut, RouteTasksMergeRollupStop, handleStopTasks},
+	{http.MethodPut, RouteTasksMergeRollupResume, handleResumeTasks},
+	{http.MethodPut, RouteTasksMergeRollupCleanup, handleCleanupTasks},
+	{http.MethodDelete, RouteTasksMergeRollup, handleDeleteTasks},
+	{http.MethodGet, RouteTasksMergeRollupDebug, handleGetTasksDebugInfo},
+	{http.MethodDelete, RouteTasksTask, handleDeleteTask},
+	{http.MethodGet, RouteTasksTaskState, handleGetTaskState},
+	{http.MethodGet, RouteTasksSubtaskConfig, handleGetSubtaskConfigs},
+	{http.MethodGet, RouteTasksTaskDebug, handleGetTaskDebugInfo},
+	{http.MethodGet, RouteTasksSubtaskProgress, handleGetSubtaskProgress},
+	{http.MethodGet, RouteTasksGeneratorDebug, handleGetTaskGeneratorDebugInfo},
+	{http.MethodPost, RouteTenantsRebalance, handleRebalanceTenant},
+	{http.MethodGet, RouteTenantsRebalanceStatus, handleGetTenantRebalanceStatus},
+	{http.MethodPost, RouteTablesTestPauseConsumption, handlePauseConsumption},
+	{http.MethodPost, RouteTablesTestResumeConsumption, handleResumeConsumption},
+	{http.MethodGet, RouteTablesTestPauseStatus, handlePauseStatus},
+	{http.MethodPost, RouteTablesTestForceCommit, handleForceCommit},
+	{http.MethodGet, RouteTablesForceCommitStatus, handleForceCommitStatus},
+	{http.MethodGet, RouteTablesTestConsumingSegmentsInfo, handleConsumingSegmentsInfo},
+	{http.MethodGet, RouteLeaderTables, handleGetLeadersForAllTables},
+	{http.MethodGet, RouteLeaderTablesTest, handleGetLeaderForTable},
+}
+
+// testTableConfig is the config of the test table the mock controller starts with
+const testTableConfig = `
+	{
+		"tableName": "test",
+		"tableType": "OFFLINE",
+		"segmentsConfig": {
+			"timeColumnName": "DaysSinceEpoch",
+			"replication": "1",
+			"timeType": "DAYS",
+			"minimizeDataMovement": false,
+			"segmentAssignmentStrategy": "BalanceNumSegmentAssignmentStrategy",
+			"segmentPushType": "APPEND"
+		},
+		"tenants": {
+			"broker": "DefaultTenant",
+			"server": "DefaultTenant"
+		},
+		"tableIndexConfig": {
+			"enableDefaultStarTree": false,
+			"starTreeIndexConfigs": [
+				{
+					"dimensionsSplitOrder": [
+						"AirlineID",
+						"Origin",
+						"Dest"
+					],
+					"functionColumnPairs": [
+						"COUNT__*",
+						"MAX__ArrDelay"
+					],
+					"maxLeafRecords": 10
+				}
+			],
+			"tierOverwrites": {
+				"hotTier": {
+					"starTreeIndexConfigs": [
+						{
+							"dimensionsSplitOrder": [
+								"Carrier",
+								"CancellationCode",
+								"Origin",
+								"Dest"
+							],
+							"skipStarNodeCreationForDimensions": [],
+							"functionColumnPairs": [
+								"MAX__CarrierDelay",
+								"AVG__CarrierDelay"
+							],
+							"maxLeafRecords": 10
+						}
+					]
+				},
+				"coldTier": {
+					"starTreeIndexConfigs": []
+				}
+			},
+			"enableDynamicStarTreeCreation": true,
+			"aggregateMetrics": false,
+			"nullHandlingEnabled": false,
+			"columnMajorSegmentBuilderEnabled": false,
+			"optimizeDictionary": false,
+			"optimizeDictionaryForMetrics": false,
+			"noDictionarySizeRatioThreshold": 0.85,
+			"rangeIndexVersion": 2,
+			"autoGeneratedInvertedIndex": false,
+			"createInvertedIndexDuringSegmentGeneration": false,
+			"loadMode": "MMAP"
+		},
+		"metadata": {
+			"customConfigs": {}
+		},
+		"fieldConfigList": [
+			{
+				"name": "ts",
+				"encodingType": "DICTIONARY",
+				"indexType": "TIMESTAMP",
+				"indexTypes": [
+					"TIMESTAMP"
+				],
+				"timestampConfig": {
+					"granularities": [
+						"DAY",
+						"WEEK",
+						"MONTH"
+					]
+				},
+				"indexes": null,
+				"tierOverwrites": null
+			},
+			{
+				"name": "ArrTimeBlk",
+				"encodingType": "DICTIONARY",
+				"indexTypes": [],
+				"indexes": {
+					"inverted": {
+						"enabled": "true"
+					}
+				},
+				"tierOverwrites": {
+					"hotTier": {
+						"encodingType": "DICTIONARY",
+						"indexes": {
+							"bloom": {
+								"enabled": "true"
+							}
+						}
+					},
+					"coldTier": {
+						"encodingType": "RAW",
+						"indexes": {
+							"text": {
+								"enabled": "true"
+							}
+						}
+					}
+				}
+			}
+		],
+		"ingestionConfig": {
+			"segmentTimeValueCheck": true,
+			"transformConfigs": [
+				{
+					"columnName": "ts",
+					"transformFunction": "fromEpochDays(DaysSinceEpoch)"
+				},
+				{
+					"columnName": "tsRaw",
+					"transformFunction": "fromEpochDays(DaysSinceEpoch)"
+				}
+			],
+			"continueOnError": false,
+			"rowTimeValueCheck": false
+		},
+		"tierConfigs": [
+			{
+				"name": "hotTier",
+				"segmentSelectorType": "time",
+				"segmentAge": "3130d",
+				"storageType": "pinot_server",
+				"serverTag": "DefaultTenant_OFFLINE"
+			},
+			{
+				"name": "coldTier",
+				"segmentSelectorType": "time",
+				"segmentAge": "3140d",
+				"storageType": "pinot_server",
+				"serverTag": "DefaultTenant_OFFLINE"
+			}
+		],
+		"isDimTable": false
+	}`
+
+// createMockController starts a fake controller with the test schema and table, the test table has one
+// segment on the first of two servers of DefaultTenant. Endpoints the fake doesn't model are served from
+// fixtures
+func createMockController(t *testing.T) *pinottest.Controller {
+	t.Helper()
+
+	controller := pinottest.NewController(pinottest.Options{Authorization: "Basic YWRtaW46YWRtaW4K"})
+	t.Cleanup(controller.Close)
+
+	for _, fixture := range fixtures {
+		controller.Handle(fixture.method, fixture.path, fixture.handler)
+	}
+
+	client := createPinotClient(controller.URL)
+	seed := func(_ any, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Couldn't seed the mock controller: %v", err)
+		}
+	}
+
+	seed(client.CreateSchemaFromBytes([]byte(testSchema)))
+	seed(client.CreateTable([]byte(testTableConfig)))
+
+	for _, instance := range []model.Instance{
+		{Host: "172.17.0.3", Port: 7050, Type: "SERVER", GrpcPort: 8090, AdminPort: 8097, QueryServicePort: 8421, QueryMailboxPort: 8842, Tags: []string{"DefaultTenant_OFFLINE", "DefaultTenant_REALTIME"}},
+		{Host: "172.17.0.4", Port: 7050, Type: "SERVER", GrpcPort: 8090, AdminPort: 8097, QueryServicePort: 8421, QueryMailboxPort: 8842, Tags: []string{"DefaultTenant_OFFLINE", "DefaultTenant_REALTIME"}},
+		{Host: "cdba1ba98e74", Port: 8099, Type: "BROKER", Tags: []string{"DefaultTenant_BROKER"}},
+		{Host: "172.19.0.2", Port: 9514, Type: "MINION", Tags: []string{"minion_untagged"}},
+	} {
+		instanceBytes, err := json.Marshal(instance)
+		seed(nil, err)
+		seed(client.CreateInstance(instanceBytes))
+	}
+
+	for _, server := range []string{"Server_172.17.0.3_7050", "Server_172.17.0.4_7050"} {
+		seed(nil, controller.SetSystemResourceInfo(server, model.SystemResourceInfo{NumCores: "8", TotalMemoryMB: "15972", MaxHeapSizeMB: "4096"}))
+	}
+
+	seed(nil, controller.AddSegment("test_OFFLINE", "test_OFFLINE_0"))
+	seed(client.CreateUser(model.User{Username: "test", Password: "test", Component: model.UserComponentBroker, Role: model.UserRoleAdmin}))
+
+	return controller
+}
+
+// createAirlineStatsController starts the mock controller with the airlineStats realtime table on the servers
+// of airlineTenant. The second server is disabled and, like the fixtures of the external view, has a segment
+// in ERROR state and one segment missing
+func createAirlineStatsController(t *testing.T) *pinottest.Controller {
+	t.Helper()
+
+	controller := createMockController(t)
+	controller.Handle(http.MethodGet, RouteTablesAirlineStatsExternalView, handleAirlineStatsExternalView)
+	controller.Handle(http.MethodGet, RouteTablesAirlineStatsSize, handleGetAirlineStatsSize)
+	controller.Handle(http.MethodGet, RouteTablesAirlineStatsConsumingSegmentsInfo, handleAirlineStatsConsumingSegmentsInfo)
+	controller.Handle(http.MethodGet, RouteSegmentAirlineStatsZKMetadata, handleAirlineStatsZKMetadata)
+
+	client := createPinotClient(controller.URL)
+	seed := func(_ any, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Couldn't seed the mock controller: %v", err)
+		}
+	}
+
+	seed(client.CreateTenantFromRequest(model.TenantRequest{TenantRole: model.TenantRoleServer, TenantName: "airlineTenant", NumberOfInstances: 2, RealtimeInstances: 2}))
+	for _, server := range []string{"Server_172.17.0.3_7050", "Server_172.17.0.4_7050"} {
+		seed(client.UpdateInstanceTags(server, []string{"DefaultTenant_OFFLINE", "DefaultTenant_REALTIME", "airlineTenant_REALTIME"}, false))
+	}
+
+	schema := getSchema()
+	schema.SchemaName = "airlineStats"
+	seed(client.CreateSchema(schema))
+
+	tableBytes, err := json.Marshal(model.Table{
+		TableName: "airlineStats",
+		TableType: "REALTIME",
+		SegmentsConfig: model.TableSegmentsConfig{
+			TimeColumnName:       "DaysSinceEpoch",
+			TimeType:             "DAYS",
+			Replication:          "1",
+			ReplicasPerPartition: "2",
+			SchemaName:           "airlineStats",
+		},
+		Tenants: model.TableTenant{Broker: "DefaultTenant", Server: "airlineTenant"},
+	})
+	seed(nil, err)
+	seed(client.CreateTable(tableBytes))
+
+	for _, segment := range []string{"airlineStats__0__1__20240412T2107Z", "airlineStats__0__2__20240412T2207Z", "airlineStats__1__1__20240412T2107Z"} {
+		seed(nil, controller.AddSegment("airlineStats_REALTIME", segment))
+	}
+	seed(client.DisableInstance("Server_172.17.0.4_7050"))
+
+	return controller
+}
+
+func createPinotClient(controllerUrl string) *goPinotAPI.PinotAPIClient {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	return goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controllerUrl),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.Logger(logger),
+	)
+}
+
+func getSchema() model.Schema {
+
+	schemaFilePath := "./example/data-gen/block_header_schema.json"
+
+	f, err := os.Open(schemaFilePath)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	defer f.Close()
+
+	var schema model.Schema
+	err = json.NewDecoder(f).Decode(&schema)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	return schema
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// TestFetchData
+// func TestFetchData(t *testing.T) {
+
+// }
+
+// test FetchData unauthorized
+
+// Test GetUsers
+func TestGetUsers(t *testing.T) {
+
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetUsers()
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// expect 1 user in the response username test
+	assert.Equal(t, len(res.Users), 1, "Expected 1 user in the response")
+	assert.Equal(t, res.Users["test_BROKER"].Username, "test", "Expected username to be test")
+
+}
+
+// Test GetUser
+func TestGetUser(t *testing.T) {
+
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetUser("test", "BROKER")
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// expect username test
+	// expect component BROKER
+	assert.Equal(t, res.Username, "test", "Expected username to be test")
+	assert.Equal(t, res.Component, model.UserComponentBroker, "Expected component to be BROKER")
+
+}
+
+// Test CreateUser
+func TestCreateUser(t *testing.T) {
+
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	user := model.User{
+		Username:    "testUser",
+		Password:    "test",
+		Component:   "BROKER",
+		Role:        model.UserRoleAdmin,
+		Permissions: []model.Permission{model.PermissionRead},
+		Tables:      []string{"my_table_OFFLINE"},
+	}
+
+	res, err := client.CreateUser(user)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// expect User testUser_BROKER has been successfully added!
+	assert.Equal(t, res.Status, "User testUser_BROKER has been successfully added!", "Expected response to be User testUser_BROKER has been successfully added!")
+}
+
+// Test UpdateUser
+func TestUpdateUser(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	user := model.User{
 		Username:  "test",
 		Password:  "test",
 		Component: "BROKER",
@@ -3988,8 +3399,8 @@ func TestUpdateUser(t *testing.T) {
 
 // Test DeleteUser
 func TestDeleteUser(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.DeleteUser("test", "BROKER")
 	if err != nil {
@@ -4002,19 +3413,17 @@ func TestDeleteUser(t *testing.T) {
 }
 
 func TestDeleteUserNoComponent(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	_, err := client.DeleteUser("test", "")
-	if err != nil {
-		assert.Equal(t, err.Error(), "client: request failed: status 400\n{\"code\": 400,\"error\": \"Name is null\"}\n", "Expected error to be Name is null")
-	}
+	assert.ErrorContains(t, err, "User test_ does not exist", "Expected a user without a component not to be found")
 
 }
 
 func TestGetInstances(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetInstances()
 	if err != nil {
@@ -4025,8 +3434,8 @@ func TestGetInstances(t *testing.T) {
 }
 
 func TestGetInstance(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetInstance("Minion_172.19.0.2_9514")
 	if err != nil {
@@ -4037,8 +3446,8 @@ func TestGetInstance(t *testing.T) {
 }
 
 func TestCreateInstance(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	instance := model.Instance{
 		Host: "localhost",
@@ -4063,8 +3472,8 @@ func TestCreateInstance(t *testing.T) {
 // DeleteInstance
 
 func TestGetClusterInfo(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetClusterInfo()
 	if err != nil {
@@ -4075,8 +3484,8 @@ func TestGetClusterInfo(t *testing.T) {
 }
 
 func TestGetClusterConfigs(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetClusterConfigs()
 	if err != nil {
@@ -4087,8 +3496,8 @@ func TestGetClusterConfigs(t *testing.T) {
 }
 
 func TestUpdateClusterConfig(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	config := model.ClusterConfig{
 		AllowParticipantAutoJoin: "false",
@@ -4109,8 +3518,8 @@ func TestUpdateClusterConfig(t *testing.T) {
 }
 
 func TestDeleteClusterConfig(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.DeleteClusterConfig("allowParticipantAutoJoin")
 	if err != nil {
@@ -4121,8 +3530,8 @@ func TestDeleteClusterConfig(t *testing.T) {
 }
 
 func TestGetTenants(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTenants()
 	if err != nil {
@@ -4136,8 +3545,8 @@ func TestGetTenants(t *testing.T) {
 
 // TestGetTenantInstances
 func TestGetTenantInstances(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTenantInstances("DefaultTenant")
 	if err != nil {
@@ -4145,27 +3554,27 @@ func TestGetTenantInstances(t *testing.T) {
 	}
 
 	assert.Equal(t, len(res.BrokerInstances), 1, "Expected 1 Broker instance in the response")
-	assert.Equal(t, len(res.ServerInstances), 1, "Expected 1 Server instance in the response")
+	assert.Equal(t, len(res.ServerInstances), 2, "Expected 2 Server instances in the response")
 	assert.Equal(t, res.TenantName, "DefaultTenant", "Expected tenant name to be DefaultTenant")
 }
 
 // TestGetTenantTables
 func TestGetTenantTables(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTenantTables("DefaultTenant")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	assert.Equal(t, len(res.Tables), 0, "Expected 0 tables in the response")
+	assert.Equal(t, res.Tables, []string{"test_OFFLINE"}, "Expected test_OFFLINE in the response")
 }
 
 // TestGetTenantMetadata
 func TestGetTenantMetadata(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTenantMetadata("DefaultTenant")
 	if err != nil {
@@ -4173,20 +3582,21 @@ func TestGetTenantMetadata(t *testing.T) {
 	}
 
 	assert.Equal(t, len(res.BrokerInstances), 1, "Expected 1 Broker instance in the response")
-	assert.Equal(t, len(res.ServerInstances), 1, "Expected 1 Server instance in the response")
-	assert.Equal(t, res.OfflineServerInstances, []string([]string(nil)), "Expected OfflineServerInstances to be nil")
-	assert.Equal(t, res.RealtimeServerInstances, []string([]string(nil)), "Expected RealtimeServerInstances to be nil")
+	assert.Equal(t, len(res.ServerInstances), 2, "Expected 2 Server instances in the response")
+	assert.Equal(t, res.OfflineServerInstances, []string{"Server_172.17.0.3_7050", "Server_172.17.0.4_7050"}, "Expected both servers to be tagged for offline tables")
+	assert.Equal(t, res.RealtimeServerInstances, []string{"Server_172.17.0.3_7050", "Server_172.17.0.4_7050"}, "Expected both servers to be tagged for realtime tables")
 	assert.Equal(t, res.TenantName, "DefaultTenant", "Expected tenant name to be DefaultTenant")
 }
 
 // TestCreateTenant
 func TestCreateTenant(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
-	tenant := model.Tenant{
-		TenantName: "test",
-		TenantRole: "BROKER",
+	tenant := model.TenantRequest{
+		TenantName:        "test",
+		TenantRole:        "BROKER",
+		NumberOfInstances: 1,
 	}
 
 	tenantBytes, err := json.Marshal(tenant)
@@ -4204,12 +3614,15 @@ func TestCreateTenant(t *testing.T) {
 
 // TestUpdateTenant
 func TestUpdateTenant(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
-
-	updateTenant := model.Tenant{
-		TenantName: "test",
-		TenantRole: "SERVER",
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	updateTenant := model.TenantRequest{
+		TenantName:        "DefaultTenant",
+		TenantRole:        "SERVER",
+		NumberOfInstances: 2,
+		OfflineInstances:  2,
+		RealtimeInstances: 2,
 	}
 
 	tenantBytes, err := json.Marshal(updateTenant)
@@ -4227,23 +3640,41 @@ func TestUpdateTenant(t *testing.T) {
 
 // TestDeleteTenant
 func TestDeleteTenant(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.DeleteTenant("DefaultTenant", "SERVER")
+	assert.ErrorContains(t, err, "it is used by tables: test_OFFLINE", "Expected a tenant with tables not to be deleted")
+
+	_, err = client.CreateTenantFromRequest(model.TenantRequest{TenantRole: model.TenantRoleServer, TenantName: "test", NumberOfInstances: 1, OfflineInstances: 1})
+	assert.NoError(t, err)
 
-	res, err := client.DeleteTenant("DefaultTenant", "SERVER")
+	res, err := client.DeleteTenant("test", "SERVER")
 	if err != nil {
-		assert.Equal(t, err.Error(), "client: request failed: status 404\n404 page not found\n", "Expected error to be 404 page not found")
+		t.Errorf("Expected no error, got %v", err)
 	}
 
-	assert.Equal(t, res.Status, "Successfully deleted tenant DefaultTenant", "Expected response to be Successfully deleted tenant DefaultTenant")
+	assert.Equal(t, res.Status, "Successfully deleted tenant test", "Expected response to be Successfully deleted tenant test")
 }
 
 // TestRebalanceTenant
+func TestRebalanceTenant(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.RebalanceTenant("DefaultTenant", model.TenantRebalanceConfig{DryRun: true})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.JobId, "4b7a7bd6-b4d4-4d3b-98a4-3e3b8a7e3f2d", "Expected a job id to be returned")
+	assert.Equal(t, res.RebalanceTableResults["test_OFFLINE"].Status, model.RebalanceStatusInProgress, "Expected test_OFFLINE to be in progress")
+}
 
 // TestSegments
 func TestSegments(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetSegments("test")
 	if err != nil {
@@ -4251,40 +3682,47 @@ func TestSegments(t *testing.T) {
 	}
 
 	assert.Equal(t, len(res[0].Offline), 1, "Expected 1 offline segment in the response")
-	assert.Equal(t, len(res[0].Realtime), 1, "Expected 1 realtime segment in the response")
+	assert.Equal(t, len(res[0].Realtime), 0, "Expected no realtime segments in the response")
 }
 
 // TestReloadTableSegments
 func TestReloadTableSegments(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.ReloadTableSegments("test")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	assert.Equal(t, res.Status, "{\"test_OFFLINE\":{\"reloadJobId\":\"f9db13c7-3ad6-45a8-a08f-75cd03c42fb5\",\"reloadJobMetaZKStorageStatus\":\"SUCCESS\",\"numMessagesSent\":\"1\"}}", "Expected response to be {\"test_OFFLINE\":{\"reloadJobId\":\"f9db13c7-3ad6-45a8-a08f-75cd03c42fb5\",\"reloadJobMetaZKStorageStatus\":\"SUCCESS\",\"numMessagesSent\":\"1\"}}")
+	jobs, err := model.ParseReloadJobs(res.Status)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.NotEmpty(t, jobs["test_OFFLINE"].ReloadJobId, "Expected a reload job for test_OFFLINE")
+	assert.Equal(t, jobs["test_OFFLINE"].ReloadJobMetaZKStorageStatus, "SUCCESS", "Expected the job meta to be stored")
+	assert.Equal(t, jobs["test_OFFLINE"].NumMessagesSent.String(), "1", "Expected 1 reload message to be sent")
 
 }
 
 // TestReloadTableSegment
 func TestReloadTableSegment(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
-	res, err := client.ReloadSegment("test", "test_1")
+	res, err := client.ReloadSegment("test", "test_OFFLINE_0")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	assert.Equal(t, res.Status, "Submitted reload job id: ce4650a9-774a-4b22-919b-4cb22b5c8129, sent 1 reload messages. Job meta ZK storage status: SUCCESS", "Expected response to be Submitted reload job id: ce4650a9-774a-4b22-919b-4cb22b5c8129, sent 1 reload messages. Job meta ZK storage status: SUCCESS")
+	assert.Regexp(t, "^Submitted reload job id: [0-9a-f-]+, sent 1 reload messages. Job meta ZK storage status: SUCCESS$", res.Status, "Expected a reload job to be submitted")
 }
 
 // TestGetSchemas
 func TestGetSchemas(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	schemas := []model.Schema{}
 
@@ -4307,8 +3745,8 @@ func TestGetSchemas(t *testing.T) {
 
 // TestGetSchema
 func TestGetSchema(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetSchema("test")
 	if err != nil {
@@ -4321,11 +3759,11 @@ func TestGetSchema(t *testing.T) {
 // TestCreateSchema
 // it appears that this is not returning the status...
 func TestCreateSchema(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	schema := model.Schema{
-		SchemaName: "test",
+		SchemaName: "flights",
 		DimensionFieldSpecs: []model.FieldSpec{
 			{
 				Name:     "test",
@@ -4357,14 +3795,14 @@ func TestCreateSchema(t *testing.T) {
 
 	// fmt.Println("Response: ", res.Status)
 
-	assert.Equal(t, res.Status, "test successfully added", "Expected response to be test successfully added")
+	assert.Equal(t, res.Status, "flights successfully added", "Expected response to be flights successfully added")
 
 }
 
 // // TestCreateSchemaFromFile
 // func TestCreateSchemaFromFile(t *testing.T) {
-// 	server := createMockControllerServer()
-// 	client := createPinotClient(server)
+// 	controller := createMockController(t)
+// 	client := createPinotClient(controller.URL)
 
 // 	res, err := client.CreateSchemaFromFile("test")
 // 	if err != nil {
@@ -4376,8 +3814,8 @@ func TestCreateSchema(t *testing.T) {
 
 // TestUpdateSchema
 func TestUpdateSchema(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	schema := model.Schema{
 		SchemaName: "test",
@@ -4410,11 +3848,35 @@ func TestUpdateSchema(t *testing.T) {
 	assert.Equal(t, res.Status, "test successfully added", "Expected response to be test successfully added")
 }
 
-// TestDeleteSchema
-// Requires /tables to be implemented first
+// TestCreateAndUpdateSchemaTyped
+func TestCreateAndUpdateSchemaTyped(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	schema := model.Schema{
+		SchemaName: "flights",
+		DimensionFieldSpecs: []model.FieldSpec{
+			{
+				Name:     "test",
+				DataType: "STRING",
+			},
+		},
+	}
+
+	res, err := client.CreateSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, "flights successfully added", res.Status)
+
+	res, err = client.UpdateSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, "flights successfully added", res.Status)
+}
+
+// TestDeleteSchema
+// Requires /tables to be implemented first
 // func TestDeleteSchema(t *testing.T) {
-// 	server := createMockControllerServer()
-// 	client := createPinotClient(server)
+// 	controller := createMockController(t)
+// 	client := createPinotClient(controller.URL)
 
 // 	res, err := client.DeleteSchema("test")
 // 	if err != nil {
@@ -4426,8 +3888,8 @@ func TestUpdateSchema(t *testing.T) {
 
 // TestValidateSchema
 func TestValidateSchema(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	schema := model.Schema{
 		SchemaName: "test",
@@ -4459,8 +3921,8 @@ func TestValidateSchema(t *testing.T) {
 }
 
 func TestGetTables(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTables()
 	if err != nil {
@@ -4471,8 +3933,8 @@ func TestGetTables(t *testing.T) {
 }
 
 func TestGetOfflineTable(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTable("test")
 	if err != nil {
@@ -4484,21 +3946,21 @@ func TestGetOfflineTable(t *testing.T) {
 }
 
 func TestGetRealtimeTable(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createAirlineStatsController(t)
+	client := createPinotClient(controller.URL)
 
-	res, err := client.GetTable("test")
+	res, err := client.GetTable("airlineStats")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	assert.Equal(t, res.REALTIME.TableName, "realtime_ethereum_mainnet_block_headers_REALTIME", "Expected table name to be test")
+	assert.Equal(t, res.REALTIME.TableName, "airlineStats_REALTIME", "Expected table name to be airlineStats_REALTIME")
 
 }
 
 func TestCreateTable(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	// Create Schema First
 	schema := model.Schema{
@@ -4616,12 +4078,12 @@ func TestCreateTable(t *testing.T) {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	assert.Equal(t, res.Status, "Table test_OFFLINE successfully added", "Expected response to be Table test_OFFLINE successfully added")
+	assert.Equal(t, res.Status, "Table ethereum_mainnet_block_headers_OFFLINE successfully added", "Expected response to be Table ethereum_mainnet_block_headers_OFFLINE successfully added")
 }
 
 func TestUpdateTable(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	updateTable := model.Table{
 		TableName: "test",
@@ -4690,8 +4152,8 @@ func TestUpdateTable(t *testing.T) {
 }
 
 func TestDeleteTable(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.DeleteTable("test")
 	if err != nil {
@@ -4702,8 +4164,8 @@ func TestDeleteTable(t *testing.T) {
 }
 
 func TestGetEmptyTable(t *testing.T) {
-	// server := createMockControllerServer()
-	// client := createPinotClient(server)
+	// controller := createMockController(t)
+	// client := createPinotClient(controller.URL)
 
 	emptyTable := model.Table{}
 
@@ -4712,8 +4174,8 @@ func TestGetEmptyTable(t *testing.T) {
 }
 
 func TestGetNonEmptyTable(t *testing.T) {
-	// server := createMockControllerServer()
-	// client := createPinotClient(server)
+	// controller := createMockController(t)
+	// client := createPinotClient(controller.URL)
 
 	table := model.Table{
 		TableName: "test",
@@ -4859,8 +4321,8 @@ func TestMultipleAuthTypes(t *testing.T) {
 }
 
 func TestPinotControllerAdmin(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.CheckPinotControllerAdminHealth()
 	if err != nil {
@@ -4871,8 +4333,8 @@ func TestPinotControllerAdmin(t *testing.T) {
 }
 
 func TestPinotHealth(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.CheckPinotControllerHealth()
 	if err != nil {
@@ -4883,8 +4345,8 @@ func TestPinotHealth(t *testing.T) {
 }
 
 func TestGetFieldSpecs(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetSchemaFieldSpecs()
 	if err != nil {
@@ -4896,8 +4358,8 @@ func TestGetFieldSpecs(t *testing.T) {
 }
 
 func TestGetTableExternalView(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableExternalView("test")
 	if err != nil {
@@ -4908,8 +4370,8 @@ func TestGetTableExternalView(t *testing.T) {
 }
 
 func TestGetTableIdealState(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableIdealState("test")
 	if err != nil {
@@ -4920,8 +4382,8 @@ func TestGetTableIdealState(t *testing.T) {
 }
 
 func TestGetTableIndexes(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableIndexes("test")
 	if err != nil {
@@ -4933,8 +4395,8 @@ func TestGetTableIndexes(t *testing.T) {
 }
 
 func TestGetTableInstances(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableInstances("test")
 	if err != nil {
@@ -4942,12 +4404,12 @@ func TestGetTableInstances(t *testing.T) {
 	}
 
 	assert.Equal(t, res.Brokers[0].TableType, "offline", "Expected broker_0 tableType to be offline")
-	assert.Equal(t, res.Brokers[0].Instances[0], "Broker_172.17.0.3_8000", "Expected broker_0 instance_0 to be Broker_172.17.0.3_8000")
+	assert.Equal(t, res.Brokers[0].Instances[0], "Broker_cdba1ba98e74_8099", "Expected broker_0 instance_0 to be Broker_cdba1ba98e74_8099")
 }
 
 func TestGetTableLiveBrokers(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetAllTableLiveBrokers()
 	if err != nil {
@@ -4966,8 +4428,8 @@ func TestGetTableLiveBrokers(t *testing.T) {
 }
 
 func TestGetTableTestLiveBrokers(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableLiveBrokers("test")
 	if err != nil {
@@ -4979,8 +4441,8 @@ func TestGetTableTestLiveBrokers(t *testing.T) {
 }
 
 func TestGetTableMetadata(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableMetadata("test")
 	if err != nil {
@@ -4991,8 +4453,8 @@ func TestGetTableMetadata(t *testing.T) {
 }
 
 func TestRebuildBrokerResourceFromHelixTags(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.RebuildBrokerResourceFromHelixTags("test")
 	if err != nil {
@@ -5003,8 +4465,8 @@ func TestRebuildBrokerResourceFromHelixTags(t *testing.T) {
 }
 
 func TestGetTableSchema(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableSchema("test")
 	if err != nil {
@@ -5015,8 +4477,8 @@ func TestGetTableSchema(t *testing.T) {
 }
 
 func TestGetTableSize(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableSize("test")
 	if err != nil {
@@ -5027,8 +4489,8 @@ func TestGetTableSize(t *testing.T) {
 }
 
 func TestGetTableState(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableState("test", "OFFLINE")
 	if err != nil {
@@ -5039,8 +4501,8 @@ func TestGetTableState(t *testing.T) {
 }
 
 func TestChangeTableState(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.ChangeTableState("test", "OFFLINE", "enable")
 	if err != nil {
@@ -5051,8 +4513,8 @@ func TestChangeTableState(t *testing.T) {
 }
 
 func TestGetTableStats(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetTableStats("test")
 	if err != nil {
@@ -5063,8 +4525,8 @@ func TestGetTableStats(t *testing.T) {
 }
 
 func TestResetTableSegment(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.ResetTableSegment("test_OFFLINE", "test_OFFLINE_16071_16071_0")
 	if err != nil {
@@ -5075,8 +4537,8 @@ func TestResetTableSegment(t *testing.T) {
 }
 
 func TestResetAllTableSegments(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.ResetTableSegments("test_OFFLINE")
 	if err != nil {
@@ -5087,8 +4549,8 @@ func TestResetAllTableSegments(t *testing.T) {
 }
 
 func TestGetSegmentTiers(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetSegmentTiers("test", "OFFLINE")
 	if err != nil {
@@ -5099,8 +4561,8 @@ func TestGetSegmentTiers(t *testing.T) {
 }
 
 func TestGetSegmentCRC(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetSegmentCRC("test")
 	if err != nil {
@@ -5111,8 +4573,8 @@ func TestGetSegmentCRC(t *testing.T) {
 }
 
 func TestGetSegmentMetadata(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetSegmentMetadata("test")
 	if err != nil {
@@ -5123,8 +4585,8 @@ func TestGetSegmentMetadata(t *testing.T) {
 }
 
 func TestGetSegmentZKMetadata(t *testing.T) {
-	server := createMockControllerServer()
-	client := createPinotClient(server)
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
 
 	res, err := client.GetSegmentZKMetadata("test")
 	if err != nil {
@@ -5133,3 +4595,695 @@ func TestGetSegmentZKMetadata(t *testing.T) {
 
 	assert.Equal(t, (*res)["test_OFFLINE_16071_16071_0"].SegmentTier, "coldTier", "Expected segment tier to be coldTier")
 }
+
+func TestGetTaskTypes(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTaskTypes()
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, len(*res), 2, "Expected 2 task types")
+	assert.Equal(t, (*res)[0], "MergeRollupTask", "Expected first task type to be MergeRollupTask")
+}
+
+func TestScheduleTasks(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ScheduleTasks("MergeRollupTask", "test_OFFLINE")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, (*res)["MergeRollupTask"], "Task_MergeRollupTask_1712959630094", "Expected task Task_MergeRollupTask_1712959630094 to be scheduled")
+}
+
+func TestGetTasks(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTasks("MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, (*res)[0], "Task_MergeRollupTask_1712959630094", "Expected task name to be Task_MergeRollupTask_1712959630094")
+}
+
+func TestGetTaskStates(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTaskStates("MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, (*res)["Task_MergeRollupTask_1712959630094"], model.TaskStateCompleted, "Expected task state to be COMPLETED")
+}
+
+func TestGetTableTaskStates(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTableTaskStates("MergeRollupTask", "test_OFFLINE")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, (*res)["Task_MergeRollupTask_1712959630094"], model.TaskStateCompleted, "Expected task state to be COMPLETED")
+}
+
+func TestGetTaskQueueState(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTaskQueueState("MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res, model.TaskStateInProgress, "Expected task queue state to be IN_PROGRESS")
+	assert.Equal(t, res.IsFinal(), false, "Expected IN_PROGRESS to not be final")
+}
+
+func TestGetTaskState(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTaskState("Task_MergeRollupTask_1712959630094")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res, model.TaskStateCompleted, "Expected task state to be COMPLETED")
+	assert.Equal(t, res.IsFinal(), true, "Expected COMPLETED to be final")
+}
+
+func TestGetSubtaskConfigs(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetSubtaskConfigs("Task_MergeRollupTask_1712959630094")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	subtask := (*res)["Task_MergeRollupTask_1712959630094_0"]
+	assert.Equal(t, subtask.TaskType, "MergeRollupTask", "Expected task type to be MergeRollupTask")
+	assert.Equal(t, subtask.Configs["mergeType"], "concat", "Expected merge type to be concat")
+}
+
+func TestGetSubtaskProgress(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetSubtaskProgress("Task_MergeRollupTask_1712959630094")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, (*res)["Task_MergeRollupTask_1712959630094_0"], "No status from worker: Minion_172.19.0.2_9514", "Expected progress to be reported for subtask")
+}
+
+func TestStopTasks(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.StopTasks("MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully stopped tasks for task type: MergeRollupTask", "Expected response to be Successfully stopped tasks for task type: MergeRollupTask")
+}
+
+func TestResumeTasks(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ResumeTasks("MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully resumed tasks for task type: MergeRollupTask", "Expected response to be Successfully resumed tasks for task type: MergeRollupTask")
+}
+
+func TestCleanupTasks(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.CleanupTasks("MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully cleaned up tasks for task type: MergeRollupTask", "Expected response to be Successfully cleaned up tasks for task type: MergeRollupTask")
+}
+
+func TestDeleteTasks(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.DeleteTasks("MergeRollupTask", false)
+	assert.Error(t, err, "Expected error when deleting running tasks without force")
+
+	res, err := client.DeleteTasks("MergeRollupTask", true)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully deleted tasks for task type: MergeRollupTask", "Expected response to be Successfully deleted tasks for task type: MergeRollupTask")
+}
+
+func TestDeleteTask(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.DeleteTask("Task_MergeRollupTask_1712959630094", false)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully deleted task: Task_MergeRollupTask_1712959630094", "Expected response to be Successfully deleted task: Task_MergeRollupTask_1712959630094")
+}
+
+func TestGetTaskDebugInfo(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTaskDebugInfo("Task_MergeRollupTask_1712959630094")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.TaskState, model.TaskStateCompleted, "Expected task state to be COMPLETED")
+	assert.Equal(t, res.SubtaskCount.Completed, 1, "Expected 1 completed subtask")
+	assert.Equal(t, res.SubtaskInfos[0].Participant, "Minion_172.19.0.2_9514", "Expected subtask to run on Minion_172.19.0.2_9514")
+}
+
+func TestGetTasksDebugInfo(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTasksDebugInfo("MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, (*res)["Task_MergeRollupTask_1712959630094"].SubtaskCount.Total, 1, "Expected 1 subtask")
+}
+
+func TestGetTaskGeneratorDebugInfo(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTaskGeneratorDebugInfo("test_OFFLINE", "MergeRollupTask")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, (*res)[0].TableNameWithType, "test_OFFLINE", "Expected table name to be test_OFFLINE")
+	assert.Equal(t, len((*res)[0].MostRecentSuccessRunTS), 1, "Expected 1 successful run")
+}
+
+func TestMinionTaskConfigRoundTrip(t *testing.T) {
+
+	mergeRollup := &model.MergeRollupTaskConfig{
+		Schedule: "0 */10 * ? * *",
+		Levels: map[string]model.MergeRollupLevelConfig{
+			"1day": {
+				MergeType:               model.MergeTypeRollup,
+				BucketTimePeriod:        "1d",
+				BufferTimePeriod:        "3d",
+				MaxNumRecordsPerSegment: 1000000,
+			},
+		},
+		AggregationTypes: map[string]string{"ArrDelay": "sum"},
+	}
+
+	upsertCompaction := &model.UpsertCompactionTaskConfig{
+		BufferTimePeriod:               "7d",
+		InvalidRecordsThresholdPercent: 30,
+		ValidDocIdsType:                model.ValidDocIdsTypeSnapshot,
+	}
+
+	task, err := model.NewTask(mergeRollup, upsertCompaction)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, task.TaskTypeConfigsMap[model.TaskTypeMergeRollup]["1day.mergeType"], "rollup", "Expected merge type to be rollup")
+	assert.Equal(t, task.TaskTypeConfigsMap[model.TaskTypeMergeRollup]["1day.maxNumRecordsPerSegment"], "1000000", "Expected max records to be 1000000")
+	assert.Equal(t, task.TaskTypeConfigsMap[model.TaskTypeMergeRollup]["ArrDelay.aggregationType"], "sum", "Expected aggregation type to be sum")
+	assert.Equal(t, task.TaskTypeConfigsMap[model.TaskTypeUpsertCompaction]["invalidRecordsThresholdPercent"], "30", "Expected threshold to be 30")
+
+	table := model.Table{TableName: "test", Task: task}
+
+	tableBytes, err := json.Marshal(table)
+	assert.NoError(t, err)
+
+	var decodedTable model.Table
+	err = json.Unmarshal(tableBytes, &decodedTable)
+	assert.NoError(t, err)
+
+	var decodedMergeRollup model.MergeRollupTaskConfig
+	found, err := decodedTable.Task.GetTaskConfig(&decodedMergeRollup)
+	assert.NoError(t, err)
+	assert.Equal(t, found, true, "Expected MergeRollupTask to be configured")
+	assert.Equal(t, decodedMergeRollup.Schedule, mergeRollup.Schedule, "Expected schedule to round trip")
+	assert.Equal(t, decodedMergeRollup.Levels, mergeRollup.Levels, "Expected levels to round trip")
+	assert.Equal(t, decodedMergeRollup.AggregationTypes, mergeRollup.AggregationTypes, "Expected aggregation types to round trip")
+
+	var purge model.PurgeTaskConfig
+	found, err = decodedTable.Task.GetTaskConfig(&purge)
+	assert.NoError(t, err)
+	assert.Equal(t, found, false, "Expected PurgeTask to not be configured")
+}
+
+func TestMinionTaskConfigValidation(t *testing.T) {
+
+	_, err := model.NewTask(&model.MergeRollupTaskConfig{
+		Levels: map[string]model.MergeRollupLevelConfig{
+			"1day": {MergeType: "append", BucketTimePeriod: "1d", BufferTimePeriod: "1d"},
+		},
+	})
+	assert.Error(t, err, "Expected error for invalid merge type")
+
+	_, err = model.NewTask(&model.MergeRollupTaskConfig{
+		Levels: map[string]model.MergeRollupLevelConfig{
+			"1day": {MergeType: "concat", BucketTimePeriod: "1 day", BufferTimePeriod: "1d"},
+		},
+	})
+	assert.Error(t, err, "Expected error for invalid bucket period")
+
+	_, err = model.NewTask(&model.MergeRollupTaskConfig{
+		Levels: map[string]model.MergeRollupLevelConfig{
+			"1day": {MergeType: "concat", BucketTimePeriod: "1d"},
+		},
+	})
+	assert.Error(t, err, "Expected error for missing buffer period")
+
+	_, err = model.NewTask(&model.RealtimeToOfflineSegmentsTaskConfig{BucketTimePeriod: "6h", BufferTimePeriod: "1d12h", MergeType: "dedup"})
+	assert.NoError(t, err, "Expected no error for valid RealtimeToOfflineSegmentsTask config")
+
+	_, err = model.NewTask(&model.PurgeTaskConfig{LastPurgeTimeThresholdPeriod: "14days"})
+	assert.Error(t, err, "Expected error for invalid purge period")
+
+	_, err = model.NewTask(&model.SegmentGenerationAndPushTaskConfig{PushMode: "copy"})
+	assert.Error(t, err, "Expected error for invalid push mode")
+
+	_, err = model.NewTask(&model.UpsertCompactionTaskConfig{InvalidRecordsThresholdPercent: 120})
+	assert.Error(t, err, "Expected error for invalid threshold percent")
+
+	_, err = model.NewTask(&model.RefreshSegmentTaskConfig{TableMaxNumTasks: -1})
+	assert.Error(t, err, "Expected error for negative max tasks")
+}
+
+func TestGetReloadJobStatus(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	reload, err := client.ReloadTableSegments("test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	jobs, err := model.ParseReloadJobs(reload.Status)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	res, err := client.GetReloadJobStatus(jobs["test_OFFLINE"].ReloadJobId)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.SuccessCount, 1, "Expected 1 segment to be reloaded")
+	assert.Equal(t, res.Metadata.TableName, "test_OFFLINE", "Expected table name to be test_OFFLINE")
+	assert.Equal(t, res.IsComplete(), true, "Expected reload job to be complete")
+}
+
+func TestGetTenantRebalanceStatus(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTenantRebalanceStatus("4b7a7bd6-b4d4-4d3b-98a4-3e3b8a7e3f2d")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.TenantRebalanceProgressStats.TableStatusMap["test_OFFLINE"], "DONE", "Expected table rebalance to be DONE")
+	assert.Equal(t, res.IsComplete(), true, "Expected tenant rebalance to be complete")
+}
+
+func TestRebalanceTable(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	// untagging the server hosting the segment gives the rebalance something to move
+	_, err := client.UpdateInstanceTags("Server_172.17.0.3_7050", []string{"server_untagged"}, false)
+	assert.NoError(t, err)
+
+	res, err := client.RebalanceTable("test", "OFFLINE", model.RebalanceTableOptions{DryRun: true})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, model.RebalanceStatusDone, "Expected dry run to be DONE")
+	assert.Equal(t, res.Description, "Dry-run mode", "Expected description to be 'Dry-run mode'")
+	assert.Equal(t, res.SegmentAssignment["test_OFFLINE_0"], map[string]string{"Server_172.17.0.4_7050": "ONLINE"}, "Expected segment to be assigned to the tagged server")
+	assert.Equal(t, res.InstanceAssignment["OFFLINE"].InstancePartitionsName, "test_OFFLINE", "Expected instance partitions to be test_OFFLINE")
+}
+
+func TestGetTableRebalanceStatus(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.UpdateInstanceTags("Server_172.17.0.3_7050", []string{"server_untagged"}, false)
+	assert.NoError(t, err)
+
+	rebalance, err := client.RebalanceTable("test", "OFFLINE", model.RebalanceTableOptions{Downtime: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	res, err := client.GetTableRebalanceStatus(rebalance.JobId)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.TableRebalanceProgressStats.Status, model.RebalanceStatusDone, "Expected status to be DONE")
+	assert.Equal(t, res.TableRebalanceProgressStats.InitialToTargetStateConvergence.SegmentsToRebalance, 1, "Expected 1 segment to rebalance")
+	assert.True(t, res.IsComplete(), "Expected rebalance to be complete")
+}
+
+func TestCancelTableRebalance(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.CancelTableRebalance("test", "OFFLINE")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Empty(t, *res, "Expected no running jobs to be cancelled")
+}
+
+func TestRebalanceTableOptionsQueryParams(t *testing.T) {
+	minAvailableReplicas := 1
+	options := model.RebalanceTableOptions{
+		DryRun:               true,
+		Downtime:             false,
+		MinAvailableReplicas: &minAvailableReplicas,
+	}
+
+	params := options.QueryParams()
+
+	assert.Equal(t, params["dryRun"], "true", "Expected dryRun to be true")
+	assert.Equal(t, params["downtime"], "false", "Expected downtime to be false")
+	assert.Equal(t, params["minAvailableReplicas"], "1", "Expected minAvailableReplicas to be 1")
+}
+
+func TestPauseConsumption(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.PauseConsumption("test", "maintenance")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.True(t, res.PauseFlag, "Expected table to be paused")
+	assert.Equal(t, res.Comment, "maintenance", "Expected comment to be 'maintenance'")
+	assert.Equal(t, res.ConsumingSegments, []string{"test__0__1__20240412T2107Z"}, "Expected 1 consuming segment")
+}
+
+func TestPauseConsumptionEscapesComment(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.PauseConsumption("test", "a&b=c #1")
+	assert.NoError(t, err)
+	assert.Equal(t, "a&b=c #1", res.Comment, "Expected comment to reach the controller unchanged")
+}
+
+func TestResumeConsumption(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ResumeConsumption("test", model.ConsumeFromLargest)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.False(t, res.PauseFlag, "Expected table to be resumed")
+
+	_, err = client.ResumeConsumption("test", "newest")
+	assert.Error(t, err, "Expected error for invalid consumeFrom")
+}
+
+func TestGetPauseStatus(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetPauseStatus("test")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.True(t, res.PauseFlag, "Expected table to be paused")
+	assert.Equal(t, res.ReasonCode, "ADMINISTRATIVE", "Expected reason code to be ADMINISTRATIVE")
+}
+
+func TestForceCommit(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ForceCommit("test", model.ForceCommitOptions{Partitions: []int{0, 1}})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.ForceCommitStatus, "SUCCESS", "Expected force commit status to be SUCCESS")
+	assert.Equal(t, res.ForceCommitJobId, "6c1d3f0a-8e2b-4b7d-9a5c-0f4e2d7b8a11", "Expected force commit job id")
+}
+
+func TestGetForceCommitStatus(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetForceCommitStatus("6c1d3f0a-8e2b-4b7d-9a5c-0f4e2d7b8a11")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	segments, err := res.ForceCommittedSegments()
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.True(t, res.IsComplete(), "Expected force commit to be complete")
+	assert.Equal(t, len(segments), 2, "Expected 2 segments to be force committed")
+}
+
+func TestGetConsumingSegmentsInfo(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetConsumingSegmentsInfo("test")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	info := res.SegmentToConsumingInfo["test__0__1__20240412T2107Z"][0]
+
+	assert.Equal(t, info.ConsumerState, "CONSUMING", "Expected consumer state to be CONSUMING")
+	assert.Equal(t, info.PartitionOffsetInfo.LatestUpstreamOffsetMap["0"], "200", "Expected latest upstream offset to be 200")
+	assert.Equal(t, info.PartitionOffsetInfo.RecordsLagMap["0"], "50", "Expected records lag to be 50")
+}
+
+func TestEnableDisableInstance(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.DisableInstance("Server_172.17.0.4_7050")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Request to disable instance Server_172.17.0.4_7050 is successful", "Expected instance to be disabled")
+
+	res, err = client.EnableInstance("Server_172.17.0.4_7050")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Request to enable instance Server_172.17.0.4_7050 is successful", "Expected instance to be enabled")
+}
+
+func TestUpdateInstanceTags(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.UpdateInstanceTags("Server_172.17.0.4_7050", []string{"DefaultTenant_OFFLINE", "DefaultTenant_REALTIME"}, false)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Updated tags: [DefaultTenant_OFFLINE DefaultTenant_REALTIME] for instance: Server_172.17.0.4_7050", "Expected tags to be updated")
+}
+
+func TestUpdateInstancePools(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.UpdateInstancePools("Server_172.17.0.4_7050", map[string]int{"DefaultTenant_OFFLINE": 1})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Updated instance: Server_172.17.0.4_7050", "Expected pools to be updated")
+}
+
+func TestUpdateBrokerResource(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.UpdateBrokerResource("Broker_cdba1ba98e74_8099")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Updated broker resource for broker instance: Broker_cdba1ba98e74_8099", "Expected broker resource to be updated")
+}
+
+func TestCreateTenantFromRequest(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.CreateTenantFromRequest(model.TenantRequest{
+		TenantRole:        model.TenantRoleServer,
+		TenantName:        "test",
+		NumberOfInstances: 2,
+		OfflineInstances:  1,
+		RealtimeInstances: 1,
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully created tenant", "Expected response to be Successfully created tenant")
+}
+
+func TestUpdateTenantFromRequest(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.UpdateTenantFromRequest(model.TenantRequest{
+		TenantRole:        model.TenantRoleBroker,
+		TenantName:        "DefaultTenant",
+		NumberOfInstances: 1,
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Updated tenant", "Expected response to be Updated tenant")
+}
+
+func TestTenantRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request model.TenantRequest
+	}{
+		{"missing name", model.TenantRequest{TenantRole: model.TenantRoleBroker, NumberOfInstances: 1}},
+		{"invalid role", model.TenantRequest{TenantRole: "MINION", TenantName: "test", NumberOfInstances: 1}},
+		{"no instances", model.TenantRequest{TenantRole: model.TenantRoleServer, TenantName: "test"}},
+		{"broker with offline instances", model.TenantRequest{TenantRole: model.TenantRoleBroker, TenantName: "test", NumberOfInstances: 1, OfflineInstances: 1}},
+		{"too many realtime instances", model.TenantRequest{TenantRole: model.TenantRoleServer, TenantName: "test", NumberOfInstances: 1, RealtimeInstances: 2}},
+	}
+
+	for _, tt := range tests {
+		assert.Error(t, tt.request.Validate(), "Expected error for %s", tt.name)
+	}
+}
+
+func TestGetTenantInstancesByRole(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetTenantInstancesByRole("DefaultTenant", model.TenantRoleBroker, "")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.BrokerInstances, []string{"Broker_cdba1ba98e74_8099"}, "Expected 1 broker")
+	assert.Empty(t, res.ServerInstances, "Expected no servers")
+}
+
+func TestSafeDeleteTenant(t *testing.T) {
+	controller := createAirlineStatsController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.SafeDeleteTenant("airlineTenant", model.TenantRoleServer)
+	assert.ErrorContains(t, err, "airlineStats_REALTIME", "Expected error when deleting a tenant with tables")
+
+	_, err = client.CreateTenantFromRequest(model.TenantRequest{TenantRole: model.TenantRoleServer, TenantName: "flights", NumberOfInstances: 1, OfflineInstances: 1})
+	assert.NoError(t, err)
+
+	res, err := client.SafeDeleteTenant("flights", model.TenantRoleServer)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Status, "Successfully deleted tenant flights", "Expected tenant without tables to be deleted")
+}
+
+func TestValidateSchemaInvalid(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ValidateSchema(model.Schema{})
+	assert.NoError(t, err)
+	assert.False(t, res.Ok, "Expected schema without a name to be invalid")
+	assert.Equal(t, "Invalid schema. Reason: schemaName is required", res.Error)
+}
+
+func TestValidateSchemaRequiresAuth(t *testing.T) {
+	controller := createMockController(t)
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(controller.URL))
+
+	_, err := client.ValidateSchema(model.Schema{SchemaName: "test"})
+	assert.Error(t, err, "Expected unauthenticated validation to fail instead of reporting the schema as valid")
+}
+
+func TestGetLeadersForAllTables(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetLeadersForAllTables()
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.True(t, res.LeadControllerResourceEnabled, "Expected lead controller resource to be enabled")
+	assert.Equal(t, 2, len(res.LeadControllerEntryMap), "Expected 2 lead controller entries")
+}
+
+func TestGetLeaderForTable(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetLeaderForTable("test")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	leader, ok := res.LeaderForTable("test")
+	assert.True(t, ok, "Expected table to have a lead controller")
+	assert.Equal(t, "Controller_172.17.0.2_9000", leader, "Expected lead controller to be Controller_172.17.0.2_9000")
+}
@@ -0,0 +1,410 @@
+package goPinotAPI
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// CheckTableHealth compares the ideal state of a table with its external view and reports missing segments,
+// replicas in ERROR or OFFLINE state, under replicated segments and disabled servers hosting the table
+func (c *PinotAPIClient) CheckTableHealth(tableName string) (*model.TableHealth, error) {
+
+	table, err := c.GetTable(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get table %s: %w", tableName, err)
+	}
+
+	idealState, err := c.GetTableIdealState(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ideal state of table %s: %w", tableName, err)
+	}
+
+	externalView, err := c.GetTableExternalView(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get external view of table %s: %w", tableName, err)
+	}
+
+	health := &model.TableHealth{
+		TableName: tableName,
+		Status:    model.HealthStatusHealthy,
+	}
+
+	servers := make(map[string]bool)
+	checkPartialHealth(health, "OFFLINE", tableReplication(table.OFFLINE, false), idealState.Offline, externalView.Offline, servers)
+	checkPartialHealth(health, "REALTIME", tableReplication(table.REALTIME, true), idealState.Realtime, externalView.Realtime, servers)
+
+	for _, serverName := range sortedKeys(servers) {
+		instance, err := c.GetInstance(serverName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get instance %s: %w", serverName, err)
+		}
+		if !instance.Enabled {
+			health.DisabledServers = append(health.DisabledServers, serverName)
+		}
+	}
+
+	if len(health.ErrorReplicas) > 0 || len(health.OfflineReplicas) > 0 || len(health.UnderReplicatedSegments) > 0 || len(health.DisabledServers) > 0 {
+		health.Status = health.Status.Worse(model.HealthStatusDegraded)
+	}
+	if len(health.MissingSegments) > 0 || len(health.UnavailableSegments) > 0 {
+		health.Status = health.Status.Worse(model.HealthStatusUnhealthy)
+	}
+
+	return health, nil
+}
+
+// tableReplication returns the number of replicas each segment should have, or 0 if unknown
+func tableReplication(table model.Table, realtime bool) int {
+
+	replication := table.SegmentsConfig.Replication
+	if realtime && table.SegmentsConfig.ReplicasPerPartition != "" {
+		replication = table.SegmentsConfig.ReplicasPerPartition
+	}
+
+	replicas, err := strconv.Atoi(replication)
+	if err != nil || replicas < 0 {
+		return 0
+	}
+	return replicas
+}
+
+// checkPartialHealth checks the segments of one table type, recording every server hosting a segment in servers
+func checkPartialHealth(health *model.TableHealth, tableType string, replication int, idealState map[string]map[string]string, externalView map[string]map[string]string, servers map[string]bool) {
+
+	for _, segmentName := range sortedKeys(idealState) {
+
+		health.TotalSegments++
+
+		viewStates, inView := externalView[segmentName]
+		if !inView {
+			health.MissingSegments = append(health.MissingSegments, segmentName)
+		}
+
+		serving := 0
+		expected := 0
+		instanceStates := idealState[segmentName]
+
+		for _, instanceName := range sortedKeys(instanceStates) {
+
+			servers[instanceName] = true
+
+			idealInstanceState := instanceStates[instanceName]
+			if idealInstanceState != "ONLINE" && idealInstanceState != "CONSUMING" {
+				continue
+			}
+			expected++
+
+			replica := model.SegmentReplicaState{
+				TableType:         tableType,
+				SegmentName:       segmentName,
+				InstanceName:      instanceName,
+				IdealState:        idealInstanceState,
+				ExternalViewState: viewStates[instanceName],
+			}
+
+			switch replica.ExternalViewState {
+			case "ONLINE", "CONSUMING":
+				serving++
+			case "ERROR":
+				health.ErrorReplicas = append(health.ErrorReplicas, replica)
+			default:
+				// missing segments are reported once rather than for every replica
+				if inView {
+					health.OfflineReplicas = append(health.OfflineReplicas, replica)
+				}
+			}
+		}
+
+		if inView && expected > 0 && serving == 0 {
+			health.UnavailableSegments = append(health.UnavailableSegments, segmentName)
+		}
+
+		if replication > 0 && serving < replication {
+			health.UnderReplicatedSegments = append(health.UnderReplicatedSegments, model.UnderReplicatedSegment{
+				TableType:        tableType,
+				SegmentName:      segmentName,
+				ExpectedReplicas: replication,
+				ServingReplicas:  serving,
+			})
+		}
+	}
+}
+
+const (
+	defaultHealthConcurrency = 8
+	defaultHealthTimeout     = 5 * time.Second
+)
+
+// ClusterHealthOptions configures how CheckClusterHealth probes instances
+type ClusterHealthOptions struct {
+	// Concurrency is the number of instances probed at once, defaults to 8
+	Concurrency int
+	// Timeout is the time allowed for each instance to respond, defaults to 5s
+	Timeout time.Duration
+	// Timeouts overrides Timeout for an instance type, e.g. to allow servers longer to respond
+	Timeouts map[model.InstanceType]time.Duration
+	// Scheme is used to reach instance health endpoints, defaults to http
+	Scheme string
+}
+
+func (o *ClusterHealthOptions) withDefaults() ClusterHealthOptions {
+	var opts ClusterHealthOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultHealthConcurrency
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultHealthTimeout
+	}
+	if opts.Scheme == "" {
+		opts.Scheme = "http"
+	}
+	return opts
+}
+
+func (o ClusterHealthOptions) timeout(instanceType model.InstanceType) time.Duration {
+	if timeout, ok := o.Timeouts[instanceType]; ok && timeout > 0 {
+		return timeout
+	}
+	return o.Timeout
+}
+
+// CheckClusterHealth checks the controller, probes the health endpoint of every instance in the cluster using
+// its hostname and admin port, and checks every tenant still has healthy brokers and servers. The check
+// stops with an error once ctx is done
+func (c *PinotAPIClient) CheckClusterHealth(ctx context.Context, opts *ClusterHealthOptions) (*model.ClusterHealth, error) {
+
+	healthOpts := opts.withDefaults()
+	client := c.WithContext(ctx)
+
+	instances, err := client.GetInstances()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instances: %w", err)
+	}
+
+	tenants, err := client.GetTenants()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tenants: %w", err)
+	}
+
+	health := &model.ClusterHealth{
+		Controller: client.checkControllerHealth(),
+		Instances:  make([]model.InstanceHealth, len(instances.Instances)),
+		Tenants:    []model.TenantHealth{},
+	}
+
+	sem := make(chan struct{}, healthOpts.Concurrency)
+	var wg sync.WaitGroup
+	for i, instanceName := range instances.Instances {
+		wg.Add(1)
+		go func(i int, instanceName string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			health.Instances[i] = client.checkInstanceHealth(ctx, instanceName, healthOpts)
+		}(i, instanceName)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("cluster health check stopped: %w", err)
+	}
+
+	sort.Slice(health.Instances, func(i, j int) bool {
+		return health.Instances[i].InstanceName < health.Instances[j].InstanceName
+	})
+
+	instanceHealth := make(map[string]model.InstanceHealth, len(health.Instances))
+	for _, instance := range health.Instances {
+		instanceHealth[instance.InstanceName] = instance
+	}
+
+	tenantNames := make(map[string]bool)
+	for _, tenantName := range tenants.ServerTenants {
+		tenantNames[tenantName] = true
+	}
+	for _, tenantName := range tenants.BrokerTenants {
+		tenantNames[tenantName] = true
+	}
+
+	for _, tenantName := range sortedKeys(tenantNames) {
+		tenantHealth, err := client.checkTenantHealth(tenantName, instanceHealth)
+		if err != nil {
+			return nil, err
+		}
+		health.Tenants = append(health.Tenants, *tenantHealth)
+	}
+
+	health.Status = health.Controller.Status
+	for _, tenant := range health.Tenants {
+		health.Status = health.Status.Worse(tenant.Status)
+	}
+	// a single unhealthy instance degrades the cluster, losing every instance of a tenant is caught above
+	for _, instance := range health.Instances {
+		if instance.Status != model.HealthStatusHealthy {
+			health.Status = health.Status.Worse(model.HealthStatusDegraded)
+		}
+	}
+
+	return health, nil
+}
+
+func (c *PinotAPIClient) checkControllerHealth() model.InstanceHealth {
+
+	health := model.InstanceHealth{
+		InstanceName: c.Host,
+		InstanceType: model.InstanceTypeController,
+		Enabled:      true,
+		Endpoint:     c.pinotControllerUrl.JoinPath("/health").String(),
+		Status:       model.HealthStatusHealthy,
+	}
+
+	start := time.Now()
+	_, err := c.CheckPinotControllerHealth()
+	health.Latency = time.Since(start)
+	if err != nil {
+		health.Status = model.HealthStatusUnhealthy
+		health.Error = err.Error()
+	}
+
+	return health
+}
+
+func (c *PinotAPIClient) checkInstanceHealth(ctx context.Context, instanceName string, opts ClusterHealthOptions) model.InstanceHealth {
+
+	health := model.InstanceHealth{
+		InstanceName: instanceName,
+		InstanceType: model.InstanceTypeOf(instanceName),
+		Status:       model.HealthStatusHealthy,
+	}
+
+	instance, err := c.GetInstance(instanceName)
+	if err != nil {
+		health.Status = model.HealthStatusUnhealthy
+		health.Error = fmt.Sprintf("unable to get instance: %s", err)
+		return health
+	}
+	health.Enabled = instance.Enabled
+
+	health.Endpoint = instanceHealthEndpoint(instance, health.InstanceType, opts.Scheme)
+	if health.Endpoint != "" {
+		probeCtx, cancel := context.WithTimeout(ctx, opts.timeout(health.InstanceType))
+		defer cancel()
+
+		start := time.Now()
+		err = c.probeHealth(probeCtx, health.Endpoint)
+		health.Latency = time.Since(start)
+		if err != nil {
+			health.Status = model.HealthStatusUnhealthy
+			health.Error = err.Error()
+			return health
+		}
+	}
+
+	if !instance.Enabled {
+		health.Status = model.HealthStatusDegraded
+	}
+
+	return health
+}
+
+// instanceHealthEndpoint returns the health endpoint of an instance on its admin port, falling back to
+// its main port for instances without an admin port such as brokers and controllers
+func instanceHealthEndpoint(instance *model.GetInstanceResponse, instanceType model.InstanceType, scheme string) string {
+
+	if instance.Hostname == "" {
+		return ""
+	}
+
+	port := ""
+	if instance.AdminPort > 0 {
+		port = strconv.Itoa(instance.AdminPort)
+	} else if p, err := strconv.Atoi(instance.Port); err == nil && p > 0 {
+		port = instance.Port
+	}
+	if port == "" {
+		return ""
+	}
+
+	path := "/health"
+	if instanceType == model.InstanceTypeServer {
+		path = "/health/readiness"
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(instance.Hostname, port), path)
+}
+
+// probeHealth calls a health endpoint of an instance, controller credentials are not sent to other instances
+func (c *PinotAPIClient) probeHealth(ctx context.Context, endpoint string) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("client: could not create request: %w", err)
+	}
+
+	res, err := c.pinotHttp.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("client: could not send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("client: request failed with status code: %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *PinotAPIClient) checkTenantHealth(tenantName string, instanceHealth map[string]model.InstanceHealth) (*model.TenantHealth, error) {
+
+	metadata, err := c.GetTenantMetadata(tenantName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get metadata of tenant %s: %w", tenantName, err)
+	}
+
+	health := &model.TenantHealth{
+		TenantName:      tenantName,
+		Status:          model.HealthStatusHealthy,
+		BrokerInstances: len(metadata.BrokerInstances),
+		ServerInstances: len(metadata.ServerInstances),
+	}
+
+	countHealthy := func(instanceNames []string) int {
+		healthy := 0
+		for _, instanceName := range instanceNames {
+			instance, ok := instanceHealth[instanceName]
+			if !ok {
+				health.UnknownInstances = append(health.UnknownInstances, instanceName)
+				continue
+			}
+			if instance.Status == model.HealthStatusHealthy {
+				healthy++
+			}
+		}
+		return healthy
+	}
+	health.HealthyBrokers = countHealthy(metadata.BrokerInstances)
+	health.HealthyServers = countHealthy(metadata.ServerInstances)
+
+	if health.HealthyBrokers < health.BrokerInstances || health.HealthyServers < health.ServerInstances {
+		health.Status = model.HealthStatusDegraded
+	}
+	if (health.BrokerInstances > 0 && health.HealthyBrokers == 0) || (health.ServerInstances > 0 && health.HealthyServers == 0) {
+		health.Status = model.HealthStatusUnhealthy
+	}
+	if health.BrokerInstances == 0 && health.ServerInstances == 0 {
+		health.Status = model.HealthStatusUnhealthy
+	}
+
+	return health, nil
+}
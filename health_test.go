@@ -0,0 +1,183 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTableHealth(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.CheckTableHealth("test")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, model.HealthStatusHealthy, res.Status, "Expected table to be healthy")
+	assert.Equal(t, 1, res.TotalSegments, "Expected 1 segment")
+	assert.Empty(t, res.UnderReplicatedSegments, "Expected no under replicated segments")
+	assert.Empty(t, res.DisabledServers, "Expected no disabled servers")
+}
+
+func TestCheckTableHealthUnhealthy(t *testing.T) {
+	controller := createAirlineStatsController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.CheckTableHealth("airlineStats")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, model.HealthStatusUnhealthy, res.Status, "Expected table to be unhealthy")
+	assert.Equal(t, 3, res.TotalSegments, "Expected 3 segments")
+	assert.Equal(t, []string{"airlineStats__1__1__20240412T2107Z"}, res.MissingSegments, "Expected 1 segment missing from the external view")
+	assert.Equal(t, 1, len(res.ErrorReplicas), "Expected 1 replica in ERROR state")
+	assert.Equal(t, "Server_172.17.0.4_7050", res.ErrorReplicas[0].InstanceName, "Expected ERROR replica on the second server")
+	assert.Equal(t, 2, len(res.UnderReplicatedSegments), "Expected 2 under replicated segments")
+	assert.Equal(t, 2, res.UnderReplicatedSegments[0].ExpectedReplicas, "Expected replicas per partition to be used for realtime tables")
+	assert.Equal(t, []string{"Server_172.17.0.4_7050"}, res.DisabledServers, "Expected the second server to be disabled")
+}
+
+func TestHealthStatusWorse(t *testing.T) {
+	assert.Equal(t, model.HealthStatusDegraded, model.HealthStatusHealthy.Worse(model.HealthStatusDegraded), "Expected DEGRADED to be worse than HEALTHY")
+	assert.Equal(t, model.HealthStatusUnhealthy, model.HealthStatusUnhealthy.Worse(model.HealthStatusDegraded), "Expected UNHEALTHY to be worse than DEGRADED")
+}
+
+// createClusterHealthServers starts a controller whose instances point at a healthy broker, a server that is not
+// ready and a minion that never responds in time
+func createClusterHealthServers(t *testing.T) *httptest.Server {
+
+	broker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "OK")
+	}))
+	t.Cleanup(broker.Close)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health/readiness" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, "NOT_READY", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	minion := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(minion.Close)
+
+	instances := map[string]*httptest.Server{
+		"Broker_127.0.0.1_8099": broker,
+		"Server_127.0.0.1_8098": server,
+		"Minion_127.0.0.1_9514": minion,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "OK")
+	})
+	mux.HandleFunc("/instances", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"instances": ["Broker_127.0.0.1_8099", "Server_127.0.0.1_8098", "Minion_127.0.0.1_9514"]}`)
+	})
+	for instanceName, instanceServer := range instances {
+		instanceName := instanceName
+		host, port, _ := net.SplitHostPort(instanceServer.Listener.Addr().String())
+		mux.HandleFunc("/instances/"+instanceName, func(w http.ResponseWriter, r *http.Request) {
+			// brokers have no admin port and are probed on their query port
+			if model.InstanceTypeOf(instanceName) == model.InstanceTypeBroker {
+				fmt.Fprintf(w, `{"instanceName": "%s", "hostName": "%s", "enabled": true, "port": "%s", "adminPort": -1}`, instanceName, host, port)
+				return
+			}
+			fmt.Fprintf(w, `{"instanceName": "%s", "hostName": "%s", "enabled": true, "port": "1", "adminPort": %s}`, instanceName, host, port)
+		})
+	}
+	mux.HandleFunc("/tenants", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"SERVER_TENANTS": ["DefaultTenant"], "BROKER_TENANTS": ["DefaultTenant"]}`)
+	})
+	mux.HandleFunc("/tenants/DefaultTenant/metadata", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"ServerInstances": ["Server_127.0.0.1_8098", "Server_127.0.0.2_8098"],
+			"BrokerInstances": ["Broker_127.0.0.1_8099"],
+			"OfflineServerInstances": null,
+			"RealtimeServerInstances": null,
+			"TenantName": "DefaultTenant"
+		}`)
+	})
+
+	controller := httptest.NewServer(mux)
+	t.Cleanup(controller.Close)
+
+	return controller
+}
+
+func TestCheckClusterHealth(t *testing.T) {
+	server := createClusterHealthServers(t)
+	client := createPinotClient(server.URL)
+
+	res, err := client.CheckClusterHealth(context.Background(), &goPinotAPI.ClusterHealthOptions{
+		Concurrency: 2,
+		Timeouts:    map[model.InstanceType]time.Duration{model.InstanceTypeMinion: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, model.HealthStatusHealthy, res.Controller.Status, "Expected controller to be healthy")
+	assert.Equal(t, 3, len(res.Instances), "Expected 3 instances")
+
+	instances := make(map[string]model.InstanceHealth)
+	for _, instance := range res.Instances {
+		instances[instance.InstanceName] = instance
+	}
+
+	assert.Equal(t, model.HealthStatusHealthy, instances["Broker_127.0.0.1_8099"].Status, "Expected broker to be healthy")
+	assert.Equal(t, model.HealthStatusUnhealthy, instances["Server_127.0.0.1_8098"].Status, "Expected server that is not ready to be unhealthy")
+	assert.Contains(t, instances["Server_127.0.0.1_8098"].Endpoint, "/health/readiness", "Expected server readiness to be probed")
+	assert.Equal(t, model.HealthStatusUnhealthy, instances["Minion_127.0.0.1_9514"].Status, "Expected minion to time out")
+
+	tenant := res.Tenants[0]
+	assert.Equal(t, model.HealthStatusUnhealthy, tenant.Status, "Expected tenant without a healthy server to be unhealthy")
+	assert.Equal(t, 1, tenant.HealthyBrokers, "Expected 1 healthy broker")
+	assert.Equal(t, 0, tenant.HealthyServers, "Expected no healthy servers")
+	assert.Equal(t, []string{"Server_127.0.0.2_8098"}, tenant.UnknownInstances, "Expected server missing from the cluster to be reported")
+
+	assert.Equal(t, model.HealthStatusUnhealthy, res.Status, "Expected cluster to be unhealthy")
+}
+
+func TestCheckClusterHealthDeadline(t *testing.T) {
+	server := createClusterHealthServers(t)
+	client := createPinotClient(server.URL)
+
+	// the minion takes a second to respond, longer than the deadline of the check
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.CheckClusterHealth(ctx, nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected the check to stop at the deadline")
+	assert.Less(t, time.Since(start), time.Second, "Expected the check not to wait for the minion")
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.CheckClusterHealth(cancelled, nil)
+	assert.ErrorIs(t, err, context.Canceled, "Expected a cancelled check not to call the controller")
+}
@@ -0,0 +1,68 @@
+package goPinotAPI
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Operation describes a call to the controller made by a client method
+type Operation struct {
+	Name      string // the client method making the call, e.g. GetTable
+	Method    string // the HTTP method
+	Path      string
+	TableName string // the table the call is about, empty when it is not about a table
+}
+
+// OperationResult is the outcome of an Operation
+type OperationResult struct {
+	StatusCode int // 0 when no response was received
+	Retries    int
+	Duration   time.Duration
+	Err        error
+}
+
+// Instrumentation observes every call the client makes to the controller, see the otelpinot module
+// for an OpenTelemetry implementation
+type Instrumentation interface {
+	// Start is called before a request is sent, with the request headers so trace context can be
+	// propagated. The returned context is used for the request and the returned func is called
+	// with the result once the call has finished
+	Start(ctx context.Context, op Operation, header http.Header) (context.Context, func(OperationResult))
+}
+
+type retriesKey struct{}
+
+// newOperation describes a request about to be sent by the client method named name
+func newOperation(name string, req *http.Request) Operation {
+	return Operation{
+		Name:      name,
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		TableName: operationTableName(req.URL.Path),
+	}
+}
+
+// operationTableName extracts the table from paths of the form /tables/{table}/... and /segments/{table}/...
+func operationTableName(path string) string {
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	switch parts[0] {
+	case "tables", "segments":
+		return parts[1]
+	default:
+		return ""
+	}
+}
+
+// countRetry records a retry of a request for its OperationResult
+func countRetry(ctx context.Context) {
+	if retries, ok := ctx.Value(retriesKey{}).(*int); ok {
+		*retries++
+	}
+}
@@ -0,0 +1,102 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedOperation struct {
+	goPinotAPI.Operation
+	goPinotAPI.OperationResult
+}
+
+type recordingInstrumentation struct {
+	operations []recordedOperation
+}
+
+func (r *recordingInstrumentation) Start(ctx context.Context, op goPinotAPI.Operation, header http.Header) (context.Context, func(goPinotAPI.OperationResult)) {
+	header.Set("X-Operation", op.Name)
+	return ctx, func(result goPinotAPI.OperationResult) {
+		r.operations = append(r.operations, recordedOperation{op, result})
+	}
+}
+
+func TestInstrumentation(t *testing.T) {
+	controller := createMockController(t)
+	instrumentation := &recordingInstrumentation{}
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controller.URL),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.Instrument(instrumentation),
+	)
+
+	_, err := client.GetTableSize("test")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteUser("test", "")
+	assert.Error(t, err)
+
+	_, err = client.CreateSchema(model.Schema{SchemaName: "flights"})
+	assert.NoError(t, err)
+
+	var tables model.GetTablesResponse
+	err = client.FetchData("/tables", &tables)
+	assert.NoError(t, err)
+
+	if assert.Len(t, instrumentation.operations, 5) {
+		getTableSize := instrumentation.operations[0]
+		assert.Equal(t, "GetTableSize", getTableSize.Name, "Expected operation to be named after the client method")
+		assert.Equal(t, http.MethodGet, getTableSize.Method)
+		assert.Equal(t, "test", getTableSize.TableName)
+		assert.Equal(t, http.StatusOK, getTableSize.StatusCode)
+		assert.NoError(t, getTableSize.Err)
+
+		deleteUser := instrumentation.operations[1]
+		assert.Equal(t, "DeleteUser", deleteUser.Name)
+		assert.Empty(t, deleteUser.TableName)
+		assert.Equal(t, http.StatusNotFound, deleteUser.StatusCode)
+		assert.Error(t, deleteUser.Err)
+
+		assert.Equal(t, "ValidateSchema", instrumentation.operations[2].Name, "Expected nested client calls to be named after themselves")
+		assert.Equal(t, "CreateSchema", instrumentation.operations[3].Name, "Expected operation to be named after the method calling the pipeline")
+		assert.Equal(t, "FetchData", instrumentation.operations[4].Name, "Expected direct pipeline calls to be named after the pipeline method")
+	}
+}
+
+func TestInstrumentationRetries(t *testing.T) {
+	var tokensIssued int32
+	server := createOAuth2Server(t, &tokensIssued)
+	defer server.Close()
+
+	instrumentation := &recordingInstrumentation{}
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.AuthProvider(&goPinotAPI.OAuth2ClientCredentials{
+			TokenURL:     server.URL + "/oauth/token",
+			ClientID:     "pinot-client",
+			ClientSecret: "secret",
+			Scopes:       []string{"pinot:read", "pinot:write"},
+		}),
+		goPinotAPI.Instrument(instrumentation),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+
+	atomic.AddInt32(&tokensIssued, 1)
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+
+	if assert.Len(t, instrumentation.operations, 2) {
+		assert.Equal(t, 0, instrumentation.operations[0].Retries)
+		assert.Equal(t, 1, instrumentation.operations[1].Retries, "Expected the retry after a 401 to be counted")
+	}
+}
@@ -0,0 +1,226 @@
+package goPinotAPI
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// GetConsumerLag reports how far behind the stream each consuming replica of a realtime table is
+func (c *PinotAPIClient) GetConsumerLag(tableName string, thresholds model.ConsumerLagThresholds) (*model.ConsumerLagReport, error) {
+	return c.GetConsumerLagAt(tableName, thresholds, time.Now())
+}
+
+// GetConsumerLagAt is GetConsumerLag with the time since last consumed measured from the given time
+func (c *PinotAPIClient) GetConsumerLagAt(tableName string, thresholds model.ConsumerLagThresholds, now time.Time) (*model.ConsumerLagReport, error) {
+
+	consumingInfo, err := c.GetConsumingSegmentsInfo(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get consuming segments info for table %s: %w", tableName, err)
+	}
+
+	zkMetadata, err := c.GetSegmentZKMetadata(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get segment zk metadata for table %s: %w", tableName, err)
+	}
+
+	report := &model.ConsumerLagReport{
+		TableName:               tableName,
+		GeneratedAt:             now,
+		Partitions:              []model.PartitionLag{},
+		ServersFailingToRespond: consumingInfo.ServersFailingToRespond,
+	}
+
+	for segmentName, replicas := range consumingInfo.SegmentToConsumingInfo {
+
+		metadata := (*zkMetadata)[segmentName]
+
+		// a segment normally consumes a single partition, but the servers report offsets per partition
+		partitions := make(map[string][]model.ReplicaLag)
+		for _, info := range replicas {
+			for _, partition := range consumingPartitions(info) {
+				replica := replicaLag(info, partition, now)
+				replica.Lagging = isLagging(replica, thresholds)
+				partitions[partition] = append(partitions[partition], replica)
+			}
+		}
+
+		for partition, replicaLags := range partitions {
+
+			sort.Slice(replicaLags, func(i, j int) bool {
+				return replicaLags[i].ServerName < replicaLags[j].ServerName
+			})
+
+			partitionLag := model.PartitionLag{
+				Partition:     partition,
+				SegmentName:   segmentName,
+				SegmentStatus: metadata.RealtimeStatus,
+				StartOffset:   metadata.RealtimeStartOffset,
+				Replicas:      replicaLags,
+			}
+
+			for _, replica := range replicaLags {
+				if replica.RecordsLag > partitionLag.MaxRecordsLag {
+					partitionLag.MaxRecordsLag = replica.RecordsLag
+				}
+				if replica.Lagging {
+					report.LaggingReplicas = append(report.LaggingReplicas, fmt.Sprintf("%s/%s", segmentName, replica.ServerName))
+				}
+			}
+
+			report.TotalRecordsLag += partitionLag.MaxRecordsLag
+			report.Partitions = append(report.Partitions, partitionLag)
+		}
+	}
+
+	sort.Slice(report.Partitions, func(i, j int) bool {
+		return comparePartitions(report.Partitions[i].Partition, report.Partitions[j].Partition)
+	})
+	sort.Strings(report.LaggingReplicas)
+
+	return report, nil
+}
+
+func consumingPartitions(info model.ConsumingSegmentInfo) []string {
+
+	offsets := info.PartitionOffsetInfo.CurrentOffsetsMap
+	if len(offsets) == 0 {
+		offsets = info.PartitionToOffsetMap
+	}
+
+	return sortedKeys(offsets)
+}
+
+func replicaLag(info model.ConsumingSegmentInfo, partition string, now time.Time) model.ReplicaLag {
+
+	offsetInfo := info.PartitionOffsetInfo
+
+	currentOffset := parseOffset(offsetInfo.CurrentOffsetsMap[partition])
+	if currentOffset < 0 {
+		currentOffset = parseOffset(info.PartitionToOffsetMap[partition])
+	}
+	latestOffset := parseOffset(offsetInfo.LatestUpstreamOffsetMap[partition])
+
+	recordsLag := parseOffset(offsetInfo.RecordsLagMap[partition])
+	if recordsLag < 0 && currentOffset >= 0 && latestOffset >= 0 {
+		recordsLag = latestOffset - currentOffset
+	}
+
+	replica := model.ReplicaLag{
+		ServerName:           info.ServerName,
+		ConsumerState:        info.ConsumerState,
+		CurrentOffset:        currentOffset,
+		LatestUpstreamOffset: latestOffset,
+		RecordsLag:           recordsLag,
+		AvailabilityLagMs:    parseOffset(offsetInfo.AvailabilityLagMsMap[partition]),
+	}
+
+	if info.LastConsumedTimestamp > 0 {
+		replica.LastConsumed = time.UnixMilli(info.LastConsumedTimestamp)
+		replica.TimeSinceLastConsumed = now.Sub(replica.LastConsumed)
+	}
+
+	return replica
+}
+
+func isLagging(replica model.ReplicaLag, thresholds model.ConsumerLagThresholds) bool {
+
+	if replica.ConsumerState != "CONSUMING" {
+		return true
+	}
+
+	if thresholds.RecordsLag > 0 && replica.RecordsLag > thresholds.RecordsLag {
+		return true
+	}
+
+	if thresholds.TimeSinceLastConsumed > 0 && replica.TimeSinceLastConsumed > thresholds.TimeSinceLastConsumed {
+		return true
+	}
+
+	return false
+}
+
+// parseOffset returns -1 for missing or non numeric offsets, e.g. UNKNOWN or offsets of non kafka streams
+func parseOffset(offset string) int64 {
+	value, err := strconv.ParseInt(offset, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// comparePartitions orders numeric partition ids numerically and anything else lexically
+func comparePartitions(a string, b string) bool {
+	numA, errA := strconv.Atoi(a)
+	numB, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return numA < numB
+	}
+	return a < b
+}
+
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteConsumerLagMetrics writes the reports in the prometheus text exposition format, with one series per
+// table, partition and server. Offsets, lag and time since last consumed the server does not report are
+// left out rather than written as a value dashboards would take for a measurement
+func WriteConsumerLagMetrics(w io.Writer, reports ...*model.ConsumerLagReport) error {
+
+	knownOffset := func(offset int64) (float64, bool) { return float64(offset), offset >= 0 }
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(replica model.ReplicaLag) (float64, bool)
+	}{
+		{"pinot_consumer_records_lag", "Records the replica is behind the latest upstream offset.", func(r model.ReplicaLag) (float64, bool) { return knownOffset(r.RecordsLag) }},
+		{"pinot_consumer_availability_lag_ms", "Milliseconds between the latest upstream record and the last consumed record.", func(r model.ReplicaLag) (float64, bool) { return knownOffset(r.AvailabilityLagMs) }},
+		{"pinot_consumer_current_offset", "Offset the replica has consumed up to.", func(r model.ReplicaLag) (float64, bool) { return knownOffset(r.CurrentOffset) }},
+		{"pinot_consumer_latest_upstream_offset", "Latest offset of the partition in the stream.", func(r model.ReplicaLag) (float64, bool) { return knownOffset(r.LatestUpstreamOffset) }},
+		{"pinot_consumer_seconds_since_last_consumed", "Seconds since the replica last consumed a record.", func(r model.ReplicaLag) (float64, bool) {
+			return r.TimeSinceLastConsumed.Seconds(), !r.LastConsumed.IsZero()
+		}},
+		{"pinot_consumer_lagging", "Whether the replica is lagging, 1 if so.", func(r model.ReplicaLag) (float64, bool) {
+			if r.Lagging {
+				return 1, true
+			}
+			return 0, true
+		}},
+	}
+
+	for _, metric := range metrics {
+
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name); err != nil {
+			return err
+		}
+
+		for _, report := range reports {
+			for _, partition := range report.Partitions {
+				for _, replica := range partition.Replicas {
+					value, known := metric.value(replica)
+					if !known {
+						continue
+					}
+
+					_, err := fmt.Fprintf(w, "%s{table=\"%s\",partition=\"%s\",server=\"%s\"} %s\n",
+						metric.name,
+						prometheusLabelEscaper.Replace(report.TableName),
+						prometheusLabelEscaper.Replace(partition.Partition),
+						prometheusLabelEscaper.Replace(replica.ServerName),
+						strconv.FormatFloat(value, 'g', -1, 64),
+					)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package goPinotAPI_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// ten seconds after the most recent consumed timestamp in the mock consuming segments info
+var consumerLagNow = time.UnixMilli(1712959640094)
+
+var consumerLagThresholds = model.ConsumerLagThresholds{
+	RecordsLag:            1000,
+	TimeSinceLastConsumed: time.Minute,
+}
+
+func TestGetConsumerLag(t *testing.T) {
+	controller := createAirlineStatsController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetConsumerLagAt("airlineStats", consumerLagThresholds, consumerLagNow)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, 2, len(res.Partitions), "Expected 2 partitions")
+
+	partition := res.Partitions[0]
+	assert.Equal(t, "0", partition.Partition, "Expected partitions to be ordered")
+	assert.Equal(t, "8000", partition.StartOffset, "Expected start offset from zk metadata")
+	assert.Equal(t, int64(6000), partition.MaxRecordsLag, "Expected max lag of the slowest replica")
+	assert.Equal(t, int64(9900), partition.Replicas[0].CurrentOffset, "Expected current offset to be 9900")
+	assert.Equal(t, 10*time.Second, partition.Replicas[0].TimeSinceLastConsumed, "Expected last consumed 10s ago")
+	assert.False(t, partition.Replicas[0].Lagging, "Expected first replica not to be lagging")
+	assert.True(t, partition.Replicas[1].Lagging, "Expected second replica to be lagging")
+
+	// records lag is derived from the offsets when the server does not report it
+	assert.Equal(t, int64(20), res.Partitions[1].Replicas[0].RecordsLag, "Expected records lag to be 20")
+
+	assert.Equal(t, int64(6020), res.TotalRecordsLag, "Expected total lag to be 6020")
+	assert.Equal(t, []string{
+		"airlineStats__0__3__20240412T2107Z/Server_172.17.0.4_7050",
+		"airlineStats__1__3__20240412T2107Z/Server_172.17.0.3_7050",
+	}, res.LaggingReplicas, "Expected the slow and not consuming replicas to be lagging")
+}
+
+func TestWriteConsumerLagMetrics(t *testing.T) {
+	controller := createAirlineStatsController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.GetConsumerLagAt("airlineStats", consumerLagThresholds, consumerLagNow)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = goPinotAPI.WriteConsumerLagMetrics(&buf, res)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	metrics := buf.String()
+	assert.Contains(t, metrics, "# TYPE pinot_consumer_records_lag gauge\n", "Expected records lag type")
+	assert.Contains(t, metrics, `pinot_consumer_records_lag{table="airlineStats",partition="0",server="Server_172.17.0.4_7050"} 6000`, "Expected records lag series")
+	assert.Contains(t, metrics, `pinot_consumer_seconds_since_last_consumed{table="airlineStats",partition="0",server="Server_172.17.0.3_7050"} 10`, "Expected seconds since last consumed series")
+	assert.Contains(t, metrics, `pinot_consumer_lagging{table="airlineStats",partition="1",server="Server_172.17.0.3_7050"} 1`, "Expected lagging series")
+}
+
+func TestWriteConsumerLagMetricsUnknown(t *testing.T) {
+
+	report := &model.ConsumerLagReport{
+		TableName: "airlineStats",
+		Partitions: []model.PartitionLag{{
+			Partition: "0",
+			Replicas: []model.ReplicaLag{{
+				ServerName:           "Server_172.17.0.3_7050",
+				ConsumerState:        "CONSUMING",
+				CurrentOffset:        -1,
+				LatestUpstreamOffset: -1,
+				RecordsLag:           -1,
+				AvailabilityLagMs:    -1,
+				Lagging:              true,
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	err := goPinotAPI.WriteConsumerLagMetrics(&buf, report)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	metrics := buf.String()
+	assert.NotContains(t, metrics, "pinot_consumer_records_lag{", "Expected unknown records lag to be left out")
+	assert.NotContains(t, metrics, "pinot_consumer_current_offset{", "Expected unknown offsets to be left out")
+	assert.NotContains(t, metrics, "pinot_consumer_seconds_since_last_consumed{", "Expected unknown last consumed time to be left out")
+	assert.Contains(t, metrics, `pinot_consumer_lagging{table="airlineStats",partition="0",server="Server_172.17.0.3_7050"} 1`, "Expected lagging series")
+}
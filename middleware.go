@@ -0,0 +1,64 @@
+package goPinotAPI
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Handler sends a request to the controller and returns its response
+type Handler func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the handler that sends requests to the controller, it can change the request
+// before calling next and inspect or replace the response after
+type Middleware func(next Handler) Handler
+
+const middlewares = "middlewares"
+
+// middlewaresOpt is an option to add middleware to the client
+type middlewaresOpt struct {
+	middlewares []Middleware
+}
+
+func (o *middlewaresOpt) apply(c *cfg) {
+	c.middlewares = append(c.middlewares, o.middlewares...)
+}
+
+func (o *middlewaresOpt) Type() string {
+	return middlewares
+}
+
+// Middlewares adds middleware around every request the client sends. Middleware runs in the order
+// it is added, the first being the outermost, and sees each request before credentials are applied
+func Middlewares(middlewares ...Middleware) Opt {
+	return &middlewaresOpt{middlewares: middlewares}
+}
+
+// SetHeaders returns middleware that sets the given headers on every request, such as the
+// database header of a multi database cluster
+func SetHeaders(headers map[string]string) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			for key, value := range headers {
+				req.Header.Set(key, value)
+			}
+			return next(req)
+		}
+	}
+}
+
+// RequestID returns middleware that sets a random request id in the given header of every request
+// that does not already have one
+func RequestID(header string) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(header) == "" {
+				id := make([]byte, 16)
+				if _, err := rand.Read(id); err == nil {
+					req.Header.Set(header, hex.EncodeToString(id))
+				}
+			}
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package goPinotAPI_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	var order []string
+	var headers http.Header
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteClusterInfo, func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	trace := func(name string) goPinotAPI.Middleware {
+		return func(next goPinotAPI.Handler) goPinotAPI.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, "before "+name)
+				res, err := next(req)
+				order = append(order, fmt.Sprintf("after %s %d", name, res.StatusCode))
+				return res, err
+			}
+		}
+	}
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.Middlewares(trace("outer"), goPinotAPI.SetHeaders(map[string]string{"database": "analytics"})),
+		goPinotAPI.Middlewares(trace("inner"), goPinotAPI.RequestID("X-Request-Id")),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"before outer", "before inner", "after inner 200", "after outer 200"}, order)
+	assert.Equal(t, "analytics", headers.Get("database"))
+	assert.Len(t, headers.Get("X-Request-Id"), 32)
+	assert.Equal(t, "Basic YWRtaW46YWRtaW4K", headers.Get("Authorization"), "Expected credentials to still be applied")
+}
+
+func TestMiddlewareFaultInjection(t *testing.T) {
+	controller := createMockController(t)
+	var audited []string
+
+	audit := func(next goPinotAPI.Handler) goPinotAPI.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				audited = append(audited, fmt.Sprintf("%s %s", req.Method, req.URL.Path))
+			}
+			return next(req)
+		}
+	}
+
+	unavailable := func(next goPinotAPI.Handler) goPinotAPI.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader(`{"code": 503, "error": "injected"}`)),
+				Header:     http.Header{},
+				Request:    req,
+			}, nil
+		}
+	}
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controller.URL),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.Middlewares(audit, unavailable),
+	)
+
+	_, err := client.DeleteUser("test", "BROKER")
+	assert.ErrorContains(t, err, "status 503", "Expected injected response to be returned")
+	assert.Equal(t, []string{"DELETE /users/test"}, audited)
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// InstanceHealth is the result of probing the health endpoint of an instance, Endpoint is empty when
+// the instance does not expose a port to probe
+type InstanceHealth struct {
+	InstanceName string        `json:"instanceName"`
+	InstanceType InstanceType  `json:"instanceType"`
+	Enabled      bool          `json:"enabled"`
+	Endpoint     string        `json:"endpoint,omitempty"`
+	Status       HealthStatus  `json:"status"`
+	Error        string        `json:"error,omitempty"`
+	Latency      time.Duration `json:"latency"`
+}
+
+// TenantHealth counts the healthy instances of a tenant, a tenant is UNHEALTHY when it has no healthy
+// broker or server for a role it serves
+type TenantHealth struct {
+	TenantName       string       `json:"tenantName"`
+	Status           HealthStatus `json:"status"`
+	BrokerInstances  int          `json:"brokerInstances"`
+	HealthyBrokers   int          `json:"healthyBrokers"`
+	ServerInstances  int          `json:"serverInstances"`
+	HealthyServers   int          `json:"healthyServers"`
+	UnknownInstances []string     `json:"unknownInstances,omitempty"`
+}
+
+// ClusterHealth is the health of the controller, every instance and every tenant of a cluster
+type ClusterHealth struct {
+	Status     HealthStatus     `json:"status"`
+	Controller InstanceHealth   `json:"controller"`
+	Instances  []InstanceHealth `json:"instances"`
+	Tenants    []TenantHealth   `json:"tenants"`
+}
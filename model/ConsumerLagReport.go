@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// ConsumerLagThresholds decide when a consuming replica is reported as lagging, a zero threshold is not checked.
+// Replicas that are not in the CONSUMING state are always lagging.
+type ConsumerLagThresholds struct {
+	RecordsLag            int64
+	TimeSinceLastConsumed time.Duration
+}
+
+// ReplicaLag is the lag of one server consuming a partition, offsets and lag are -1 when the server does not
+// report them or they are not numeric
+type ReplicaLag struct {
+	ServerName            string        `json:"serverName"`
+	ConsumerState         string        `json:"consumerState"`
+	CurrentOffset         int64         `json:"currentOffset"`
+	LatestUpstreamOffset  int64         `json:"latestUpstreamOffset"`
+	RecordsLag            int64         `json:"recordsLag"`
+	AvailabilityLagMs     int64         `json:"availabilityLagMs"`
+	LastConsumed          time.Time     `json:"lastConsumed"`
+	TimeSinceLastConsumed time.Duration `json:"timeSinceLastConsumed"`
+	Lagging               bool          `json:"lagging"`
+}
+
+// PartitionLag is the lag of the consuming segment of a stream partition across its replicas
+type PartitionLag struct {
+	Partition     string       `json:"partition"`
+	SegmentName   string       `json:"segmentName"`
+	SegmentStatus string       `json:"segmentStatus,omitempty"`
+	StartOffset   string       `json:"startOffset,omitempty"`
+	Replicas      []ReplicaLag `json:"replicas"`
+	// MaxRecordsLag is the largest lag of any replica of the partition
+	MaxRecordsLag int64 `json:"maxRecordsLag"`
+}
+
+// ConsumerLagReport is the per-partition consumer lag of a realtime table
+type ConsumerLagReport struct {
+	TableName               string         `json:"tableName"`
+	GeneratedAt             time.Time      `json:"generatedAt"`
+	Partitions              []PartitionLag `json:"partitions"`
+	LaggingReplicas         []string       `json:"laggingReplicas,omitempty"`
+	ServersFailingToRespond int            `json:"serversFailingToRespond"`
+	TotalRecordsLag         int64          `json:"totalRecordsLag"`
+}
@@ -0,0 +1,48 @@
+package model
+
+type DecommissionStep string
+
+const (
+	DecommissionStepUntag     DecommissionStep = "UNTAG"
+	DecommissionStepRebalance DecommissionStep = "REBALANCE"
+	DecommissionStepConverge  DecommissionStep = "CONVERGE"
+	DecommissionStepVerify    DecommissionStep = "VERIFY"
+	DecommissionStepDisable   DecommissionStep = "DISABLE"
+	DecommissionStepDelete    DecommissionStep = "DELETE"
+	DecommissionStepDone      DecommissionStep = "DONE"
+)
+
+// DecommissionSteps are the steps of decommissioning a server in the order they run
+var DecommissionSteps = []DecommissionStep{
+	DecommissionStepUntag,
+	DecommissionStepRebalance,
+	DecommissionStepConverge,
+	DecommissionStepVerify,
+	DecommissionStepDisable,
+	DecommissionStepDelete,
+	DecommissionStepDone,
+}
+
+// DecommissionState records the progress of decommissioning a server, it can be saved as json and passed
+// back to resume a decommission that was interrupted
+type DecommissionState struct {
+	InstanceName string `json:"instanceName"`
+	// Step is the next step to run
+	Step DecommissionStep `json:"step"`
+	// PreviousTags are the tags the server had before it was untagged, restored on rollback
+	PreviousTags []string `json:"previousTags"`
+	// Tables are the tables, with type, that had segments on the server when the decommission started
+	Tables           []string `json:"tables"`
+	RebalancedTables []string `json:"rebalancedTables,omitempty"`
+	ConvergedTables  []string `json:"convergedTables,omitempty"`
+	// SegmentsOnServer counts the segments each table had on the server when the decommission started
+	SegmentsOnServer map[string]int `json:"segmentsOnServer,omitempty"`
+	DryRun           bool           `json:"dryRun,omitempty"`
+	RolledBack       bool           `json:"rolledBack,omitempty"`
+	Error            string         `json:"error,omitempty"`
+}
+
+// IsDone returns true once the server has been deleted
+func (s *DecommissionState) IsDone() bool {
+	return s.Step == DecommissionStepDone
+}
@@ -0,0 +1,18 @@
+package model
+
+// DrainServerResult is the outcome of moving every segment off a server
+type DrainServerResult struct {
+	InstanceName string `json:"instanceName"`
+	// PreviousTags are the tags the server had before it was untagged, to restore it if needed
+	PreviousTags []string `json:"previousTags"`
+	// RebalancedTables are the tables with segments on the server when the drain started
+	RebalancedTables []string `json:"rebalancedTables"`
+	// RemainingTables are the tables whose ideal state or external view still has segments on the server after
+	// rebalancing
+	RemainingTables []string `json:"remainingTables,omitempty"`
+}
+
+// IsDrained returns true once no ideal state or external view has segments on the server
+func (r *DrainServerResult) IsDrained() bool {
+	return len(r.RemainingTables) == 0
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ForceCommitOptions selects the consuming segments to force commit, every consuming segment
+// is committed when no partitions or segments are given
+type ForceCommitOptions struct {
+	Partitions []int
+	Segments   []string
+	// BatchSize commits the segments in batches of this size, pinot commits them all at once when zero
+	BatchSize int
+}
+
+func (o ForceCommitOptions) QueryParams() map[string]string {
+	params := make(map[string]string)
+	if len(o.Partitions) > 0 {
+		partitions := make([]string, len(o.Partitions))
+		for i, partition := range o.Partitions {
+			partitions[i] = strconv.Itoa(partition)
+		}
+		params["partitions"] = strings.Join(partitions, ",")
+	}
+	if len(o.Segments) > 0 {
+		params["segments"] = strings.Join(o.Segments, ",")
+	}
+	if o.BatchSize > 0 {
+		params["batchSize"] = strconv.Itoa(o.BatchSize)
+	}
+	return params
+}
@@ -0,0 +1,7 @@
+package model
+
+type ForceCommitResponse struct {
+	ForceCommitStatus    string `json:"forceCommitStatus"`
+	JobMetaZKWriteStatus string `json:"jobMetaZKWriteStatus"`
+	ForceCommitJobId     string `json:"forceCommitJobId"`
+}
@@ -0,0 +1,25 @@
+package model
+
+// PartitionOffsetInfo holds the offsets and lag of the partitions a consuming segment reads from,
+// keyed by partition id. Values are strings as offsets are stream specific.
+type PartitionOffsetInfo struct {
+	CurrentOffsetsMap       map[string]string `json:"currentOffsetsMap"`
+	LatestUpstreamOffsetMap map[string]string `json:"latestUpstreamOffsetMap"`
+	RecordsLagMap           map[string]string `json:"recordsLagMap"`
+	AvailabilityLagMsMap    map[string]string `json:"availabilityLagMsMap"`
+}
+
+// ConsumingSegmentInfo is the state of a consuming segment on one server
+type ConsumingSegmentInfo struct {
+	ServerName            string              `json:"serverName"`
+	ConsumerState         string              `json:"consumerState"`
+	LastConsumedTimestamp int64               `json:"lastConsumedTimestamp"`
+	PartitionToOffsetMap  map[string]string   `json:"partitionToOffsetMap"`
+	PartitionOffsetInfo   PartitionOffsetInfo `json:"partitionOffsetInfo"`
+}
+
+type GetConsumingSegmentsInfoResponse struct {
+	ServersFailingToRespond  int                               `json:"serversFailingToRespond"`
+	ServersUnparsableRespond int                               `json:"serversUnparsableRespond"`
+	SegmentToConsumingInfo   map[string][]ConsumingSegmentInfo `json:"_segmentToConsumingInfoMap"`
+}
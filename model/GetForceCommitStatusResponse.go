@@ -0,0 +1,29 @@
+package model
+
+import "encoding/json"
+
+type GetForceCommitStatusResponse struct {
+	JobId            string `json:"jobId"`
+	JobType          string `json:"jobType"`
+	TableName        string `json:"tableName"`
+	SubmissionTimeMs string `json:"submissionTimeMs"`
+	// SegmentsForceCommitted is the json encoded list of segments the job force committed
+	SegmentsForceCommitted           string   `json:"segmentsForceCommitted"`
+	SegmentsYetToBeCommitted         []string `json:"segmentsYetToBeCommitted"`
+	NumberOfSegmentsYetToBeCommitted int      `json:"numberOfSegmentsYetToBeCommitted"`
+}
+
+// ForceCommittedSegments decodes the list of segments the job force committed
+func (r *GetForceCommitStatusResponse) ForceCommittedSegments() ([]string, error) {
+	var segments []string
+	if r.SegmentsForceCommitted == "" {
+		return segments, nil
+	}
+	err := json.Unmarshal([]byte(r.SegmentsForceCommitted), &segments)
+	return segments, err
+}
+
+// IsComplete returns true once every force committed segment has been committed
+func (r *GetForceCommitStatusResponse) IsComplete() bool {
+	return r.NumberOfSegmentsYetToBeCommitted == 0
+}
@@ -12,7 +12,7 @@ type GetInstanceResponse struct {
 	Enabled            bool               `json:"enabled"`
 	Port               string             `json:"port"`
 	Tags               []string           `json:"tags"`
-	Pools              []string           `json:"pools"`
+	Pools              map[string]string  `json:"pools"` // keyed by tag, the pool number of the instance for that tag
 	GRPCPort           int                `json:"grpcPort"`
 	AdminPort          int                `json:"adminPort"`
 	QueryServicePort   int                `json:"queryServicePort"`
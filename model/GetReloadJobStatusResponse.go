@@ -0,0 +1,33 @@
+package model
+
+type ReloadJobMetadata struct {
+	JobId            string `json:"jobId"`
+	JobType          string `json:"jobType"`
+	TableName        string `json:"tableName"`
+	SegmentName      string `json:"segmentName,omitempty"`
+	SubmissionTimeMs string `json:"submissionTimeMs"`
+	MessageCount     string `json:"messageCount,omitempty"`
+}
+
+type GetReloadJobStatusResponse struct {
+	TotalSegmentCount               int               `json:"totalSegmentCount"`
+	SuccessCount                    int               `json:"successCount"`
+	TotalServersQueried             int               `json:"totalServersQueried"`
+	TotalServerCallsFailed          int               `json:"totalServerCallsFailed"`
+	EstimatedTimeRemainingInMinutes float64           `json:"estimatedTimeRemainingInMinutes"`
+	TimeElapsedInMinutes            float64           `json:"timeElapsedInMinutes"`
+	Metadata                        ReloadJobMetadata `json:"metadata"`
+}
+
+// IsComplete returns true once every segment has been reloaded or a server call has failed,
+// check TotalServerCallsFailed to tell the two apart. A job reporting no segments is only complete
+// once servers have been queried, before that its counts have not been populated
+func (r *GetReloadJobStatusResponse) IsComplete() bool {
+	if r.TotalServerCallsFailed > 0 {
+		return true
+	}
+	if r.TotalSegmentCount == 0 {
+		return r.TotalServersQueried > 0
+	}
+	return r.SuccessCount >= r.TotalSegmentCount
+}
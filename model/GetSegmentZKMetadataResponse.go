@@ -9,6 +9,10 @@ type ZkSegmentMetadata struct {
 	SegmentEndTimeRaw   string `json:"segment.end.time.raw"`
 	SegmentIndexVersion string `json:"segment.index.version"`
 	SegmentPushTime     string `json:"segment.push.time"`
+	RealtimeStartOffset string `json:"segment.realtime.startOffset,omitempty"`
+	RealtimeEndOffset   string `json:"segment.realtime.endOffset,omitempty"`
+	RealtimeStatus      string `json:"segment.realtime.status,omitempty"`
+	RealtimeNumReplicas string `json:"segment.realtime.numReplicas,omitempty"`
 	SegmentSizeInBytes  string `json:"segment.size.bytes"`
 	SegmentStartTime    string `json:"segment.start.time"`
 	SegmentStartTimeRaw string `json:"segment.start.time.raw"`
@@ -0,0 +1,10 @@
+package model
+
+type PinotTaskConfig struct {
+	TaskType string            `json:"taskType"`
+	TaskId   string            `json:"taskId,omitempty"`
+	Configs  map[string]string `json:"configs"`
+}
+
+// GetSubtaskConfigsResponse maps subtask names to their config
+type GetSubtaskConfigsResponse map[string]PinotTaskConfig
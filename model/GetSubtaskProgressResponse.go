@@ -0,0 +1,5 @@
+package model
+
+// GetSubtaskProgressResponse maps subtask names to the progress reported by the minion running it,
+// the shape of the progress depends on the task type
+type GetSubtaskProgressResponse map[string]any
@@ -0,0 +1,31 @@
+package model
+
+type RebalanceStateStats struct {
+	SegmentsMissing                  int     `json:"_segmentsMissing"`
+	SegmentsToRebalance              int     `json:"_segmentsToRebalance"`
+	PercentSegmentsToRebalance       float64 `json:"_percentSegmentsToRebalance"`
+	ReplicasToRebalance              int     `json:"_replicasToRebalance"`
+	PercentRemainingReplicas         float64 `json:"_percentRemainingReplicasToRebalance"`
+	EstimatedTimeToCompleteInSeconds float64 `json:"_estimatedTimeToCompleteInSeconds"`
+}
+
+type TableRebalanceProgressStats struct {
+	Status                              string              `json:"status"`
+	StartTimeMs                         int64               `json:"startTimeMs"`
+	TimeToFinishInSeconds               int64               `json:"timeToFinishInSeconds"`
+	CompletionStatusMsg                 string              `json:"completionStatusMsg"`
+	InitialToTargetStateConvergence     RebalanceStateStats `json:"initialToTargetStateConvergence"`
+	CurrentToTargetConvergence          RebalanceStateStats `json:"currentToTargetConvergence"`
+	ExternalViewToIdealStateConvergence RebalanceStateStats `json:"externalViewToIdealStateConvergence"`
+}
+
+type GetTableRebalanceStatusResponse struct {
+	TableRebalanceProgressStats    TableRebalanceProgressStats `json:"tableRebalanceProgressStats"`
+	TimeElapsedSinceStartInSeconds int64                       `json:"timeElapsedSinceStartInSeconds"`
+}
+
+// IsComplete returns true once the rebalance job is no longer running
+func (r *GetTableRebalanceStatusResponse) IsComplete() bool {
+	status := r.TableRebalanceProgressStats.Status
+	return status != "" && status != RebalanceStatusInProgress
+}
@@ -0,0 +1,32 @@
+package model
+
+type TaskCount struct {
+	Waiting   int `json:"waiting"`
+	Error     int `json:"error"`
+	Running   int `json:"running"`
+	Completed int `json:"completed"`
+	Unknown   int `json:"unknown"`
+	Total     int `json:"total"`
+}
+
+type SubtaskDebugInfo struct {
+	TaskId      string           `json:"taskId"`
+	State       string           `json:"state"`
+	StartTime   string           `json:"startTime,omitempty"`
+	FinishTime  string           `json:"finishTime,omitempty"`
+	Participant string           `json:"participant,omitempty"`
+	Info        string           `json:"info,omitempty"`
+	TaskConfig  *PinotTaskConfig `json:"taskConfig,omitempty"`
+}
+
+type TaskDebugInfo struct {
+	TaskState          TaskState          `json:"taskState"`
+	SubtaskCount       TaskCount          `json:"subtaskCount"`
+	StartTime          string             `json:"startTime,omitempty"`
+	ExecutionStartTime string             `json:"executionStartTime,omitempty"`
+	FinishTime         string             `json:"finishTime,omitempty"`
+	SubtaskInfos       []SubtaskDebugInfo `json:"subtaskInfos,omitempty"`
+}
+
+// GetTasksDebugInfoResponse maps task names to their debug info
+type GetTasksDebugInfoResponse map[string]TaskDebugInfo
@@ -0,0 +1,10 @@
+package model
+
+type TaskGeneratorRunInfo struct {
+	TableNameWithType          string            `json:"tableNameWithType"`
+	TaskType                   string            `json:"taskType"`
+	MostRecentSuccessRunTS     []string          `json:"mostRecentSuccessRunTS"`
+	MostRecentErrorRunMessages map[string]string `json:"mostRecentErrorRunMessages"`
+}
+
+type GetTaskGeneratorDebugInfoResponse []TaskGeneratorRunInfo
@@ -0,0 +1,4 @@
+package model
+
+// GetTaskStatesResponse maps task names to their state
+type GetTaskStatesResponse map[string]TaskState
@@ -0,0 +1,22 @@
+package model
+
+type TenantRebalanceProgressStats struct {
+	StartTimeMs            int64             `json:"startTimeMs"`
+	TimeToFinishInSeconds  int64             `json:"timeToFinishInSeconds"`
+	CompletionStatusMsg    string            `json:"completionStatusMsg"`
+	TableStatusMap         map[string]string `json:"tableStatusMap"`
+	TotalTables            int               `json:"totalTables"`
+	RemainingTables        int               `json:"remainingTables"`
+	TableRebalanceJobIdMap map[string]string `json:"tableRebalanceJobIdMap"`
+}
+
+type GetTenantRebalanceStatusResponse struct {
+	TenantRebalanceProgressStats   TenantRebalanceProgressStats `json:"tenantRebalanceProgressStats"`
+	TimeElapsedSinceStartInSeconds int64                        `json:"timeElapsedSinceStartInSeconds"`
+}
+
+// IsComplete returns true once the controller has finished rebalancing every table of the tenant
+func (r *GetTenantRebalanceStatusResponse) IsComplete() bool {
+	stats := r.TenantRebalanceProgressStats
+	return stats.CompletionStatusMsg != "" || (stats.TotalTables > 0 && stats.RemainingTables == 0)
+}
@@ -1,7 +1,18 @@
 package model
 
+const (
+	InstanceStateEnable  = "ENABLE"
+	InstanceStateDisable = "DISABLE"
+)
+
 type Instance struct {
-	Host string `json:"host"`
-	Port int    `json:"port"`
-	Type string `json:"type"` // Can be CONTROLLER, BROKER, SERVER, MINION
+	Host             string         `json:"host"`
+	Port             int            `json:"port"`
+	Type             string         `json:"type"` // Can be CONTROLLER, BROKER, SERVER, MINION
+	Tags             []string       `json:"tags,omitempty"`
+	Pools            map[string]int `json:"pools,omitempty"`
+	GrpcPort         int            `json:"grpcPort,omitempty"`
+	AdminPort        int            `json:"adminPort,omitempty"`
+	QueryServicePort int            `json:"queryServicePort,omitempty"`
+	QueryMailboxPort int            `json:"queryMailboxPort,omitempty"`
 }
@@ -0,0 +1,24 @@
+package model
+
+import "strings"
+
+type InstanceType string
+
+const (
+	InstanceTypeController InstanceType = "CONTROLLER"
+	InstanceTypeBroker     InstanceType = "BROKER"
+	InstanceTypeServer     InstanceType = "SERVER"
+	InstanceTypeMinion     InstanceType = "MINION"
+)
+
+// InstanceTypeOf returns the type of an instance from the prefix of its name, e.g. Server_172.17.0.3_7050,
+// or an empty type if the prefix is not known
+func InstanceTypeOf(instanceName string) InstanceType {
+	prefix, _, _ := strings.Cut(instanceName, "_")
+	switch instanceType := InstanceType(strings.ToUpper(prefix)); instanceType {
+	case InstanceTypeController, InstanceTypeBroker, InstanceTypeServer, InstanceTypeMinion:
+		return instanceType
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+// LeadControllerEntry is a partition of the lead controller resource, with the controller leading it
+// and the tables it holds
+type LeadControllerEntry struct {
+	TableNames       []string `json:"tableNames"`
+	LeadControllerId string   `json:"leadControllerId"`
+}
+
+type LeadControllerResponse struct {
+	LeadControllerResourceEnabled bool                           `json:"leadControllerResourceEnabled"`
+	LeadControllerEntryMap        map[string]LeadControllerEntry `json:"leadControllerEntryMap"`
+}
+
+// LeaderForTable returns the id of the controller leading the table, false when the table has no leader
+func (r *LeadControllerResponse) LeaderForTable(tableName string) (string, bool) {
+	for _, entry := range r.LeadControllerEntryMap {
+		for _, name := range entry.TableNames {
+			if name == tableName && entry.LeadControllerId != "" {
+				return entry.LeadControllerId, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,630 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	TaskTypeMergeRollup               = "MergeRollupTask"
+	TaskTypeRealtimeToOfflineSegments = "RealtimeToOfflineSegmentsTask"
+	TaskTypePurge                     = "PurgeTask"
+	TaskTypeSegmentGenerationAndPush  = "SegmentGenerationAndPushTask"
+	TaskTypeUpsertCompaction          = "UpsertCompactionTask"
+	TaskTypeRefreshSegment            = "RefreshSegmentTask"
+)
+
+const (
+	MergeTypeConcat = "concat"
+	MergeTypeRollup = "rollup"
+	MergeTypeDedup  = "dedup"
+)
+
+// MinionTaskConfig is a typed config for a minion task type that can be converted to and from
+// the entry for that task type in Task.TaskTypeConfigsMap
+type MinionTaskConfig interface {
+	TaskType() string
+	ToMap() map[string]string
+	FromMap(configs map[string]string) error
+	Validate() error
+}
+
+// NewTask builds a table task config from typed minion task configs
+func NewTask(configs ...MinionTaskConfig) (*Task, error) {
+	task := &Task{TaskTypeConfigsMap: make(map[string]map[string]string)}
+	for _, config := range configs {
+		if err := task.SetTaskConfig(config); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// SetTaskConfig validates the config and sets it for its task type, replacing any existing config
+func (t *Task) SetTaskConfig(config MinionTaskConfig) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid %s config: %w", config.TaskType(), err)
+	}
+	if t.TaskTypeConfigsMap == nil {
+		t.TaskTypeConfigsMap = make(map[string]map[string]string)
+	}
+	t.TaskTypeConfigsMap[config.TaskType()] = config.ToMap()
+	return nil
+}
+
+// GetTaskConfig populates config from the entry for its task type, it returns false if the task
+// type is not configured
+func (t *Task) GetTaskConfig(config MinionTaskConfig) (bool, error) {
+	configs, ok := t.TaskTypeConfigsMap[config.TaskType()]
+	if !ok {
+		return false, nil
+	}
+	if err := config.FromMap(configs); err != nil {
+		return true, fmt.Errorf("unable to parse %s config: %w", config.TaskType(), err)
+	}
+	return true, nil
+}
+
+// MergeRollupLevelConfig is the config for a single merge level, e.g. 1day or 1month
+type MergeRollupLevelConfig struct {
+	MergeType               string
+	BucketTimePeriod        string
+	BufferTimePeriod        string
+	RoundBucketTimePeriod   string
+	MaxNumRecordsPerSegment int64
+	MaxNumRecordsPerTask    int64
+	MaxNumParallelBuckets   int64
+}
+
+type MergeRollupTaskConfig struct {
+	Schedule string
+	// Levels are keyed by merge level name, e.g. 1day
+	Levels map[string]MergeRollupLevelConfig
+	// AggregationTypes are keyed by metric column, e.g. sum, max or min
+	AggregationTypes map[string]string
+	// Extra holds any configs not covered by the fields above
+	Extra map[string]string
+}
+
+func (c *MergeRollupTaskConfig) TaskType() string {
+	return TaskTypeMergeRollup
+}
+
+func (c *MergeRollupTaskConfig) ToMap() map[string]string {
+	m := copyExtra(c.Extra)
+	setString(m, "schedule", c.Schedule)
+	for level, levelConfig := range c.Levels {
+		setString(m, level+".mergeType", levelConfig.MergeType)
+		setString(m, level+".bucketTimePeriod", levelConfig.BucketTimePeriod)
+		setString(m, level+".bufferTimePeriod", levelConfig.BufferTimePeriod)
+		setString(m, level+".roundBucketTimePeriod", levelConfig.RoundBucketTimePeriod)
+		setInt(m, level+".maxNumRecordsPerSegment", levelConfig.MaxNumRecordsPerSegment)
+		setInt(m, level+".maxNumRecordsPerTask", levelConfig.MaxNumRecordsPerTask)
+		setInt(m, level+".maxNumParallelBuckets", levelConfig.MaxNumParallelBuckets)
+	}
+	for column, aggregationType := range c.AggregationTypes {
+		setString(m, column+".aggregationType", aggregationType)
+	}
+	return m
+}
+
+func (c *MergeRollupTaskConfig) FromMap(configs map[string]string) error {
+	*c = MergeRollupTaskConfig{
+		Levels:           make(map[string]MergeRollupLevelConfig),
+		AggregationTypes: make(map[string]string),
+		Extra:            make(map[string]string),
+	}
+
+	for key, value := range configs {
+
+		if key == "schedule" {
+			c.Schedule = value
+			continue
+		}
+
+		prefix, property, found := cutLast(key, ".")
+		if !found {
+			c.Extra[key] = value
+			continue
+		}
+
+		if property == "aggregationType" {
+			c.AggregationTypes[prefix] = value
+			continue
+		}
+
+		levelConfig := c.Levels[prefix]
+		var err error
+		switch property {
+		case "mergeType":
+			levelConfig.MergeType = value
+		case "bucketTimePeriod":
+			levelConfig.BucketTimePeriod = value
+		case "bufferTimePeriod":
+			levelConfig.BufferTimePeriod = value
+		case "roundBucketTimePeriod":
+			levelConfig.RoundBucketTimePeriod = value
+		case "maxNumRecordsPerSegment":
+			levelConfig.MaxNumRecordsPerSegment, err = parseInt(key, value)
+		case "maxNumRecordsPerTask":
+			levelConfig.MaxNumRecordsPerTask, err = parseInt(key, value)
+		case "maxNumParallelBuckets":
+			levelConfig.MaxNumParallelBuckets, err = parseInt(key, value)
+		default:
+			c.Extra[key] = value
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		c.Levels[prefix] = levelConfig
+	}
+
+	return nil
+}
+
+func (c *MergeRollupTaskConfig) Validate() error {
+
+	if len(c.Levels) == 0 {
+		return fmt.Errorf("at least one merge level is required")
+	}
+
+	levels := make([]string, 0, len(c.Levels))
+	for level := range c.Levels {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+
+	for _, level := range levels {
+		levelConfig := c.Levels[level]
+		if err := validateMergeType(levelConfig.MergeType); err != nil {
+			return fmt.Errorf("level %s: %w", level, err)
+		}
+		if err := validateRequiredPeriod("bucketTimePeriod", levelConfig.BucketTimePeriod); err != nil {
+			return fmt.Errorf("level %s: %w", level, err)
+		}
+		if err := validateRequiredPeriod("bufferTimePeriod", levelConfig.BufferTimePeriod); err != nil {
+			return fmt.Errorf("level %s: %w", level, err)
+		}
+		if err := validateOptionalPeriod("roundBucketTimePeriod", levelConfig.RoundBucketTimePeriod); err != nil {
+			return fmt.Errorf("level %s: %w", level, err)
+		}
+	}
+
+	return validateAggregationTypes(c.AggregationTypes)
+}
+
+type RealtimeToOfflineSegmentsTaskConfig struct {
+	Schedule                string
+	BucketTimePeriod        string
+	BufferTimePeriod        string
+	RoundBucketTimePeriod   string
+	MergeType               string
+	MaxNumRecordsPerSegment int64
+	// AggregationTypes are keyed by metric column, e.g. sum, max or min
+	AggregationTypes map[string]string
+	// Extra holds any configs not covered by the fields above
+	Extra map[string]string
+}
+
+func (c *RealtimeToOfflineSegmentsTaskConfig) TaskType() string {
+	return TaskTypeRealtimeToOfflineSegments
+}
+
+func (c *RealtimeToOfflineSegmentsTaskConfig) ToMap() map[string]string {
+	m := copyExtra(c.Extra)
+	setString(m, "schedule", c.Schedule)
+	setString(m, "bucketTimePeriod", c.BucketTimePeriod)
+	setString(m, "bufferTimePeriod", c.BufferTimePeriod)
+	setString(m, "roundBucketTimePeriod", c.RoundBucketTimePeriod)
+	setString(m, "mergeType", c.MergeType)
+	setInt(m, "maxNumRecordsPerSegment", c.MaxNumRecordsPerSegment)
+	for column, aggregationType := range c.AggregationTypes {
+		setString(m, column+".aggregationType", aggregationType)
+	}
+	return m
+}
+
+func (c *RealtimeToOfflineSegmentsTaskConfig) FromMap(configs map[string]string) error {
+	*c = RealtimeToOfflineSegmentsTaskConfig{
+		AggregationTypes: make(map[string]string),
+		Extra:            make(map[string]string),
+	}
+
+	var err error
+	for key, value := range configs {
+		switch key {
+		case "schedule":
+			c.Schedule = value
+		case "bucketTimePeriod":
+			c.BucketTimePeriod = value
+		case "bufferTimePeriod":
+			c.BufferTimePeriod = value
+		case "roundBucketTimePeriod":
+			c.RoundBucketTimePeriod = value
+		case "mergeType":
+			c.MergeType = value
+		case "maxNumRecordsPerSegment":
+			c.MaxNumRecordsPerSegment, err = parseInt(key, value)
+		default:
+			if column, found := strings.CutSuffix(key, ".aggregationType"); found {
+				c.AggregationTypes[column] = value
+			} else {
+				c.Extra[key] = value
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *RealtimeToOfflineSegmentsTaskConfig) Validate() error {
+	// all fields are optional, pinot defaults to 1d buckets with a 2d buffer and concat merge
+	if c.MergeType != "" {
+		if err := validateMergeType(c.MergeType); err != nil {
+			return err
+		}
+	}
+	if err := validateOptionalPeriod("bucketTimePeriod", c.BucketTimePeriod); err != nil {
+		return err
+	}
+	if err := validateOptionalPeriod("bufferTimePeriod", c.BufferTimePeriod); err != nil {
+		return err
+	}
+	if err := validateOptionalPeriod("roundBucketTimePeriod", c.RoundBucketTimePeriod); err != nil {
+		return err
+	}
+	return validateAggregationTypes(c.AggregationTypes)
+}
+
+type PurgeTaskConfig struct {
+	Schedule                     string
+	LastPurgeTimeThresholdPeriod string
+	// Extra holds any configs not covered by the fields above
+	Extra map[string]string
+}
+
+func (c *PurgeTaskConfig) TaskType() string {
+	return TaskTypePurge
+}
+
+func (c *PurgeTaskConfig) ToMap() map[string]string {
+	m := copyExtra(c.Extra)
+	setString(m, "schedule", c.Schedule)
+	setString(m, "lastPurgeTimeThresholdPeriod", c.LastPurgeTimeThresholdPeriod)
+	return m
+}
+
+func (c *PurgeTaskConfig) FromMap(configs map[string]string) error {
+	*c = PurgeTaskConfig{Extra: make(map[string]string)}
+	for key, value := range configs {
+		switch key {
+		case "schedule":
+			c.Schedule = value
+		case "lastPurgeTimeThresholdPeriod":
+			c.LastPurgeTimeThresholdPeriod = value
+		default:
+			c.Extra[key] = value
+		}
+	}
+	return nil
+}
+
+func (c *PurgeTaskConfig) Validate() error {
+	return validateOptionalPeriod("lastPurgeTimeThresholdPeriod", c.LastPurgeTimeThresholdPeriod)
+}
+
+type SegmentGenerationAndPushTaskConfig struct {
+	Schedule               string
+	InputDirURI            string
+	InputFormat            string
+	InputFsClassName       string
+	IncludeFileNamePattern string
+	ExcludeFileNamePattern string
+	OutputDirURI           string
+	OverwriteOutput        *bool
+	PushMode               string
+	PushControllerURI      string
+	TableMaxNumTasks       int64
+	// Extra holds any configs not covered by the fields above, e.g. input.fs.prop.* or recordReader.prop.*
+	Extra map[string]string
+}
+
+func (c *SegmentGenerationAndPushTaskConfig) TaskType() string {
+	return TaskTypeSegmentGenerationAndPush
+}
+
+func (c *SegmentGenerationAndPushTaskConfig) ToMap() map[string]string {
+	m := copyExtra(c.Extra)
+	setString(m, "schedule", c.Schedule)
+	setString(m, "inputDirURI", c.InputDirURI)
+	setString(m, "inputFormat", c.InputFormat)
+	setString(m, "input.fs.className", c.InputFsClassName)
+	setString(m, "includeFileNamePattern", c.IncludeFileNamePattern)
+	setString(m, "excludeFileNamePattern", c.ExcludeFileNamePattern)
+	setString(m, "outputDirURI", c.OutputDirURI)
+	if c.OverwriteOutput != nil {
+		m["overwriteOutput"] = strconv.FormatBool(*c.OverwriteOutput)
+	}
+	setString(m, "push.mode", c.PushMode)
+	setString(m, "push.controllerUri", c.PushControllerURI)
+	setInt(m, "tableMaxNumTasks", c.TableMaxNumTasks)
+	return m
+}
+
+func (c *SegmentGenerationAndPushTaskConfig) FromMap(configs map[string]string) error {
+	*c = SegmentGenerationAndPushTaskConfig{Extra: make(map[string]string)}
+
+	var err error
+	for key, value := range configs {
+		switch key {
+		case "schedule":
+			c.Schedule = value
+		case "inputDirURI":
+			c.InputDirURI = value
+		case "inputFormat":
+			c.InputFormat = value
+		case "input.fs.className":
+			c.InputFsClassName = value
+		case "includeFileNamePattern":
+			c.IncludeFileNamePattern = value
+		case "excludeFileNamePattern":
+			c.ExcludeFileNamePattern = value
+		case "outputDirURI":
+			c.OutputDirURI = value
+		case "overwriteOutput":
+			overwriteOutput, parseErr := strconv.ParseBool(value)
+			if parseErr != nil {
+				return fmt.Errorf("invalid value for %s: %w", key, parseErr)
+			}
+			c.OverwriteOutput = &overwriteOutput
+		case "push.mode":
+			c.PushMode = value
+		case "push.controllerUri":
+			c.PushControllerURI = value
+		case "tableMaxNumTasks":
+			c.TableMaxNumTasks, err = parseInt(key, value)
+		default:
+			c.Extra[key] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *SegmentGenerationAndPushTaskConfig) Validate() error {
+	switch strings.ToLower(c.PushMode) {
+	case "", "tar", "uri", "metadata":
+	default:
+		return fmt.Errorf("invalid push.mode %s, must be one of tar, uri or metadata", c.PushMode)
+	}
+	return nil
+}
+
+const (
+	ValidDocIdsTypeSnapshot           = "SNAPSHOT"
+	ValidDocIdsTypeInMemory           = "IN_MEMORY"
+	ValidDocIdsTypeInMemoryWithDelete = "IN_MEMORY_WITH_DELETE"
+)
+
+type UpsertCompactionTaskConfig struct {
+	Schedule                       string
+	BufferTimePeriod               string
+	InvalidRecordsThresholdPercent float64
+	InvalidRecordsThresholdCount   int64
+	ValidDocIdsType                string
+	TableMaxNumTasks               int64
+	// Extra holds any configs not covered by the fields above
+	Extra map[string]string
+}
+
+func (c *UpsertCompactionTaskConfig) TaskType() string {
+	return TaskTypeUpsertCompaction
+}
+
+func (c *UpsertCompactionTaskConfig) ToMap() map[string]string {
+	m := copyExtra(c.Extra)
+	setString(m, "schedule", c.Schedule)
+	setString(m, "bufferTimePeriod", c.BufferTimePeriod)
+	if c.InvalidRecordsThresholdPercent != 0 {
+		m["invalidRecordsThresholdPercent"] = strconv.FormatFloat(c.InvalidRecordsThresholdPercent, 'f', -1, 64)
+	}
+	setInt(m, "invalidRecordsThresholdCount", c.InvalidRecordsThresholdCount)
+	setString(m, "validDocIdsType", c.ValidDocIdsType)
+	setInt(m, "tableMaxNumTasks", c.TableMaxNumTasks)
+	return m
+}
+
+func (c *UpsertCompactionTaskConfig) FromMap(configs map[string]string) error {
+	*c = UpsertCompactionTaskConfig{Extra: make(map[string]string)}
+
+	var err error
+	for key, value := range configs {
+		switch key {
+		case "schedule":
+			c.Schedule = value
+		case "bufferTimePeriod":
+			c.BufferTimePeriod = value
+		case "invalidRecordsThresholdPercent":
+			c.InvalidRecordsThresholdPercent, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				err = fmt.Errorf("invalid value for %s: %w", key, err)
+			}
+		case "invalidRecordsThresholdCount":
+			c.InvalidRecordsThresholdCount, err = parseInt(key, value)
+		case "validDocIdsType":
+			c.ValidDocIdsType = value
+		case "tableMaxNumTasks":
+			c.TableMaxNumTasks, err = parseInt(key, value)
+		default:
+			c.Extra[key] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *UpsertCompactionTaskConfig) Validate() error {
+	if err := validateOptionalPeriod("bufferTimePeriod", c.BufferTimePeriod); err != nil {
+		return err
+	}
+	if c.InvalidRecordsThresholdPercent < 0 || c.InvalidRecordsThresholdPercent > 100 {
+		return fmt.Errorf("invalidRecordsThresholdPercent must be between 0 and 100, got %v", c.InvalidRecordsThresholdPercent)
+	}
+	if c.InvalidRecordsThresholdCount < 0 {
+		return fmt.Errorf("invalidRecordsThresholdCount must not be negative, got %d", c.InvalidRecordsThresholdCount)
+	}
+	switch c.ValidDocIdsType {
+	case "", ValidDocIdsTypeSnapshot, ValidDocIdsTypeInMemory, ValidDocIdsTypeInMemoryWithDelete:
+	default:
+		return fmt.Errorf("invalid validDocIdsType %s", c.ValidDocIdsType)
+	}
+	return nil
+}
+
+type RefreshSegmentTaskConfig struct {
+	Schedule              string
+	TableMaxNumTasks      int64
+	MaxNumSegmentsPerTask int64
+	// Extra holds any configs not covered by the fields above
+	Extra map[string]string
+}
+
+func (c *RefreshSegmentTaskConfig) TaskType() string {
+	return TaskTypeRefreshSegment
+}
+
+func (c *RefreshSegmentTaskConfig) ToMap() map[string]string {
+	m := copyExtra(c.Extra)
+	setString(m, "schedule", c.Schedule)
+	setInt(m, "tableMaxNumTasks", c.TableMaxNumTasks)
+	setInt(m, "taskMaxNumSegmentsPerTask", c.MaxNumSegmentsPerTask)
+	return m
+}
+
+func (c *RefreshSegmentTaskConfig) FromMap(configs map[string]string) error {
+	*c = RefreshSegmentTaskConfig{Extra: make(map[string]string)}
+
+	var err error
+	for key, value := range configs {
+		switch key {
+		case "schedule":
+			c.Schedule = value
+		case "tableMaxNumTasks":
+			c.TableMaxNumTasks, err = parseInt(key, value)
+		case "taskMaxNumSegmentsPerTask":
+			c.MaxNumSegmentsPerTask, err = parseInt(key, value)
+		default:
+			c.Extra[key] = value
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *RefreshSegmentTaskConfig) Validate() error {
+	if c.TableMaxNumTasks < 0 {
+		return fmt.Errorf("tableMaxNumTasks must not be negative, got %d", c.TableMaxNumTasks)
+	}
+	if c.MaxNumSegmentsPerTask < 0 {
+		return fmt.Errorf("taskMaxNumSegmentsPerTask must not be negative, got %d", c.MaxNumSegmentsPerTask)
+	}
+	return nil
+}
+
+// periodPattern matches pinot periods such as 1d, 12h or 1d12h30m
+var periodPattern = regexp.MustCompile(`^(\d+[dD])?(\d+[hH])?(\d+[mM])?(\d+[sS])?$`)
+
+// ValidatePeriod checks that period is in the format pinot expects for task time periods, e.g. 1d or 6h
+func ValidatePeriod(period string) error {
+	if period == "" || !periodPattern.MatchString(period) {
+		return fmt.Errorf("invalid period %q, expected a period such as 1d, 12h or 1d12h", period)
+	}
+	return nil
+}
+
+func validateRequiredPeriod(name string, period string) error {
+	if period == "" {
+		return fmt.Errorf("%s is required", name)
+	}
+	return validateOptionalPeriod(name, period)
+}
+
+func validateOptionalPeriod(name string, period string) error {
+	if period == "" {
+		return nil
+	}
+	if err := ValidatePeriod(period); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func validateMergeType(mergeType string) error {
+	switch strings.ToLower(mergeType) {
+	case MergeTypeConcat, MergeTypeRollup, MergeTypeDedup:
+		return nil
+	default:
+		return fmt.Errorf("invalid mergeType %q, must be one of concat, rollup or dedup", mergeType)
+	}
+}
+
+func validateAggregationTypes(aggregationTypes map[string]string) error {
+	for column, aggregationType := range aggregationTypes {
+		if aggregationType == "" {
+			return fmt.Errorf("aggregationType for column %s is empty", column)
+		}
+	}
+	return nil
+}
+
+func copyExtra(extra map[string]string) map[string]string {
+	m := make(map[string]string, len(extra))
+	for key, value := range extra {
+		m[key] = value
+	}
+	return m
+}
+
+func setString(m map[string]string, key string, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
+
+func setInt(m map[string]string, key string, value int64) {
+	if value != 0 {
+		m[key] = strconv.FormatInt(value, 10)
+	}
+}
+
+func parseInt(key string, value string) (int64, error) {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+func cutLast(s string, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
@@ -0,0 +1,16 @@
+package model
+
+const (
+	ConsumeFromSmallest = "smallest"
+	ConsumeFromLargest  = "largest"
+)
+
+// PauseStatus is returned when pausing or resuming consumption of a realtime table
+type PauseStatus struct {
+	PauseFlag         bool     `json:"pauseFlag"`
+	ConsumingSegments []string `json:"consumingSegments"`
+	Description       string   `json:"description,omitempty"`
+	ReasonCode        string   `json:"reasonCode,omitempty"`
+	Comment           string   `json:"comment,omitempty"`
+	Timestamp         string   `json:"timestamp,omitempty"`
+}
@@ -0,0 +1,47 @@
+package model
+
+const (
+	RebalanceStatusNoOp       = "NO_OP"
+	RebalanceStatusDone       = "DONE"
+	RebalanceStatusFailed     = "FAILED"
+	RebalanceStatusInProgress = "IN_PROGRESS"
+	RebalanceStatusAborted    = "ABORTED"
+	RebalanceStatusCancelled  = "CANCELLED"
+)
+
+type InstancePartitions struct {
+	InstancePartitionsName  string              `json:"instancePartitionsName"`
+	PartitionToInstancesMap map[string][]string `json:"partitionToInstancesMap"`
+}
+
+type RebalanceResult struct {
+	JobId       string `json:"jobId"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	// InstanceAssignment is keyed by instance partitions type, e.g. OFFLINE, CONSUMING or COMPLETED
+	InstanceAssignment map[string]InstancePartitions `json:"instanceAssignment,omitempty"`
+	// TierInstanceAssignment is keyed by tier name
+	TierInstanceAssignment map[string]InstancePartitions `json:"tierInstanceAssignment,omitempty"`
+	// SegmentAssignment is the target assignment of segments to instances and their states
+	SegmentAssignment map[string]map[string]string `json:"segmentAssignment,omitempty"`
+}
+
+// SegmentMovement is a segment whose assigned instances change during a rebalance
+type SegmentMovement struct {
+	SegmentName      string   `json:"segmentName"`
+	CurrentInstances []string `json:"currentInstances"`
+	TargetInstances  []string `json:"targetInstances"`
+	InstancesAdded   []string `json:"instancesAdded,omitempty"`
+	InstancesRemoved []string `json:"instancesRemoved,omitempty"`
+}
+
+// RebalancePlan is the result of a dry run rebalance compared with the current ideal state
+type RebalancePlan struct {
+	Result            RebalanceResult   `json:"result"`
+	SegmentMovements  []SegmentMovement `json:"segmentMovements"`
+	TotalSegments     int               `json:"totalSegments"`
+	SegmentsToMove    int               `json:"segmentsToMove"`
+	ReplicasToAdd     int               `json:"replicasToAdd"`
+	ReplicasToRemove  int               `json:"replicasToRemove"`
+	InstancesInvolved []string          `json:"instancesInvolved"`
+}
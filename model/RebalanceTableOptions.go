@@ -0,0 +1,34 @@
+package model
+
+import "strconv"
+
+// RebalanceTableOptions are the options for rebalancing a table, see the pinot documentation on
+// rebalancing servers for the meaning of each
+type RebalanceTableOptions struct {
+	DryRun            bool
+	ReassignInstances bool
+	IncludeConsuming  bool
+	Bootstrap         bool
+	Downtime          bool
+	// MinAvailableReplicas is the number of replicas kept online during a no-downtime rebalance, or the number
+	// of replicas allowed to be offline if negative, pinot defaults to 1 when nil
+	MinAvailableReplicas *int
+	BestEfforts          bool
+	LowDiskMode          bool
+}
+
+func (o RebalanceTableOptions) QueryParams() map[string]string {
+	params := map[string]string{
+		"dryRun":            strconv.FormatBool(o.DryRun),
+		"reassignInstances": strconv.FormatBool(o.ReassignInstances),
+		"includeConsuming":  strconv.FormatBool(o.IncludeConsuming),
+		"bootstrap":         strconv.FormatBool(o.Bootstrap),
+		"downtime":          strconv.FormatBool(o.Downtime),
+		"bestEfforts":       strconv.FormatBool(o.BestEfforts),
+		"lowDiskMode":       strconv.FormatBool(o.LowDiskMode),
+	}
+	if o.MinAvailableReplicas != nil {
+		params["minAvailableReplicas"] = strconv.Itoa(*o.MinAvailableReplicas)
+	}
+	return params
+}
@@ -0,0 +1,6 @@
+package model
+
+type RebalanceTenantResponse struct {
+	JobId                 string                     `json:"jobId"`
+	RebalanceTableResults map[string]RebalanceResult `json:"rebalanceTableResults"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type ReloadJob struct {
+	ReloadJobId                  string      `json:"reloadJobId"`
+	ReloadJobMetaZKStorageStatus string      `json:"reloadJobMetaZKStorageStatus"`
+	NumMessagesSent              json.Number `json:"numMessagesSent"`
+}
+
+// ParseReloadJobs extracts the reload jobs, keyed by table name with type, from the status
+// returned when reloading all segments of a table
+func ParseReloadJobs(status string) (map[string]ReloadJob, error) {
+	var jobs map[string]ReloadJob
+	err := json.Unmarshal([]byte(status), &jobs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse reload jobs from status %q: %w", status, err)
+	}
+	return jobs, nil
+}
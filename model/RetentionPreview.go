@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// RetentionPreviewSegment is a segment that would be purged under a proposed retention
+type RetentionPreviewSegment struct {
+	SegmentName string    `json:"segmentName"`
+	EndTime     time.Time `json:"endTime"`
+	AgeInDays   float64   `json:"ageInDays"`
+	SizeInBytes int64     `json:"sizeInBytes"`
+	TotalDocs   int64     `json:"totalDocs"`
+	SegmentTier string    `json:"segmentTier,omitempty"`
+}
+
+// RetentionPreview is the result of previewing a retention change for a table
+type RetentionPreview struct {
+	TableName          string                    `json:"tableName"`
+	RetentionTimeUnit  string                    `json:"retentionTimeUnit"`
+	RetentionTimeValue string                    `json:"retentionTimeValue"`
+	Cutoff             time.Time                 `json:"cutoff"`
+	TotalSegments      int                       `json:"totalSegments"`
+	SkippedSegments    []string                  `json:"skippedSegments,omitempty"`
+	PurgedSegments     []RetentionPreviewSegment `json:"purgedSegments"`
+	PurgedSizeInBytes  int64                     `json:"purgedSizeInBytes"`
+	TableSizeInBytes   int64                     `json:"tableSizeInBytes"`
+}
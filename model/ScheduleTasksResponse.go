@@ -0,0 +1,4 @@
+package model
+
+// ScheduleTasksResponse maps each scheduled task type to the comma separated names of the tasks created
+type ScheduleTasksResponse map[string]string
@@ -0,0 +1,9 @@
+package model
+
+// TableConvergence compares the ideal state of a table with its external view
+type TableConvergence struct {
+	Converged bool `json:"converged"`
+	// PendingSegments maps segments to the instances whose external view state differs from the ideal state,
+	// with the ideal state of each
+	PendingSegments map[string]map[string]string `json:"pendingSegments,omitempty"`
+}
@@ -0,0 +1,59 @@
+package model
+
+type HealthStatus string
+
+const (
+	HealthStatusHealthy   HealthStatus = "HEALTHY"
+	HealthStatusDegraded  HealthStatus = "DEGRADED"
+	HealthStatusUnhealthy HealthStatus = "UNHEALTHY"
+)
+
+// Worse returns the more severe of the two statuses
+func (s HealthStatus) Worse(other HealthStatus) HealthStatus {
+	if s.severity() >= other.severity() {
+		return s
+	}
+	return other
+}
+
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthStatusHealthy:
+		return 0
+	case HealthStatusDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// SegmentReplicaState is a replica whose external view state differs from what its ideal state expects
+type SegmentReplicaState struct {
+	TableType         string `json:"tableType"`
+	SegmentName       string `json:"segmentName"`
+	InstanceName      string `json:"instanceName"`
+	IdealState        string `json:"idealState"`
+	ExternalViewState string `json:"externalViewState,omitempty"`
+}
+
+// UnderReplicatedSegment is a segment with fewer serving replicas than the table replication
+type UnderReplicatedSegment struct {
+	TableType        string `json:"tableType"`
+	SegmentName      string `json:"segmentName"`
+	ExpectedReplicas int    `json:"expectedReplicas"`
+	ServingReplicas  int    `json:"servingReplicas"`
+}
+
+// TableHealth summarises the health of a table, Status is UNHEALTHY when a segment has no serving
+// replica and DEGRADED for any other issue
+type TableHealth struct {
+	TableName               string                   `json:"tableName"`
+	Status                  HealthStatus             `json:"status"`
+	TotalSegments           int                      `json:"totalSegments"`
+	MissingSegments         []string                 `json:"missingSegments,omitempty"`
+	UnavailableSegments     []string                 `json:"unavailableSegments,omitempty"`
+	ErrorReplicas           []SegmentReplicaState    `json:"errorReplicas,omitempty"`
+	OfflineReplicas         []SegmentReplicaState    `json:"offlineReplicas,omitempty"`
+	UnderReplicatedSegments []UnderReplicatedSegment `json:"underReplicatedSegments,omitempty"`
+	DisabledServers         []string                 `json:"disabledServers,omitempty"`
+}
@@ -0,0 +1,27 @@
+package model
+
+// TaskState is the state of a minion task or task queue as reported by helix
+type TaskState string
+
+const (
+	TaskStateNotStarted TaskState = "NOT_STARTED"
+	TaskStateInProgress TaskState = "IN_PROGRESS"
+	TaskStateStopping   TaskState = "STOPPING"
+	TaskStateStopped    TaskState = "STOPPED"
+	TaskStateAborted    TaskState = "ABORTED"
+	TaskStateCompleted  TaskState = "COMPLETED"
+	TaskStateFailing    TaskState = "FAILING"
+	TaskStateFailed     TaskState = "FAILED"
+	TaskStateTimingOut  TaskState = "TIMING_OUT"
+	TaskStateTimedOut   TaskState = "TIMED_OUT"
+)
+
+// IsFinal returns true if the task can no longer change state
+func (s TaskState) IsFinal() bool {
+	switch s {
+	case TaskStateStopped, TaskStateAborted, TaskStateCompleted, TaskStateFailed, TaskStateTimedOut:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,30 @@
+package model
+
+// ServerCapacity is the data a server holds for its tenant's tables and the resources it has
+type ServerCapacity struct {
+	InstanceName    string `json:"instanceName"`
+	Enabled         bool   `json:"enabled"`
+	DataSizeInBytes int64  `json:"dataSizeInBytes"`
+	// Segments counts segment replicas, so a segment with two replicas counts once on each of its servers
+	Segments      int      `json:"segments"`
+	Tables        []string `json:"tables"`
+	NumCores      int      `json:"numCores"`
+	TotalMemoryMB int64    `json:"totalMemoryMB"`
+	MaxHeapSizeMB int64    `json:"maxHeapSizeMB"`
+}
+
+// TenantCapacityReport summarises how much data the servers of a tenant hold and how evenly it is spread.
+// Skew is the largest server's share divided by the average, 1 when perfectly balanced.
+type TenantCapacityReport struct {
+	TenantName                 string           `json:"tenantName"`
+	NumServers                 int              `json:"numServers"`
+	Tables                     []string         `json:"tables"`
+	TotalDataSizeInBytes       int64            `json:"totalDataSizeInBytes"`
+	AverageDataSizePerServer   int64            `json:"averageDataSizePerServer"`
+	TotalSegments              int              `json:"totalSegments"`
+	DataSizeSkew               float64          `json:"dataSizeSkew"`
+	SegmentSkew                float64          `json:"segmentSkew"`
+	TotalMemoryMB              int64            `json:"totalMemoryMB"`
+	DataSizeInBytesPerMemoryMB float64          `json:"dataSizeInBytesPerMemoryMB,omitempty"`
+	Servers                    []ServerCapacity `json:"servers"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// TenantRebalanceConfig is the body of a tenant rebalance, see the pinot documentation on
+// rebalancing servers for the meaning of each option
+type TenantRebalanceConfig struct {
+	TenantName          string   `json:"tenantName,omitempty"`
+	DegreeOfParallelism int      `json:"degreeOfParallelism,omitempty"`
+	ParallelWhitelist   []string `json:"parallelWhitelist,omitempty"`
+	ParallelBlacklist   []string `json:"parallelBlacklist,omitempty"`
+	VerboseResult       bool     `json:"verboseResult,omitempty"`
+	DryRun              bool     `json:"dryRun"`
+	ReassignInstances   bool     `json:"reassignInstances"`
+	IncludeConsuming    bool     `json:"includeConsuming"`
+	Bootstrap           bool     `json:"bootstrap"`
+	Downtime            bool     `json:"downtime"`
+	// MinAvailableReplicas is the number of replicas kept online during a no-downtime rebalance, or the number
+	// of replicas allowed to be offline if negative, pinot defaults to 1 when nil
+	MinAvailableReplicas *int `json:"minAvailableReplicas,omitempty"`
+	BestEfforts          bool `json:"bestEfforts"`
+	LowDiskMode          bool `json:"lowDiskMode"`
+}
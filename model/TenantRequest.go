@@ -0,0 +1,47 @@
+package model
+
+import "fmt"
+
+const (
+	TenantRoleBroker = "BROKER"
+	TenantRoleServer = "SERVER"
+)
+
+// TenantRequest creates or updates a tenant, OfflineInstances and RealtimeInstances only apply to server tenants
+// and are the number of its servers tagged for offline and realtime tables
+type TenantRequest struct {
+	TenantRole        string `json:"tenantRole"`
+	TenantName        string `json:"tenantName"`
+	NumberOfInstances int    `json:"numberOfInstances"`
+	OfflineInstances  int    `json:"offlineInstances,omitempty"`
+	RealtimeInstances int    `json:"realtimeInstances,omitempty"`
+}
+
+func (r TenantRequest) Validate() error {
+
+	if r.TenantName == "" {
+		return fmt.Errorf("tenant name is required")
+	}
+
+	if r.NumberOfInstances <= 0 {
+		return fmt.Errorf("tenant %s must have at least one instance", r.TenantName)
+	}
+
+	switch r.TenantRole {
+	case TenantRoleBroker:
+		if r.OfflineInstances != 0 || r.RealtimeInstances != 0 {
+			return fmt.Errorf("broker tenant %s can not have offline or realtime instances", r.TenantName)
+		}
+	case TenantRoleServer:
+		if r.OfflineInstances < 0 || r.RealtimeInstances < 0 {
+			return fmt.Errorf("server tenant %s can not have a negative number of offline or realtime instances", r.TenantName)
+		}
+		if r.OfflineInstances > r.NumberOfInstances || r.RealtimeInstances > r.NumberOfInstances {
+			return fmt.Errorf("server tenant %s can not have more offline or realtime instances than its %d instances", r.TenantName, r.NumberOfInstances)
+		}
+	default:
+		return fmt.Errorf("tenant role must be %s or %s, got %s", TenantRoleBroker, TenantRoleServer, r.TenantRole)
+	}
+
+	return nil
+}
@@ -1,11 +1,38 @@
 package model
 
+// UserComponent is the component a user can be granted access to
+type UserComponent string
+
+const (
+	UserComponentController UserComponent = "CONTROLLER"
+	UserComponentBroker     UserComponent = "BROKER"
+	UserComponentServer     UserComponent = "SERVER"
+	UserComponentMinion     UserComponent = "MINION"
+)
+
+type UserRole string
+
+const (
+	UserRoleAdmin UserRole = "ADMIN"
+	UserRoleUser  UserRole = "USER"
+)
+
+// Permission is an action a USER role can be granted on its tables
+type Permission string
+
+const (
+	PermissionRead   Permission = "READ"
+	PermissionCreate Permission = "CREATE"
+	PermissionUpdate Permission = "UPDATE"
+	PermissionDelete Permission = "DELETE"
+)
+
 type User struct {
-	Username              string    `json:"username"`
-	Password              string    `json:"password"`
-	Component             string    `json:"component"`
-	Role                  string    `json:"role"`
-	UsernameWithComponent string    `json:"usernameWithComponent"`
-	Permissions           *[]string `json:"permissions,omitempty"` // optional for adding permissions etc
-	Tables                *[]string `json:"tables,omitempty"`
+	Username              string        `json:"username"`
+	Password              string        `json:"password"`
+	Component             UserComponent `json:"component"`
+	Role                  UserRole      `json:"role"`
+	UsernameWithComponent string        `json:"usernameWithComponent"`
+	Permissions           []Permission  `json:"permissions,omitempty"` // optional for adding permissions etc
+	Tables                []string      `json:"tables,omitempty"`      // tables the permissions apply to, all tables when empty
 }
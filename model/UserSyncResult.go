@@ -0,0 +1,10 @@
+package model
+
+// UserSyncResult lists the users, by username with component, that a sync created, updated or deleted
+type UserSyncResult struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Deleted   []string `json:"deleted"`
+	Unchanged []string `json:"unchanged"`
+	DryRun    bool     `json:"dryRun,omitempty"`
+}
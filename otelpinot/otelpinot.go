@@ -0,0 +1,143 @@
+// Package otelpinot instruments the go-pinot-api client with OpenTelemetry tracing and metrics.
+//
+//	client := goPinotAPI.NewPinotAPIClient(
+//		goPinotAPI.ControllerUrl(controllerUrl),
+//		goPinotAPI.Instrument(otelpinot.New()),
+//	)
+package otelpinot
+
+import (
+	"context"
+	"net/http"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/azaurus1/go-pinot-api/otelpinot"
+
+// Attribute keys set on spans and metrics, in addition to the semantic convention http keys
+const (
+	OperationKey  = attribute.Key("pinot.operation")
+	TableNameKey  = attribute.Key("pinot.table.name")
+	RetryCountKey = attribute.Key("pinot.retry.count")
+)
+
+// Option configures the instrumentation
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider, the global one by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = provider }
+}
+
+// WithMeterProvider sets the meter provider, the global one by default
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = provider }
+}
+
+// WithPropagator sets the propagator used to send trace context to the controller, W3C trace
+// context and baggage by default
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) { c.propagator = propagator }
+}
+
+type instrumentation struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	duration   metric.Float64Histogram
+	errors     metric.Int64Counter
+}
+
+// New returns instrumentation that records a client span per API call, named after the client
+// method, propagates trace context to the controller, and records call latency and error counts
+func New(opts ...Option) goPinotAPI.Instrumentation {
+
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagator:     propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+
+	// instruments fall back to no-ops when the meter provider can't create them
+	duration, err := meter.Float64Histogram("pinot.client.request.duration",
+		metric.WithDescription("Duration of calls to the Pinot controller"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	errors, err := meter.Int64Counter("pinot.client.request.errors",
+		metric.WithDescription("Number of failed calls to the Pinot controller"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return &instrumentation{
+		tracer:     cfg.tracerProvider.Tracer(instrumentationName),
+		propagator: cfg.propagator,
+		duration:   duration,
+		errors:     errors,
+	}
+}
+
+func (i *instrumentation) Start(ctx context.Context, op goPinotAPI.Operation, header http.Header) (context.Context, func(goPinotAPI.OperationResult)) {
+
+	attrs := []attribute.KeyValue{
+		OperationKey.String(op.Name),
+		attribute.String("http.request.method", op.Method),
+	}
+	if op.TableName != "" {
+		attrs = append(attrs, TableNameKey.String(op.TableName))
+	}
+
+	ctx, span := i.tracer.Start(ctx, op.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(attribute.String("url.path", op.Path)),
+	)
+
+	i.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+
+	return ctx, func(result goPinotAPI.OperationResult) {
+
+		if result.StatusCode != 0 {
+			status := attribute.Int("http.response.status_code", result.StatusCode)
+			attrs = append(attrs, status)
+			span.SetAttributes(status)
+		}
+
+		span.SetAttributes(RetryCountKey.Int(result.Retries))
+		if result.Err != nil {
+			span.RecordError(result.Err)
+			span.SetStatus(codes.Error, result.Err.Error())
+		}
+		span.End()
+
+		if i.duration != nil {
+			i.duration.Record(ctx, result.Duration.Seconds(), metric.WithAttributes(attrs...))
+		}
+		if i.errors != nil && result.Err != nil {
+			i.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package otelpinot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInstrumentation(t *testing.T) {
+	var traceparents []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tables/airlineStats", func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		fmt.Fprint(w, `{"OFFLINE": {"tableName": "airlineStats_OFFLINE", "tableType": "OFFLINE"}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.Instrument(New(WithTracerProvider(tracerProvider), WithMeterProvider(meterProvider))),
+	)
+
+	_, err := client.GetTable("airlineStats")
+	assert.NoError(t, err)
+
+	_, err = client.GetSchema("missing")
+	assert.Error(t, err)
+
+	ended := spans.Ended()
+	if assert.Len(t, ended, 2) {
+		getTable := ended[0]
+		assert.Equal(t, "GetTable", getTable.Name())
+		assert.Equal(t, trace.SpanKindClient, getTable.SpanKind())
+		assert.Contains(t, getTable.Attributes(), TableNameKey.String("airlineStats"))
+		assert.Contains(t, getTable.Attributes(), attribute.String("http.request.method", http.MethodGet))
+		assert.Contains(t, getTable.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+		assert.Contains(t, getTable.Attributes(), RetryCountKey.Int(0))
+
+		assert.Equal(t, []string{fmt.Sprintf("00-%s-%s-01", getTable.SpanContext().TraceID(), getTable.SpanContext().SpanID())}, traceparents,
+			"Expected W3C trace context to be propagated")
+
+		getSchema := ended[1]
+		assert.Equal(t, "GetSchema", getSchema.Name())
+		assert.Equal(t, codes.Error, getSchema.Status().Code)
+		assert.Contains(t, getSchema.Attributes(), attribute.Int("http.response.status_code", http.StatusNotFound))
+	}
+
+	var metrics metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &metrics))
+
+	recorded := map[string]metricdata.Aggregation{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			recorded[m.Name] = m.Data
+		}
+	}
+
+	if duration, ok := recorded["pinot.client.request.duration"].(metricdata.Histogram[float64]); assert.True(t, ok) {
+		assert.Len(t, duration.DataPoints, 2, "Expected a latency series per operation")
+	}
+	if errors, ok := recorded["pinot.client.request.errors"].(metricdata.Sum[int64]); assert.True(t, ok) {
+		if assert.Len(t, errors.DataPoints, 1) {
+			assert.Equal(t, int64(1), errors.DataPoints[0].Value)
+		}
+	}
+}
+
+func TestInstrumentationDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("traceparent"), "Expected no trace context without instrumentation")
+		fmt.Fprint(w, `{"OFFLINE": {"tableName": "airlineStats_OFFLINE"}}`)
+	}))
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(server.URL))
+
+	_, err := client.GetTable("airlineStats")
+	assert.NoError(t, err)
+}
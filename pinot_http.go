@@ -1,6 +1,8 @@
 package goPinotAPI
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 )
@@ -8,12 +10,79 @@ import (
 type pinotHttp struct {
 	httpClient         *http.Client
 	pinotControllerUrl *url.URL
-	httpAuthWriter     httpAuthWriter
+	credentials        CredentialProvider
+	middlewares        []Middleware
+	readLimiter        *limiter
+	mutationLimiter    *limiter
 }
 
-type httpAuthWriter func(*http.Request)
-
 func (p *pinotHttp) Do(req *http.Request) (*http.Response, error) {
-	p.httpAuthWriter(req)
-	return p.httpClient.Do(req)
+
+	handler := p.send
+	for i := len(p.middlewares) - 1; i >= 0; i-- {
+		handler = p.middlewares[i](handler)
+	}
+
+	return p.limit(req, handler)
+}
+
+// send applies the credentials and sends the request, retrying once with refreshed credentials
+// when the controller responds with 401
+func (p *pinotHttp) send(req *http.Request) (*http.Response, error) {
+
+	if err := p.authorize(req); err != nil {
+		return nil, err
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil || res.StatusCode != http.StatusUnauthorized || p.credentials == nil {
+		return res, err
+	}
+
+	// the request can only be sent again if its body can be replayed
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return res, nil
+	}
+
+	if err := p.credentials.Refresh(req.Context()); err != nil {
+		if !errors.Is(err, ErrCredentialsNotRefreshable) {
+			res.Body.Close()
+			return nil, fmt.Errorf("client: could not refresh credentials: %w", err)
+		}
+		return res, nil
+	}
+	res.Body.Close()
+	countRetry(req.Context())
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("client: could not replay request body: %w", err)
+		}
+	}
+
+	if err := p.authorize(retry); err != nil {
+		return nil, err
+	}
+
+	return p.httpClient.Do(retry)
+}
+
+func (p *pinotHttp) authorize(req *http.Request) error {
+
+	if p.credentials == nil {
+		return nil
+	}
+
+	authorization, err := p.credentials.Authorization(req.Context())
+	if err != nil {
+		return fmt.Errorf("client: could not get credentials: %w", err)
+	}
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	return nil
 }
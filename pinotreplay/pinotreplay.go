@@ -0,0 +1,394 @@
+// Package pinotreplay records the interactions of a go-pinot-api client with a real controller into
+// fixture files and replays them, so tests can run without a live Pinot cluster.
+//
+//	recorder, err := pinotreplay.New("testdata/create_table.json", pinotreplay.Options{Mode: pinotreplay.ModeFromEnv()})
+//	...
+//	client := goPinotAPI.NewPinotAPIClient(
+//		goPinotAPI.ControllerUrl("http://localhost:9000"),
+//		goPinotAPI.Transport(recorder),
+//	)
+//	...
+//	err = recorder.Save()
+package pinotreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode is whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay serves recorded interactions and fails on requests that were not recorded
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the controller and records them
+	ModeRecord
+)
+
+// EnvRecord is the environment variable that switches ModeFromEnv to recording
+const EnvRecord = "PINOT_REPLAY_RECORD"
+
+// ModeFromEnv returns ModeRecord when PINOT_REPLAY_RECORD is set to a non empty value other than 0 or
+// false, and ModeReplay otherwise
+func ModeFromEnv() Mode {
+	switch strings.ToLower(os.Getenv(EnvRecord)) {
+	case "", "0", "false":
+		return ModeReplay
+	default:
+		return ModeRecord
+	}
+}
+
+// DefaultRedactedHeaders are the headers whose values are never written to fixtures
+var DefaultRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization", "X-Api-Key"}
+
+// DefaultRedactedFields are the JSON request body fields whose values are never written to fixtures
+var DefaultRedactedFields = []string{"password"}
+
+const redacted = "REDACTED"
+
+// ErrUnexpectedRequest is returned in replay mode for requests that have no recorded interaction
+var ErrUnexpectedRequest = errors.New("pinotreplay: unexpected request")
+
+// Request is a recorded request
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a request and the response the controller gave to it
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Fixture is the content of a fixture file
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Options configures a Recorder
+type Options struct {
+	Mode Mode
+	// RedactHeaders are the request and response headers recorded as REDACTED,
+	// DefaultRedactedHeaders when nil
+	RedactHeaders []string
+	// RedactBody returns the request body written to the fixture, requests are replayed by matching
+	// them to recorded ones after redacting them the same way. RedactJSONFields(DefaultRedactedFields...)
+	// when nil
+	RedactBody func(Request) string
+	// Transport sends requests in record mode, http.DefaultTransport by default
+	Transport http.RoundTripper
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions with the controller
+type Recorder struct {
+	path    string
+	options Options
+
+	mu      sync.Mutex
+	fixture Fixture
+	used    []bool
+}
+
+// New returns a Recorder for the fixture file at path. In replay mode the fixture is loaded from the
+// file, in record mode it is written to the file by Save
+func New(path string, options Options) (*Recorder, error) {
+
+	if options.RedactHeaders == nil {
+		options.RedactHeaders = DefaultRedactedHeaders
+	}
+	if options.RedactBody == nil {
+		options.RedactBody = RedactJSONFields(DefaultRedactedFields...)
+	}
+	if options.Transport == nil {
+		options.Transport = http.DefaultTransport
+	}
+
+	r := &Recorder{path: path, options: options}
+
+	if options.Mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("pinotreplay: unable to read fixture: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.fixture); err != nil {
+			return nil, fmt.Errorf("pinotreplay: invalid fixture %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.fixture.Interactions))
+	}
+
+	return r, nil
+}
+
+// RoundTrip records or replays a request depending on the mode of the recorder
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	req, body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.options.Mode == ModeRecord {
+		return r.record(req, body)
+	}
+
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+
+	res, err := r.options.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("pinotreplay: unable to read response body: %w", err)
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fixture.Interactions = append(r.fixture.Interactions, Interaction{
+		Request: r.recordedRequest(req, body),
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     r.redact(res.Header),
+			Body:       string(resBody),
+		},
+	})
+
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := r.recordedRequest(req, body)
+
+	// repeated requests are answered in the order they were recorded
+	for i, interaction := range r.fixture.Interactions {
+		if r.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+
+		r.used[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrUnexpectedRequest, req.Method, req.URL.RequestURI())
+}
+
+// Save writes the recorded interactions to the fixture file, it does nothing in replay mode
+func (r *Recorder) Save() error {
+
+	if r.options.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("pinotreplay: unable to marshal fixture: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("pinotreplay: unable to create fixture directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("pinotreplay: unable to write fixture: %w", err)
+	}
+
+	return nil
+}
+
+// Unused returns the recorded interactions that were not replayed, so tests can check that every
+// expected call was made
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.fixture.Interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) redact(header http.Header) http.Header {
+
+	redactedHeader := header.Clone()
+	for _, name := range r.options.RedactHeaders {
+		if _, ok := redactedHeader[http.CanonicalHeaderKey(name)]; ok {
+			redactedHeader.Set(name, redacted)
+		}
+	}
+
+	return redactedHeader
+}
+
+// recordedRequest is a request as it is written to the fixture, with its headers and body redacted
+func (r *Recorder) recordedRequest(req *http.Request, body []byte) Request {
+
+	recorded := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  normalizeQuery(req.URL.RawQuery),
+		Header: r.redact(req.Header),
+		Body:   string(body),
+	}
+	recorded.Body = r.options.RedactBody(recorded)
+
+	return recorded
+}
+
+// RedactJSONFields returns a RedactBody that records the values of the named fields of a JSON body as
+// REDACTED at any depth, bodies without them or that are not JSON are recorded as they are
+func RedactJSONFields(fields ...string) func(Request) string {
+	return func(req Request) string {
+
+		decoder := json.NewDecoder(strings.NewReader(req.Body))
+		decoder.UseNumber()
+
+		var body any
+		if decoder.Decode(&body) != nil || !redactFields(body, fields) {
+			return req.Body
+		}
+
+		redactedBody, err := json.Marshal(body)
+		if err != nil {
+			return req.Body
+		}
+		return string(redactedBody)
+	}
+}
+
+// redactFields replaces the values of the named fields in a decoded JSON value, it reports whether any
+// field was replaced
+func redactFields(value any, fields []string) bool {
+
+	replaced := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if containsFold(fields, key) {
+				v[key] = redacted
+				replaced = true
+			} else if redactFields(field, fields) {
+				replaced = true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if redactFields(item, fields) {
+				replaced = true
+			}
+		}
+	}
+
+	return replaced
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (req Request) matches(other Request) bool {
+	return req.Method == other.Method &&
+		req.Path == other.Path &&
+		req.Query == other.Query &&
+		equalBodies([]byte(req.Body), []byte(other.Body))
+}
+
+// equalBodies compares JSON bodies ignoring formatting, and other bodies byte for byte
+func equalBodies(recorded []byte, body []byte) bool {
+
+	var compactRecorded, compactBody bytes.Buffer
+	if json.Compact(&compactRecorded, recorded) == nil && json.Compact(&compactBody, body) == nil {
+		return bytes.Equal(compactRecorded.Bytes(), compactBody.Bytes())
+	}
+
+	return bytes.Equal(recorded, body)
+}
+
+func (res Response) toHTTP(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        res.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(res.Body)),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}
+}
+
+// normalizeQuery sorts the query parameters so the order they were added in doesn't matter
+func normalizeQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
+// readRequestBody reads the body of a request and returns a clone of the request with the body restored
+// for sending, as a RoundTripper must not modify the request it is given
+func readRequestBody(req *http.Request) (*http.Request, []byte, error) {
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("pinotreplay: unable to read request body: %w", err)
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	clone.ContentLength = int64(len(body))
+
+	return clone, body, nil
+}
@@ -0,0 +1,200 @@
+package pinotreplay_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/azaurus1/go-pinot-api/pinotreplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func createControllerServer() *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/cluster/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+	})
+
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"username":"liam"`) {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"status": "User liam_BROKER has been successfully added!"}`)
+	})
+
+	mux.HandleFunc("/users/liam", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"code": 404, "error": "User liam_SERVER does not exist"}`, http.StatusNotFound)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func createClient(url string, recorder *pinotreplay.Recorder) *goPinotAPI.PinotAPIClient {
+	return goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(url),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.Transport(recorder),
+	)
+}
+
+func runInteractions(t *testing.T, client *goPinotAPI.PinotAPIClient) {
+	t.Helper()
+
+	info, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "PinotCluster", info.ClusterName)
+
+	_, err = client.CreateUser(model.User{Username: "liam", Password: "secret", Component: model.UserComponentBroker, Role: model.UserRoleUser})
+	assert.NoError(t, err)
+
+	_, err = client.GetUser("liam", "SERVER")
+	var reqErr *goPinotAPI.RequestError
+	assert.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, http.StatusNotFound, reqErr.StatusCode)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "testdata", "users.json")
+
+	server := createControllerServer()
+
+	recorder, err := pinotreplay.New(fixture, pinotreplay.Options{Mode: pinotreplay.ModeRecord})
+	assert.NoError(t, err)
+
+	runInteractions(t, createClient(server.URL, recorder))
+	assert.NoError(t, recorder.Save())
+
+	server.Close()
+
+	data, err := os.ReadFile(fixture)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "YWRtaW46YWRtaW4K")
+	assert.NotContains(t, string(data), "session=secret")
+	assert.NotContains(t, string(data), `\"password\":\"secret\"`, "Expected the user's password not to be recorded")
+	assert.Contains(t, string(data), `\"password\":\"REDACTED\"`)
+	assert.Contains(t, string(data), "REDACTED")
+
+	replayer, err := pinotreplay.New(fixture, pinotreplay.Options{})
+	assert.NoError(t, err)
+
+	runInteractions(t, createClient(server.URL, replayer))
+	assert.Empty(t, replayer.Unused())
+}
+
+func TestRecordRedactBody(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "users.json")
+
+	server := createControllerServer()
+	defer server.Close()
+
+	redactBody := func(req pinotreplay.Request) string {
+		if req.Path == "/users" {
+			return `{"username":"liam"}`
+		}
+		return req.Body
+	}
+
+	recorder, err := pinotreplay.New(fixture, pinotreplay.Options{Mode: pinotreplay.ModeRecord, RedactBody: redactBody})
+	assert.NoError(t, err)
+
+	runInteractions(t, createClient(server.URL, recorder))
+	assert.NoError(t, recorder.Save())
+
+	data, err := os.ReadFile(fixture)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "password")
+
+	replayer, err := pinotreplay.New(fixture, pinotreplay.Options{RedactBody: redactBody})
+	assert.NoError(t, err)
+
+	runInteractions(t, createClient(server.URL, replayer))
+	assert.Empty(t, replayer.Unused())
+}
+
+type requestBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *requestBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestRecordLeavesRequestUntouched(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "users.json")
+
+	server := createControllerServer()
+	defer server.Close()
+
+	recorder, err := pinotreplay.New(fixture, pinotreplay.Options{Mode: pinotreplay.ModeRecord})
+	assert.NoError(t, err)
+
+	body := &requestBody{Reader: strings.NewReader(`{"username":"liam"}`)}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/users", body)
+	assert.NoError(t, err)
+
+	res, err := recorder.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode, "Expected the body to be sent to the controller")
+	assert.Same(t, body, req.Body, "Expected the caller's request body not to be replaced")
+	assert.True(t, body.closed, "Expected the caller's request body to be closed")
+}
+
+func TestReplayUnexpectedRequest(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "cluster.json")
+
+	err := os.WriteFile(fixture, []byte(`{
+  "interactions": [
+    {
+      "request": {"method": "GET", "path": "/cluster/info"},
+      "response": {"statusCode": 200, "body": "{\"clusterName\": \"PinotCluster\"}"}
+    }
+  ]
+}`), 0o644)
+	assert.NoError(t, err)
+
+	replayer, err := pinotreplay.New(fixture, pinotreplay.Options{})
+	assert.NoError(t, err)
+
+	client := createClient("http://localhost:9000", replayer)
+
+	_, err = client.GetTables()
+	assert.True(t, errors.Is(err, pinotreplay.ErrUnexpectedRequest), "unexpected error: %v", err)
+	assert.Len(t, replayer.Unused(), 1)
+
+	_, err = client.GetClusterInfo()
+	assert.NoError(t, err)
+
+	// each interaction is replayed once
+	_, err = client.GetClusterInfo()
+	assert.ErrorIs(t, err, pinotreplay.ErrUnexpectedRequest)
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	_, err := pinotreplay.New(filepath.Join(t.TempDir(), "missing.json"), pinotreplay.Options{})
+	assert.Error(t, err)
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(pinotreplay.EnvRecord, "")
+	assert.Equal(t, pinotreplay.ModeReplay, pinotreplay.ModeFromEnv())
+
+	t.Setenv(pinotreplay.EnvRecord, "false")
+	assert.Equal(t, pinotreplay.ModeReplay, pinotreplay.ModeFromEnv())
+
+	t.Setenv(pinotreplay.EnvRecord, "1")
+	assert.Equal(t, pinotreplay.ModeRecord, pinotreplay.ModeFromEnv())
+}
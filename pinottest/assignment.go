@@ -0,0 +1,325 @@
+package pinottest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// assignment maps the segments of a table to the servers they are assigned to
+type assignment map[string][]string
+
+// replication is the number of replicas of each segment of the table, pinot defaults to 1 when it is not set
+func (t *table) replication() int {
+
+	replication := t.config.SegmentsConfig.Replication
+	if t.config.TableType == tableTypeRealtime && t.config.SegmentsConfig.ReplicasPerPartition != "" {
+		replication = t.config.SegmentsConfig.ReplicasPerPartition
+	}
+
+	replicas, err := strconv.Atoi(replication)
+	if err != nil || replicas < 1 {
+		return 1
+	}
+	return replicas
+}
+
+// tableServers returns the servers tagged for the server tenant and type of a table, e.g. DefaultTenant_OFFLINE
+func (c *Controller) tableServers(t *table) []string {
+	return c.instancesWithTag(t.serverTenant() + "_" + t.config.TableType)
+}
+
+// assign returns the servers for a segment: the current ones that are still candidates, then the candidates
+// holding the fewest segments of the assignment, up to replicas
+func (a assignment) assign(current []string, candidates []string, replicas int) []string {
+
+	servers := []string{}
+	for _, server := range current {
+		if slices.Contains(candidates, server) && len(servers) < replicas {
+			servers = append(servers, server)
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, segmentServers := range a {
+		for _, server := range segmentServers {
+			counts[server]++
+		}
+	}
+
+	available := slices.DeleteFunc(slices.Clone(candidates), func(server string) bool {
+		return slices.Contains(servers, server)
+	})
+	sort.SliceStable(available, func(i, j int) bool {
+		return counts[available[i]] < counts[available[j]]
+	})
+
+	for _, server := range available {
+		if len(servers) >= replicas {
+			break
+		}
+		servers = append(servers, server)
+	}
+
+	slices.Sort(servers)
+	return servers
+}
+
+// targetAssignment is the assignment a rebalance moves a table to, segments stay on the servers still tagged
+// for the table and replicas are added to the tagged servers with the fewest segments
+func (c *Controller) targetAssignment(t *table) (assignment, error) {
+
+	current := c.segments[t.config.TableName]
+	candidates := c.tableServers(t)
+	if len(candidates) == 0 && len(current) > 0 {
+		return nil, fmt.Errorf("no instances found with tag: %s_%s", t.serverTenant(), t.config.TableType)
+	}
+
+	target := make(assignment)
+	for _, segmentName := range sortedKeys(current) {
+		target[segmentName] = target.assign(current[segmentName], candidates, t.replication())
+	}
+
+	return target, nil
+}
+
+func (a assignment) equal(b assignment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for segmentName, servers := range a {
+		if !slices.Equal(servers, b[segmentName]) {
+			return false
+		}
+	}
+	return true
+}
+
+// externalView returns the state of each replica of the segments of a table, leaving out replicas on
+// disabled servers and segments without any other replica
+func (c *Controller) externalView(tableNameWithType string) map[string]map[string]string {
+
+	states := make(map[string]map[string]string)
+	for segmentName, replicas := range c.segments[tableNameWithType].states() {
+		for server := range replicas {
+			if i, ok := c.instances[server]; !ok || !i.enabled {
+				delete(replicas, server)
+			}
+		}
+		if len(replicas) > 0 {
+			states[segmentName] = replicas
+		}
+	}
+
+	return states
+}
+
+// servers returns the servers holding any segment of the assignment
+func (a assignment) servers() []string {
+
+	var servers []string
+	for _, segmentServers := range a {
+		for _, server := range segmentServers {
+			if !slices.Contains(servers, server) {
+				servers = append(servers, server)
+			}
+		}
+	}
+
+	slices.Sort(servers)
+	return servers
+}
+
+// states returns the state of each replica of the assignment, every replica is ONLINE
+func (a assignment) states() map[string]map[string]string {
+
+	states := make(map[string]map[string]string)
+	for segmentName, servers := range a {
+		states[segmentName] = make(map[string]string)
+		for _, server := range servers {
+			states[segmentName][server] = "ONLINE"
+		}
+	}
+
+	return states
+}
+
+func (c *Controller) getIdealState(w http.ResponseWriter, r *http.Request, path []string) {
+	c.writeTableStates(w, r, path, false)
+}
+
+func (c *Controller) getExternalView(w http.ResponseWriter, r *http.Request, path []string) {
+	c.writeTableStates(w, r, path, true)
+}
+
+// writeTableStates writes the ideal state or external view of each type of a table, null for the types
+// the table doesn't have. The external view leaves out replicas on disabled servers
+func (c *Controller) writeTableStates(w http.ResponseWriter, r *http.Request, path []string, external bool) {
+
+	found := c.findTables(path[0], r.URL.Query().Get("tableType"))
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	result := map[string]any{tableTypeOffline: nil, tableTypeRealtime: nil}
+	for _, tableNameWithType := range found {
+		_, tableType := splitTableNameWithType(tableNameWithType)
+		if external {
+			result[tableType] = c.externalView(tableNameWithType)
+		} else {
+			result[tableType] = c.segments[tableNameWithType].states()
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// rebalanceTable moves the segments of a table to its target assignment. A dry run returns the target
+// without moving anything, otherwise the segments move at once and the job reports the rebalance as done.
+// Like the controller, a rebalance without downtime fails unless more replicas than minAvailableReplicas
+// stay up
+func (c *Controller) rebalanceTable(w http.ResponseWriter, r *http.Request, path []string) {
+
+	query := r.URL.Query()
+	tableType := strings.ToUpper(query.Get("type"))
+	if tableType != tableTypeOffline && tableType != tableTypeRealtime {
+		writeError(w, http.StatusBadRequest, "Table type must be %s or %s", tableTypeOffline, tableTypeRealtime)
+		return
+	}
+
+	tableName, _ := splitTableNameWithType(path[0])
+	t, ok := c.tables[tableName+"_"+tableType]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Table %s_%s does not exist", tableName, tableType)
+		return
+	}
+
+	target, err := c.targetAssignment(t)
+	if err != nil {
+		writeJSON(w, http.StatusOK, model.RebalanceResult{
+			Status:      model.RebalanceStatusFailed,
+			Description: fmt.Sprintf("Caught exception while calculating target assignment: %s", err),
+		})
+		return
+	}
+
+	partitionsType := tableType
+	if tableType == tableTypeRealtime {
+		partitionsType = "CONSUMING"
+	}
+
+	result := model.RebalanceResult{
+		InstanceAssignment: map[string]model.InstancePartitions{
+			partitionsType: {
+				InstancePartitionsName:  tableName + "_" + partitionsType,
+				PartitionToInstancesMap: map[string][]string{"0_0": c.tableServers(t)},
+			},
+		},
+		SegmentAssignment: target.states(),
+	}
+
+	current := c.segments[t.config.TableName]
+	minAvailable := minAvailableReplicas(query.Get("minAvailableReplicas"), t.replication())
+	switch {
+	case target.equal(current):
+		result.Status = model.RebalanceStatusNoOp
+		result.Description = "Table is already balanced"
+	case query.Get("dryRun") == "true":
+		result.Status = model.RebalanceStatusDone
+		result.Description = "Dry-run mode"
+	case query.Get("downtime") != "true" && minAvailable >= t.replication():
+		result.Status = model.RebalanceStatusFailed
+		result.Description = fmt.Sprintf("Illegal config for minAvailableReplicas: %d for no-downtime rebalance, must be less than number of replicas: %d",
+			minAvailable, t.replication())
+	default:
+		result.JobId = c.addRebalanceJob(current, target)
+		result.Status = model.RebalanceStatusInProgress
+		result.Description = "In progress, check controller task status for the progress"
+		c.segments[t.config.TableName] = target
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// minAvailableReplicas is the number of replicas a rebalance without downtime keeps up, 1 by default, a
+// negative value is the number of replicas allowed to be down
+func minAvailableReplicas(value string, replication int) int {
+
+	replicas, err := strconv.Atoi(value)
+	if err != nil {
+		return 1
+	}
+	if replicas < 0 {
+		return max(replication+replicas, 0)
+	}
+	return replicas
+}
+
+// addRebalanceJob records a finished rebalance from current to target, returning its id
+func (c *Controller) addRebalanceJob(current assignment, target assignment) string {
+
+	var stats model.RebalanceStateStats
+	for segmentName, servers := range target {
+		added := 0
+		for _, server := range servers {
+			if !slices.Contains(current[segmentName], server) {
+				added++
+			}
+		}
+		if added > 0 {
+			stats.SegmentsToRebalance++
+			stats.ReplicasToRebalance += added
+		}
+	}
+	if len(target) > 0 {
+		stats.PercentSegmentsToRebalance = 100 * float64(stats.SegmentsToRebalance) / float64(len(target))
+	}
+
+	jobId := newJobId()
+	c.rebalanceJobs[jobId] = model.GetTableRebalanceStatusResponse{
+		TableRebalanceProgressStats: model.TableRebalanceProgressStats{
+			Status:                          model.RebalanceStatusDone,
+			StartTimeMs:                     time.Now().UnixMilli(),
+			CompletionStatusMsg:             "Finished rebalancing table",
+			InitialToTargetStateConvergence: stats,
+		},
+	}
+
+	return jobId
+}
+
+// cancelTableRebalance returns the ids of the cancelled rebalance jobs, rebalances finish as soon as they
+// start so there are never any
+func (c *Controller) cancelTableRebalance(w http.ResponseWriter, r *http.Request, path []string) {
+
+	if len(c.findTables(path[0], r.URL.Query().Get("type"))) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []string{})
+}
+
+func (c *Controller) serveRebalanceStatus(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 1, handler: c.getRebalanceStatus},
+	})
+}
+
+func (c *Controller) getRebalanceStatus(w http.ResponseWriter, r *http.Request, path []string) {
+
+	job, ok := c.rebalanceJobs[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Failed to find controller job id: %s", path[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
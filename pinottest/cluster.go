@@ -0,0 +1,56 @@
+package pinottest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+func (c *Controller) serveCluster(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 1, action: "info", handler: c.getClusterInfo},
+		{method: http.MethodGet, segments: 1, action: "configs", handler: c.getClusterConfigs},
+		{method: http.MethodPost, segments: 1, action: "configs", handler: c.updateClusterConfigs},
+		{method: http.MethodDelete, segments: 2, handler: c.deleteClusterConfig},
+	})
+}
+
+func (c *Controller) getClusterInfo(w http.ResponseWriter, r *http.Request, path []string) {
+	writeJSON(w, http.StatusOK, model.GetClusterResponse{ClusterName: c.options.ClusterName})
+}
+
+func (c *Controller) getClusterConfigs(w http.ResponseWriter, r *http.Request, path []string) {
+	writeJSON(w, http.StatusOK, c.clusterConfigs)
+}
+
+// updateClusterConfigs sets the configs in the body, leaving the others unchanged
+func (c *Controller) updateClusterConfigs(w http.ResponseWriter, r *http.Request, path []string) {
+
+	var configs map[string]any
+	if !decodeBody(w, r, &configs) {
+		return
+	}
+
+	for name, value := range configs {
+		c.clusterConfigs[name] = fmt.Sprint(value)
+	}
+
+	writeStatus(w, "Updated cluster config.")
+}
+
+func (c *Controller) deleteClusterConfig(w http.ResponseWriter, r *http.Request, path []string) {
+
+	if path[0] != "configs" {
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+		return
+	}
+
+	if _, ok := c.clusterConfigs[path[1]]; !ok {
+		writeError(w, http.StatusNotFound, "Cluster config %s not found", path[1])
+		return
+	}
+
+	delete(c.clusterConfigs, path[1])
+	writeStatus(w, "Deleted cluster config: %s", path[1])
+}
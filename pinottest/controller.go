@@ -0,0 +1,281 @@
+// Package pinottest provides an in memory fake of the Pinot controller REST API for tests that use
+// go-pinot-api without a live cluster.
+//
+// The fake keeps state across requests and behaves like a controller for schemas, tables, users,
+// tenants, instances, segments and cluster configs: missing objects return 404, creating an object
+// that exists returns 409, a schema can't be deleted while a table uses it, and tables are stored by
+// name with type, e.g. airlineStats_OFFLINE.
+//
+// Segments are assigned to the servers tagged for the server tenant of their table, up to its
+// replication, and served as the ideal state and external view of the table. Rebalances and reloads
+// finish as soon as they are submitted, their jobs report them as done.
+//
+//	controller := pinottest.NewController(pinottest.Options{})
+//	defer controller.Close()
+//
+//	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(controller.URL))
+//
+// Endpoints the fake doesn't model, such as tasks or table sizes, return 404. Use Handle to serve
+// them, or to make a modeled endpoint fail:
+//
+//	controller.Handle(http.MethodGet, "/tables/airlineStats/size", func(w http.ResponseWriter, r *http.Request) {
+//		fmt.Fprint(w, `{"tableName": "airlineStats", "reportedSizeInBytes": 4000}`)
+//	})
+package pinottest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// DefaultTenant is the broker and server tenant that exists in every cluster
+const DefaultTenant = "DefaultTenant"
+
+const (
+	tableTypeOffline  = "OFFLINE"
+	tableTypeRealtime = "REALTIME"
+)
+
+// DefaultClusterConfigs are the cluster configs of a new fake controller
+var DefaultClusterConfigs = map[string]string{
+	"allowParticipantAutoJoin":                 "true",
+	"enable.case.insensitive":                  "true",
+	"default.hyperloglog.log2m":                "8",
+	"pinot.broker.enable.query.limit.override": "false",
+}
+
+// Options configures a fake controller
+type Options struct {
+	// ClusterName is returned by /cluster/info, defaults to PinotCluster
+	ClusterName string
+	// Authorization is the Authorization header every request must carry, e.g. "Basic YWRtaW46YWRtaW4K",
+	// requests are not authenticated when empty
+	Authorization string
+}
+
+// Controller is a fake Pinot controller served by an httptest.Server
+type Controller struct {
+	*httptest.Server
+
+	options Options
+
+	mu             sync.Mutex
+	schemas        map[string]json.RawMessage
+	tables         map[string]*table
+	users          map[string]*user
+	brokerTenants  map[string]bool
+	serverTenants  map[string]bool
+	instances      map[string]*instance
+	segments       map[string]assignment
+	clusterConfigs map[string]string
+	rebalanceJobs  map[string]model.GetTableRebalanceStatusResponse
+	reloadJobs     map[string]model.GetReloadJobStatusResponse
+	handlers       map[string]http.HandlerFunc
+}
+
+// NewController starts a fake controller, Close it when the test is done
+func NewController(options Options) *Controller {
+
+	if options.ClusterName == "" {
+		options.ClusterName = "PinotCluster"
+	}
+
+	c := &Controller{
+		options:        options,
+		schemas:        make(map[string]json.RawMessage),
+		tables:         make(map[string]*table),
+		users:          make(map[string]*user),
+		brokerTenants:  map[string]bool{DefaultTenant: true},
+		serverTenants:  map[string]bool{DefaultTenant: true},
+		instances:      make(map[string]*instance),
+		segments:       make(map[string]assignment),
+		clusterConfigs: make(map[string]string),
+		rebalanceJobs:  make(map[string]model.GetTableRebalanceStatusResponse),
+		reloadJobs:     make(map[string]model.GetReloadJobStatusResponse),
+		handlers:       make(map[string]http.HandlerFunc),
+	}
+
+	for name, value := range DefaultClusterConfigs {
+		c.clusterConfigs[name] = value
+	}
+
+	c.Server = httptest.NewServer(c)
+	return c
+}
+
+// ServeHTTP serves the controller REST API
+func (c *Controller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	// health checks are not authenticated
+	switch r.URL.Path {
+	case "/health":
+		writeText(w, "OK")
+		return
+	case "/pinot-controller/admin":
+		writeText(w, "GOOD")
+		return
+	}
+
+	if c.options.Authorization != "" && r.Header.Get("Authorization") != c.options.Authorization {
+		writeError(w, http.StatusUnauthorized, "HTTP 401 Unauthorized")
+		return
+	}
+
+	c.mu.Lock()
+	handler, handled := c.handlers[r.Method+" "+r.URL.Path]
+	c.mu.Unlock()
+
+	if handled {
+		handler(w, r)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := pathSegments(r.URL.Path)
+	if len(path) == 0 {
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+		return
+	}
+
+	switch path[0] {
+	case "schemas":
+		c.serveSchemas(w, r, path[1:])
+	case "tables":
+		c.serveTables(w, r, path[1:])
+	case "users":
+		c.serveUsers(w, r, path[1:])
+	case "tenants":
+		c.serveTenants(w, r, path[1:])
+	case "instances":
+		c.serveInstances(w, r, path[1:])
+	case "segments":
+		c.serveSegments(w, r, path[1:])
+	case "rebalanceStatus":
+		c.serveRebalanceStatus(w, r, path[1:])
+	case "cluster":
+		c.serveCluster(w, r, path[1:])
+	default:
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+	}
+}
+
+// Handle serves requests with a method and path, e.g. GET /tables/airlineStats/size, with handler instead
+// of the fake. The request is authorized first, and the handler replaces the fake for the path even if
+// it models it
+func (c *Controller) Handle(method string, path string, handler http.HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers[method+" "+path] = handler
+}
+
+// route is a handler for a method and number of path segments after the resource
+type route struct {
+	method   string
+	segments int
+	// action is the last path segment, e.g. reload in /segments/{tableName}/reload
+	action  string
+	handler func(w http.ResponseWriter, r *http.Request, path []string)
+}
+
+// serve calls the route matching the request, 404 when no route has its path, 405 when none has its method
+func serve(w http.ResponseWriter, r *http.Request, path []string, routes []route) {
+
+	pathFound := false
+	for _, rt := range routes {
+		if len(path) != rt.segments || (rt.action != "" && path[len(path)-1] != rt.action) {
+			continue
+		}
+		pathFound = true
+		if r.Method == rt.method {
+			rt.handler(w, r, path)
+			return
+		}
+	}
+
+	if pathFound {
+		writeError(w, http.StatusMethodNotAllowed, "HTTP 405 Method Not Allowed")
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+}
+
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// writeError writes an error in the format of the controller, {"code": 404, "error": "..."}
+func writeError(w http.ResponseWriter, statusCode int, format string, args ...any) {
+	writeJSON(w, statusCode, map[string]any{
+		"code":  statusCode,
+		"error": fmt.Sprintf(format, args...),
+	})
+}
+
+func writeStatus(w http.ResponseWriter, format string, args ...any) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": fmt.Sprintf(format, args...)})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeText(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, text)
+}
+
+// decodeBody decodes a JSON request body, writing a 400 when it is invalid
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: %s", err)
+		return false
+	}
+	return true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitTableNameWithType splits airlineStats_OFFLINE into airlineStats and OFFLINE, the type is empty for
+// a raw table name
+func splitTableNameWithType(tableNameWithType string) (string, string) {
+	for _, tableType := range []string{tableTypeOffline, tableTypeRealtime} {
+		if tableName, ok := strings.CutSuffix(tableNameWithType, "_"+tableType); ok {
+			return tableName, tableType
+		}
+	}
+	return tableNameWithType, ""
+}
+
+// newJobId returns a random id in the format of controller job ids, e.g. f9db13c7-3ad6-45a8-a08f-75cd03c42fb5
+func newJobId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
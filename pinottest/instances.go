@@ -0,0 +1,257 @@
+package pinottest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// instance is an instance stored by name, e.g. Server_172.17.0.3_7050
+type instance struct {
+	config             model.Instance
+	enabled            bool
+	systemResourceInfo model.SystemResourceInfo
+}
+
+func (i *instance) tags() map[string]bool {
+	tags := make(map[string]bool)
+	for _, tag := range i.config.Tags {
+		tags[tag] = true
+	}
+	return tags
+}
+
+func (i *instance) response(instanceName string) model.GetInstanceResponse {
+
+	pools := make(map[string]string)
+	for tag, pool := range i.config.Pools {
+		pools[tag] = strconv.Itoa(pool)
+	}
+
+	tags := i.config.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return model.GetInstanceResponse{
+		InstanceName:       instanceName,
+		Hostname:           i.config.Host,
+		Enabled:            i.enabled,
+		Port:               strconv.Itoa(i.config.Port),
+		Tags:               tags,
+		Pools:              pools,
+		GRPCPort:           i.config.GrpcPort,
+		AdminPort:          i.config.AdminPort,
+		QueryServicePort:   i.config.QueryServicePort,
+		QueryMailboxPort:   i.config.QueryMailboxPort,
+		SystemResourceInfo: i.systemResourceInfo,
+	}
+}
+
+// instanceName is the name the controller gives an instance, {Type}_{host}_{port}
+func instanceName(config model.Instance) string {
+	instanceType := strings.ToUpper(config.Type)
+	return fmt.Sprintf("%s%s_%s_%d", instanceType[:1], strings.ToLower(instanceType[1:]), config.Host, config.Port)
+}
+
+func (c *Controller) serveInstances(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 0, handler: c.getInstances},
+		{method: http.MethodPost, segments: 0, handler: c.createInstance},
+		{method: http.MethodGet, segments: 1, handler: c.getInstance},
+		{method: http.MethodPut, segments: 1, handler: c.updateInstance},
+		{method: http.MethodDelete, segments: 1, handler: c.deleteInstance},
+		{method: http.MethodPut, segments: 2, action: "state", handler: c.setInstanceState},
+		{method: http.MethodPut, segments: 2, action: "updateTags", handler: c.updateInstanceTags},
+		{method: http.MethodPost, segments: 2, action: "updateBrokerResource", handler: c.updateBrokerResource},
+	})
+}
+
+// SetSystemResourceInfo sets the cores and memory an instance reports, as if it had joined the cluster with them
+func (c *Controller) SetSystemResourceInfo(instanceName string, info model.SystemResourceInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i, ok := c.instances[instanceName]
+	if !ok {
+		return fmt.Errorf("pinottest: instance %s does not exist", instanceName)
+	}
+
+	i.systemResourceInfo = info
+	return nil
+}
+
+// instancesWithTag returns the names of the instances with a tag, e.g. DefaultTenant_BROKER
+func (c *Controller) instancesWithTag(tag string) []string {
+
+	instances := []string{}
+	for _, instanceName := range sortedKeys(c.instances) {
+		if c.instances[instanceName].tags()[tag] {
+			instances = append(instances, instanceName)
+		}
+	}
+
+	return instances
+}
+
+// decodeInstance decodes an instance from the request body and validates it like the controller does
+func decodeInstance(w http.ResponseWriter, r *http.Request) (model.Instance, bool) {
+
+	var config model.Instance
+	if !decodeBody(w, r, &config) {
+		return config, false
+	}
+
+	if config.Host == "" || config.Port <= 0 {
+		writeError(w, http.StatusBadRequest, "Invalid instance: host and port are required")
+		return config, false
+	}
+
+	config.Type = strings.ToUpper(config.Type)
+	switch model.InstanceType(config.Type) {
+	case model.InstanceTypeController, model.InstanceTypeBroker, model.InstanceTypeServer, model.InstanceTypeMinion:
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid instance: unknown instance type %s", config.Type)
+		return config, false
+	}
+
+	return config, true
+}
+
+func (c *Controller) getInstances(w http.ResponseWriter, r *http.Request, path []string) {
+	writeJSON(w, http.StatusOK, model.GetInstancesResponse{Instances: sortedKeys(c.instances)})
+}
+
+func (c *Controller) getInstance(w http.ResponseWriter, r *http.Request, path []string) {
+
+	i, ok := c.instances[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance %s not found", path[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, i.response(path[0]))
+}
+
+func (c *Controller) createInstance(w http.ResponseWriter, r *http.Request, path []string) {
+
+	config, ok := decodeInstance(w, r)
+	if !ok {
+		return
+	}
+
+	name := instanceName(config)
+	if _, exists := c.instances[name]; exists {
+		writeError(w, http.StatusConflict, "Instance %s already exists", name)
+		return
+	}
+
+	c.instances[name] = &instance{config: config, enabled: true}
+	writeStatus(w, "Added instance: %s", name)
+}
+
+func (c *Controller) updateInstance(w http.ResponseWriter, r *http.Request, path []string) {
+
+	existing, ok := c.instances[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance %s not found", path[0])
+		return
+	}
+
+	config, ok := decodeInstance(w, r)
+	if !ok {
+		return
+	}
+
+	if name := instanceName(config); name != path[0] {
+		writeError(w, http.StatusBadRequest, "Instance %s does not match %s in the body", path[0], name)
+		return
+	}
+
+	c.instances[path[0]] = &instance{config: config, enabled: existing.enabled, systemResourceInfo: existing.systemResourceInfo}
+	writeStatus(w, "Updated instance: %s", path[0])
+}
+
+// deleteInstance drops an instance, refusing while the ideal state of a table assigns segments to it
+func (c *Controller) deleteInstance(w http.ResponseWriter, r *http.Request, path []string) {
+
+	if _, ok := c.instances[path[0]]; !ok {
+		writeError(w, http.StatusNotFound, "Instance %s not found", path[0])
+		return
+	}
+
+	for _, tableNameWithType := range sortedKeys(c.segments) {
+		if slices.Contains(c.segments[tableNameWithType].servers(), path[0]) {
+			writeError(w, http.StatusConflict, "Failed to drop instance %s - Instance %s exists in ideal state for %s", path[0], path[0], tableNameWithType)
+			return
+		}
+	}
+
+	delete(c.instances, path[0])
+	writeStatus(w, "Successfully dropped instance")
+}
+
+func (c *Controller) setInstanceState(w http.ResponseWriter, r *http.Request, path []string) {
+
+	i, ok := c.instances[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance %s not found", path[0])
+		return
+	}
+
+	state := strings.ToUpper(r.URL.Query().Get("state"))
+	switch state {
+	case model.InstanceStateEnable:
+		i.enabled = true
+	case model.InstanceStateDisable:
+		i.enabled = false
+	default:
+		writeError(w, http.StatusBadRequest, "Unknown state '%s' for instance request", r.URL.Query().Get("state"))
+		return
+	}
+
+	writeStatus(w, "Request to %s instance %s is successful", strings.ToLower(state), path[0])
+}
+
+func (c *Controller) updateInstanceTags(w http.ResponseWriter, r *http.Request, path []string) {
+
+	i, ok := c.instances[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Instance %s not found", path[0])
+		return
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(r.URL.Query().Get("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) == 0 {
+		writeError(w, http.StatusBadRequest, "Must provide tags to update")
+		return
+	}
+
+	i.config.Tags = tags
+	writeStatus(w, "Updated tags: %v for instance: %s", tags, path[0])
+}
+
+func (c *Controller) updateBrokerResource(w http.ResponseWriter, r *http.Request, path []string) {
+
+	if _, ok := c.instances[path[0]]; !ok {
+		writeError(w, http.StatusNotFound, "Instance %s not found", path[0])
+		return
+	}
+
+	if model.InstanceTypeOf(path[0]) != model.InstanceTypeBroker {
+		writeError(w, http.StatusBadRequest, "Cannot update broker resource for non-broker instance: %s", path[0])
+		return
+	}
+
+	writeStatus(w, "Updated broker resource for broker instance: %s", path[0])
+}
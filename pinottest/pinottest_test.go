@@ -0,0 +1,610 @@
+package pinottest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/azaurus1/go-pinot-api/pinottest"
+	"github.com/stretchr/testify/assert"
+)
+
+func createClient(controller *pinottest.Controller) *goPinotAPI.PinotAPIClient {
+	return goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controller.URL),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+	)
+}
+
+func assertStatusCode(t *testing.T, err error, statusCode int) {
+	t.Helper()
+
+	var reqErr *goPinotAPI.RequestError
+	if assert.True(t, errors.As(err, &reqErr), "expected a request error, got %v", err) {
+		assert.Equal(t, statusCode, reqErr.StatusCode)
+	}
+}
+
+func getSchema(schemaName string) model.Schema {
+	return model.Schema{
+		SchemaName: schemaName,
+		DimensionFieldSpecs: []model.FieldSpec{
+			{Name: "origin", DataType: "STRING"},
+		},
+		MetricFieldSpecs: []model.FieldSpec{
+			{Name: "delay", DataType: "INT"},
+		},
+	}
+}
+
+func getTable(tableName string, tableType string, tenant string) []byte {
+	table := model.Table{
+		TableName: tableName,
+		TableType: tableType,
+		SegmentsConfig: model.TableSegmentsConfig{
+			TimeType:    "MILLISECONDS",
+			Replication: "1",
+		},
+		Tenants: model.TableTenant{Broker: tenant, Server: tenant},
+	}
+
+	tableBytes, _ := json.Marshal(table)
+	return tableBytes
+}
+
+func TestSchemas(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	_, err := client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	schema, err := client.GetSchema("airlineStats")
+	assert.NoError(t, err)
+	assert.Equal(t, "airlineStats", schema.SchemaName)
+	assert.Len(t, schema.DimensionFieldSpecs, 1)
+
+	schemaBytes, err := json.Marshal(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateSchemaFromBytes(schemaBytes)
+	assertStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.CreateSchema(getSchema("airlineStats"))
+	assertStatusCode(t, err, http.StatusConflict)
+
+	var replaced model.UserActionResponse
+	err = client.CreateObject("/schemas?override=true", schemaBytes, &replaced)
+	assert.NoError(t, err, "Expected an existing schema to be replaced when override is requested")
+
+	_, err = client.GetSchema("missing")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	validation, err := client.ValidateSchema(model.Schema{})
+	assert.NoError(t, err)
+	assert.False(t, validation.Ok)
+
+	schemas, err := client.GetSchemas()
+	assert.NoError(t, err)
+	assert.Equal(t, model.GetSchemaResponse{"airlineStats"}, *schemas)
+
+	_, err = client.DeleteSchema("airlineStats")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteSchema("airlineStats")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestSchemaInUse(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	_, err := client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	// the client checks table names before deleting, the controller checks the schema of every table
+	var deleted model.UserActionResponse
+	err = client.DeleteObject("/schemas/airlineStats", nil, &deleted)
+	assertStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.DeleteTable("airlineStats")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteSchema("airlineStats")
+	assert.NoError(t, err)
+}
+
+func TestTables(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	_, err := client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats_REALTIME", "REALTIME", ""))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assertStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.CreateTable(getTable("airlineStats_OFFLINE", "REALTIME", ""))
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	tables, err := client.GetTables()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"airlineStats"}, tables.Tables)
+
+	table, err := client.GetTable("airlineStats")
+	assert.NoError(t, err)
+	assert.Equal(t, "airlineStats_OFFLINE", table.OFFLINE.TableName)
+	assert.Equal(t, "airlineStats_REALTIME", table.REALTIME.TableName)
+
+	table, err = client.GetTable("airlineStats_OFFLINE")
+	assert.NoError(t, err)
+	assert.Equal(t, "airlineStats_OFFLINE", table.OFFLINE.TableName)
+	assert.True(t, table.REALTIME.IsEmpty())
+
+	_, err = client.UpdateTable("airlineStats", getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	_, err = client.UpdateTable("flights", getTable("flights", "OFFLINE", ""))
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	schema, err := client.GetTableSchema("airlineStats_REALTIME")
+	assert.NoError(t, err)
+	assert.Equal(t, "airlineStats", schema.SchemaName)
+
+	_, err = client.DeleteTable("airlineStats_OFFLINE")
+	assert.NoError(t, err)
+
+	_, err = client.GetTable("airlineStats_OFFLINE")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	_, err = client.GetTable("airlineStats_REALTIME")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteTable("missing")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestUsers(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	user := model.User{
+		Username:  "liam",
+		Password:  "password",
+		Component: model.UserComponentBroker,
+		Role:      model.UserRoleUser,
+	}
+
+	_, err := client.CreateUser(user)
+	assert.NoError(t, err)
+
+	_, err = client.CreateUser(user)
+	assertStatusCode(t, err, http.StatusConflict)
+
+	fetched, err := client.GetUser("liam", "BROKER")
+	assert.NoError(t, err)
+	assert.Equal(t, "liam_BROKER", fetched.UsernameWithComponent)
+	assert.NotEqual(t, "password", fetched.Password)
+
+	_, err = client.RotateUserPassword("liam", "BROKER", "rotated")
+	assert.NoError(t, err)
+	assert.True(t, controller.CheckPassword("liam", "BROKER", "rotated"))
+
+	_, err = client.GetUser("liam", "SERVER")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	users, err := client.GetUsers()
+	assert.NoError(t, err)
+	assert.Contains(t, users.Users, "liam_BROKER")
+
+	_, err = client.DeleteUser("liam", "BROKER")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteUser("liam", "BROKER")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestTenantsAndInstances(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	for _, instance := range []model.Instance{
+		{Host: "pinot-broker-0", Port: 8099, Type: "BROKER", Tags: []string{"airline_BROKER"}},
+		{Host: "pinot-server-0", Port: 8098, Type: "SERVER", Tags: []string{"airline_OFFLINE"}},
+		{Host: "pinot-server-1", Port: 8098, Type: "SERVER", Tags: []string{"airline_REALTIME"}},
+	} {
+		instanceBytes, err := json.Marshal(instance)
+		assert.NoError(t, err)
+
+		_, err = client.CreateInstance(instanceBytes)
+		assert.NoError(t, err)
+
+		_, err = client.CreateInstance(instanceBytes)
+		assertStatusCode(t, err, http.StatusConflict)
+	}
+
+	instance, err := client.GetInstance("Server_pinot-server-0_8098")
+	assert.NoError(t, err)
+	assert.True(t, instance.Enabled)
+	assert.Equal(t, []string{"airline_OFFLINE"}, instance.Tags)
+
+	_, err = client.GetInstance("Server_pinot-server-2_8098")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	_, err = client.DisableInstance("Server_pinot-server-0_8098")
+	assert.NoError(t, err)
+
+	instance, err = client.GetInstance("Server_pinot-server-0_8098")
+	assert.NoError(t, err)
+	assert.False(t, instance.Enabled)
+
+	for _, role := range []string{model.TenantRoleBroker, model.TenantRoleServer} {
+		tenant := model.TenantRequest{TenantRole: role, TenantName: "airline", NumberOfInstances: 1}
+
+		_, err = client.CreateTenantFromRequest(tenant)
+		assert.NoError(t, err)
+
+		_, err = client.CreateTenantFromRequest(tenant)
+		assertStatusCode(t, err, http.StatusConflict)
+	}
+
+	tenants, err := client.GetTenants()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DefaultTenant", "airline"}, tenants.BrokerTenants)
+
+	tenantInstances, err := client.GetTenantInstances("airline")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Broker_pinot-broker-0_8099"}, tenantInstances.BrokerInstances)
+	assert.Equal(t, []string{"Server_pinot-server-0_8098", "Server_pinot-server-1_8098"}, tenantInstances.ServerInstances)
+
+	tenantInstances, err = client.GetTenantInstancesByRole("airline", model.TenantRoleServer, "REALTIME")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Server_pinot-server-1_8098"}, tenantInstances.ServerInstances)
+
+	_, err = client.GetTenantInstances("missing")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	_, err = client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", "flights"))
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", "airline"))
+	assert.NoError(t, err)
+
+	tableInstances, err := client.GetTableInstances("airlineStats")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Server_pinot-server-0_8098"}, tableInstances.Servers[0].Instances)
+
+	tenantTables, err := client.GetTenantTablesByRole("airline", model.TenantRoleServer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"airlineStats_OFFLINE"}, tenantTables.Tables)
+
+	_, err = client.DeleteTenant("airline", model.TenantRoleServer)
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.UpdateInstanceTags("Server_pinot-server-1_8098", []string{"DefaultTenant_REALTIME"}, false)
+	assert.NoError(t, err)
+
+	tenantInstances, err = client.GetTenantInstancesByRole("airline", model.TenantRoleServer, "REALTIME")
+	assert.NoError(t, err)
+	assert.Empty(t, tenantInstances.ServerInstances)
+
+	_, err = client.DeleteInstance("Server_pinot-server-1_8098")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteInstance("Server_pinot-server-1_8098")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestSegments(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	_, err := client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	assert.NoError(t, controller.AddSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_0"))
+	assert.NoError(t, controller.AddSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_1"))
+	assert.Error(t, controller.AddSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_1"))
+	assert.Error(t, controller.AddSegment("airlineStats_REALTIME", "airlineStats_REALTIME_0"))
+
+	segments, err := client.GetSegments("airlineStats")
+	assert.NoError(t, err)
+	assert.Equal(t, model.GetSegmentsResponse{{Offline: []string{"airlineStats_OFFLINE_0", "airlineStats_OFFLINE_1"}}}, segments)
+
+	_, err = client.ReloadSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_1")
+	assert.NoError(t, err)
+
+	_, err = client.ReloadSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_2")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	_, err = client.GetSegments("flights")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	_, err = client.DeleteTable("airlineStats")
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	segments, err = client.GetSegments("airlineStats")
+	assert.NoError(t, err)
+	assert.Empty(t, segments[0].Offline)
+}
+
+func TestClusterConfigs(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{ClusterName: "airline"})
+	defer controller.Close()
+	client := createClient(controller)
+
+	info, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, "airline", info.ClusterName)
+
+	_, err = client.UpdateClusterConfigs([]byte(`{"enable.case.insensitive": "false"}`))
+	assert.NoError(t, err)
+
+	configs, err := client.GetClusterConfigs()
+	assert.NoError(t, err)
+	assert.Equal(t, "false", configs.EnableCaseInsensitive)
+	assert.Equal(t, "true", configs.AllowParticipantAutoJoin)
+
+	_, err = client.DeleteClusterConfig("enable.case.insensitive")
+	assert.NoError(t, err)
+
+	_, err = client.DeleteClusterConfig("enable.case.insensitive")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestAuthorization(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{Authorization: "Basic YWRtaW46YWRtaW4K"})
+	defer controller.Close()
+
+	_, err := createClient(controller).GetTables()
+	assert.NoError(t, err)
+
+	unauthenticated := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(controller.URL))
+
+	_, err = unauthenticated.GetTables()
+	assertStatusCode(t, err, http.StatusUnauthorized)
+
+	health, err := unauthenticated.CheckPinotControllerHealth()
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", health.Response)
+}
+
+// createServers creates servers tagged for the tables of a tenant
+func createServers(t *testing.T, client *goPinotAPI.PinotAPIClient, tag string, hosts ...string) {
+	t.Helper()
+
+	for _, host := range hosts {
+		instanceBytes, err := json.Marshal(model.Instance{Host: host, Port: 8098, Type: "SERVER", Tags: []string{tag}})
+		assert.NoError(t, err)
+
+		_, err = client.CreateInstance(instanceBytes)
+		assert.NoError(t, err)
+	}
+}
+
+func TestAssignment(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	createServers(t, client, "DefaultTenant_OFFLINE", "pinot-server-0", "pinot-server-1")
+
+	_, err := client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	assert.NoError(t, controller.AddSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_0"))
+	assert.NoError(t, controller.AddSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_1"))
+
+	idealState, err := client.GetTableIdealState("airlineStats")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"airlineStats_OFFLINE_0": {"Server_pinot-server-0_8098": "ONLINE"},
+		"airlineStats_OFFLINE_1": {"Server_pinot-server-1_8098": "ONLINE"},
+	}, idealState.Offline, "Expected segments to be spread across the servers")
+	assert.Nil(t, idealState.Realtime)
+
+	_, err = client.DisableInstance("Server_pinot-server-1_8098")
+	assert.NoError(t, err)
+
+	externalView, err := client.GetTableExternalView("airlineStats")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"airlineStats_OFFLINE_0": {"Server_pinot-server-0_8098": "ONLINE"},
+	}, externalView.Offline, "Expected replicas on disabled servers to be left out")
+
+	_, err = client.DeleteInstance("Server_pinot-server-1_8098")
+	assertStatusCode(t, err, http.StatusConflict)
+
+	_, err = client.GetTableIdealState("flights")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	assert.NoError(t, controller.SetSystemResourceInfo("Server_pinot-server-0_8098", model.SystemResourceInfo{NumCores: "8"}))
+	assert.Error(t, controller.SetSystemResourceInfo("Server_pinot-server-2_8098", model.SystemResourceInfo{}))
+
+	instance, err := client.GetInstance("Server_pinot-server-0_8098")
+	assert.NoError(t, err)
+	assert.Equal(t, "8", instance.SystemResourceInfo.NumCores)
+}
+
+func TestRebalance(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	createServers(t, client, "DefaultTenant_OFFLINE", "pinot-server-0")
+
+	_, err := client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	assert.NoError(t, controller.AddSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_0"))
+
+	result, err := client.RebalanceTable("airlineStats", "OFFLINE", model.RebalanceTableOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.RebalanceStatusNoOp, result.Status)
+
+	_, err = client.RebalanceTable("airlineStats", "REALTIME", model.RebalanceTableOptions{})
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	createServers(t, client, "DefaultTenant_OFFLINE", "pinot-server-1")
+
+	table := model.Table{}
+	assert.NoError(t, json.Unmarshal(getTable("airlineStats", "OFFLINE", ""), &table))
+	table.SegmentsConfig.Replication = "2"
+	tableBytes, err := json.Marshal(table)
+	assert.NoError(t, err)
+
+	_, err = client.UpdateTable("airlineStats", tableBytes)
+	assert.NoError(t, err)
+
+	result, err = client.RebalanceTable("airlineStats", "OFFLINE", model.RebalanceTableOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, model.RebalanceStatusDone, result.Status)
+	assert.Equal(t, map[string]map[string]string{
+		"airlineStats_OFFLINE_0": {"Server_pinot-server-0_8098": "ONLINE", "Server_pinot-server-1_8098": "ONLINE"},
+	}, result.SegmentAssignment)
+
+	idealState, err := client.GetTableIdealState("airlineStats")
+	assert.NoError(t, err)
+	assert.Len(t, idealState.Offline["airlineStats_OFFLINE_0"], 1, "Expected a dry run to leave the ideal state alone")
+
+	minAvailableReplicas := 2
+	result, err = client.RebalanceTable("airlineStats", "OFFLINE", model.RebalanceTableOptions{MinAvailableReplicas: &minAvailableReplicas})
+	assert.NoError(t, err)
+	assert.Equal(t, model.RebalanceStatusFailed, result.Status)
+
+	result, err = client.RebalanceTable("airlineStats", "OFFLINE", model.RebalanceTableOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.RebalanceStatusInProgress, result.Status)
+
+	status, err := client.GetTableRebalanceStatus(result.JobId)
+	assert.NoError(t, err)
+	assert.Equal(t, model.RebalanceStatusDone, status.TableRebalanceProgressStats.Status)
+	assert.Equal(t, 1, status.TableRebalanceProgressStats.InitialToTargetStateConvergence.ReplicasToRebalance)
+
+	idealState, err = client.GetTableIdealState("airlineStats")
+	assert.NoError(t, err)
+	assert.Len(t, idealState.Offline["airlineStats_OFFLINE_0"], 2)
+
+	_, err = client.GetTableRebalanceStatus("missing")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	cancelled, err := client.CancelTableRebalance("airlineStats", "OFFLINE")
+	assert.NoError(t, err)
+	assert.Empty(t, *cancelled)
+}
+
+func TestReloadJobs(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	createServers(t, client, "DefaultTenant_OFFLINE", "pinot-server-0")
+
+	_, err := client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	_, err = client.ReloadTableSegments("airlineStats")
+	assertStatusCode(t, err, http.StatusNotFound)
+
+	assert.NoError(t, controller.AddSegment("airlineStats_OFFLINE", "airlineStats_OFFLINE_0"))
+
+	res, err := client.ReloadTableSegments("airlineStats")
+	assert.NoError(t, err)
+
+	jobs, err := model.ParseReloadJobs(res.Status)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", jobs["airlineStats_OFFLINE"].NumMessagesSent.String())
+
+	status, err := client.GetReloadJobStatus(jobs["airlineStats_OFFLINE"].ReloadJobId)
+	assert.NoError(t, err)
+	assert.True(t, status.IsComplete())
+	assert.Equal(t, "airlineStats_OFFLINE", status.Metadata.TableName)
+
+	_, err = client.GetReloadJobStatus("missing")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestTableState(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+	client := createClient(controller)
+
+	_, err := client.CreateSchema(getSchema("airlineStats"))
+	assert.NoError(t, err)
+
+	_, err = client.CreateTable(getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	_, err = client.ChangeTableState("airlineStats", "OFFLINE", "disable")
+	assert.NoError(t, err)
+
+	_, err = client.UpdateTable("airlineStats", getTable("airlineStats", "OFFLINE", ""))
+	assert.NoError(t, err)
+
+	state, err := client.GetTableState("airlineStats", "OFFLINE")
+	assert.NoError(t, err)
+	assert.Equal(t, "disabled", state.State, "Expected updating a table to keep its state")
+
+	_, err = client.ChangeTableState("airlineStats", "OFFLINE", "drop")
+	assertStatusCode(t, err, http.StatusBadRequest)
+
+	_, err = client.GetTableState("airlineStats", "REALTIME")
+	assertStatusCode(t, err, http.StatusNotFound)
+}
+
+func TestHandle(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{Authorization: "Basic YWRtaW46YWRtaW4K"})
+	defer controller.Close()
+
+	controller.Handle(http.MethodGet, "/tables", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := createClient(controller).GetTables()
+	assertStatusCode(t, err, http.StatusServiceUnavailable)
+
+	_, err = goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(controller.URL)).GetTables()
+	assertStatusCode(t, err, http.StatusUnauthorized)
+
+	_, err = createClient(controller).GetSchemas()
+	assert.NoError(t, err, "Expected other paths to be served by the fake")
+}
@@ -0,0 +1,126 @@
+package pinottest
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (c *Controller) serveSchemas(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 0, handler: c.getSchemas},
+		{method: http.MethodPost, segments: 0, handler: c.createSchema},
+		{method: http.MethodPost, segments: 1, action: "validate", handler: c.validateSchema},
+		{method: http.MethodGet, segments: 1, handler: c.getSchema},
+		{method: http.MethodPut, segments: 1, handler: c.updateSchema},
+		{method: http.MethodDelete, segments: 1, handler: c.deleteSchema},
+	})
+}
+
+// decodeSchema decodes a schema from the request body, keeping fields the model doesn't know
+func decodeSchema(w http.ResponseWriter, r *http.Request) (string, json.RawMessage, bool) {
+
+	var raw json.RawMessage
+	if !decodeBody(w, r, &raw) {
+		return "", nil, false
+	}
+
+	var schema struct {
+		SchemaName string `json:"schemaName"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid schema: %s", err)
+		return "", nil, false
+	}
+
+	if schema.SchemaName == "" {
+		writeError(w, http.StatusBadRequest, "Invalid schema. Reason: schemaName is required")
+		return "", nil, false
+	}
+
+	return schema.SchemaName, raw, true
+}
+
+func (c *Controller) getSchemas(w http.ResponseWriter, r *http.Request, path []string) {
+	writeJSON(w, http.StatusOK, sortedKeys(c.schemas))
+}
+
+func (c *Controller) getSchema(w http.ResponseWriter, r *http.Request, path []string) {
+
+	schema, ok := c.schemas[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Schema %s not found", path[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schema)
+}
+
+// createSchema adds a schema, an existing one is only replaced when override=true is requested
+func (c *Controller) createSchema(w http.ResponseWriter, r *http.Request, path []string) {
+
+	schemaName, schema, ok := decodeSchema(w, r)
+	if !ok {
+		return
+	}
+
+	if _, exists := c.schemas[schemaName]; exists && r.URL.Query().Get("override") != "true" {
+		writeError(w, http.StatusConflict, "Schema %s already exists", schemaName)
+		return
+	}
+
+	c.schemas[schemaName] = schema
+	writeJSON(w, http.StatusOK, map[string]any{
+		"unrecognizedProperties": map[string]any{},
+		"status":                 schemaName + " successfully added",
+	})
+}
+
+func (c *Controller) validateSchema(w http.ResponseWriter, r *http.Request, path []string) {
+
+	_, schema, ok := decodeSchema(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schema)
+}
+
+func (c *Controller) updateSchema(w http.ResponseWriter, r *http.Request, path []string) {
+
+	if _, ok := c.schemas[path[0]]; !ok {
+		writeError(w, http.StatusNotFound, "Schema %s not found", path[0])
+		return
+	}
+
+	schemaName, schema, ok := decodeSchema(w, r)
+	if !ok {
+		return
+	}
+
+	if schemaName != path[0] {
+		writeError(w, http.StatusBadRequest, "Schema name mismatch for uploaded schema, tried to add schema with name %s as %s", schemaName, path[0])
+		return
+	}
+
+	c.schemas[schemaName] = schema
+	writeStatus(w, "%s successfully added", schemaName)
+}
+
+func (c *Controller) deleteSchema(w http.ResponseWriter, r *http.Request, path []string) {
+
+	schemaName := path[0]
+	if _, ok := c.schemas[schemaName]; !ok {
+		writeError(w, http.StatusNotFound, "Schema %s not found", schemaName)
+		return
+	}
+
+	for _, tableNameWithType := range sortedKeys(c.tables) {
+		if c.tables[tableNameWithType].schemaName() == schemaName {
+			writeError(w, http.StatusConflict, "Cannot delete schema %s, as it is associated with table %s", schemaName, tableNameWithType)
+			return
+		}
+	}
+
+	delete(c.schemas, schemaName)
+	writeStatus(w, "Schema %s deleted", schemaName)
+}
@@ -0,0 +1,201 @@
+package pinottest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+func (c *Controller) serveSegments(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 1, handler: c.getSegments},
+		{method: http.MethodDelete, segments: 1, handler: c.deleteSegments},
+		{method: http.MethodPost, segments: 2, action: "reload", handler: c.reloadTableSegments},
+		{method: http.MethodGet, segments: 2, handler: c.getReloadJobStatus},
+		{method: http.MethodDelete, segments: 2, handler: c.deleteSegment},
+		{method: http.MethodPost, segments: 3, action: "reload", handler: c.reloadSegment},
+	})
+}
+
+// AddSegment adds a segment to a table, as if an ingestion job had uploaded it. The segment is assigned to
+// the servers tagged for the table with the fewest of its segments, up to the replication of the table
+func (c *Controller) AddSegment(tableNameWithType string, segmentName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.tables[tableNameWithType]
+	if !ok {
+		return fmt.Errorf("pinottest: table %s does not exist", tableNameWithType)
+	}
+
+	if _, exists := c.segments[tableNameWithType][segmentName]; exists {
+		return fmt.Errorf("pinottest: segment %s of table %s already exists", segmentName, tableNameWithType)
+	}
+
+	if c.segments[tableNameWithType] == nil {
+		c.segments[tableNameWithType] = make(assignment)
+	}
+	c.segments[tableNameWithType][segmentName] = c.segments[tableNameWithType].assign(nil, c.tableServers(t), t.replication())
+	return nil
+}
+
+// findSegment returns the table with type holding a segment of a table
+func (c *Controller) findSegment(tableName string, segmentName string) (string, bool) {
+	for _, tableNameWithType := range c.findTables(tableName, "") {
+		if _, ok := c.segments[tableNameWithType][segmentName]; ok {
+			return tableNameWithType, true
+		}
+	}
+	return "", false
+}
+
+// getSegments returns the segments of a table by type, both types of a raw table name unless type is set
+func (c *Controller) getSegments(w http.ResponseWriter, r *http.Request, path []string) {
+
+	found := c.findTables(path[0], r.URL.Query().Get("type"))
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	result := model.GetSegmentsResponse{}
+	for _, tableNameWithType := range found {
+		segments := sortedKeys(c.segments[tableNameWithType])
+
+		if _, tableType := splitTableNameWithType(tableNameWithType); tableType == tableTypeOffline {
+			result = append(result, model.SegmentDetail{Offline: segments})
+		} else {
+			result = append(result, model.SegmentDetail{Realtime: segments})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (c *Controller) deleteSegments(w http.ResponseWriter, r *http.Request, path []string) {
+
+	found := c.findTables(path[0], r.URL.Query().Get("type"))
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	for _, tableNameWithType := range found {
+		delete(c.segments, tableNameWithType)
+	}
+
+	writeStatus(w, "All segments of table %s deleted", strings.Join(found, ", "))
+}
+
+func (c *Controller) deleteSegment(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tableNameWithType, ok := c.findSegment(path[0], path[1])
+	if !ok {
+		writeError(w, http.StatusNotFound, "Segment %s of table %s not found", path[1], path[0])
+		return
+	}
+
+	delete(c.segments[tableNameWithType], path[1])
+	writeStatus(w, "Segment %s deleted", path[1])
+}
+
+// reloadTableSegments submits a reload job for each type of the table, the status holds the jobs by table
+// name with type encoded as JSON like the controller does
+func (c *Controller) reloadTableSegments(w http.ResponseWriter, r *http.Request, path []string) {
+
+	found := c.findTables(path[0], r.URL.Query().Get("type"))
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	jobs := make(map[string]model.ReloadJob)
+	for _, tableNameWithType := range found {
+		segments := c.segments[tableNameWithType]
+		if len(segments) == 0 {
+			continue
+		}
+
+		servers := segments.servers()
+		jobId := c.addReloadJob(tableNameWithType, "", len(segments), len(servers))
+		jobs[tableNameWithType] = model.ReloadJob{
+			ReloadJobId:                  jobId,
+			ReloadJobMetaZKStorageStatus: "SUCCESS",
+			NumMessagesSent:              json.Number(strconv.Itoa(len(servers))),
+		}
+	}
+
+	if len(jobs) == 0 {
+		writeError(w, http.StatusNotFound, "Failed to find any segments in table: %s", path[0])
+		return
+	}
+
+	status, err := json.Marshal(jobs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+
+	writeStatus(w, "%s", status)
+}
+
+func (c *Controller) reloadSegment(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tableNameWithType, ok := c.findSegment(path[0], path[1])
+	if !ok {
+		writeError(w, http.StatusNotFound, "Segment %s of table %s not found", path[1], path[0])
+		return
+	}
+
+	servers := c.segments[tableNameWithType][path[1]]
+	jobId := c.addReloadJob(tableNameWithType, path[1], 1, len(servers))
+
+	writeStatus(w, "Submitted reload job id: %s, sent %d reload messages. Job meta ZK storage status: SUCCESS", jobId, len(servers))
+}
+
+// addReloadJob records a reload job that has reloaded every segment, returning its id
+func (c *Controller) addReloadJob(tableNameWithType string, segmentName string, segments int, servers int) string {
+
+	jobType := "RELOAD_ALL_SEGMENTS"
+	if segmentName != "" {
+		jobType = "RELOAD_SEGMENT"
+	}
+
+	jobId := newJobId()
+	c.reloadJobs[jobId] = model.GetReloadJobStatusResponse{
+		TotalSegmentCount:   segments,
+		SuccessCount:        segments,
+		TotalServersQueried: servers,
+		Metadata: model.ReloadJobMetadata{
+			JobId:            jobId,
+			JobType:          jobType,
+			TableName:        tableNameWithType,
+			SegmentName:      segmentName,
+			SubmissionTimeMs: strconv.FormatInt(time.Now().UnixMilli(), 10),
+			MessageCount:     strconv.Itoa(servers),
+		},
+	}
+
+	return jobId
+}
+
+func (c *Controller) getReloadJobStatus(w http.ResponseWriter, r *http.Request, path []string) {
+
+	if path[0] != "segmentReloadStatus" {
+		writeError(w, http.StatusNotFound, "HTTP 404 Not Found")
+		return
+	}
+
+	job, ok := c.reloadJobs[path[1]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Failed to find controller job id: %s", path[1])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,371 @@
+package pinottest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// table is a table config stored by name with type
+type table struct {
+	config model.Table
+	// raw is the config as it was sent, with its name set to the name with type
+	raw      json.RawMessage
+	disabled bool
+}
+
+// schemaName is the schema of the table, set in its segments config or named after the table
+func (t *table) schemaName() string {
+	if t.config.SegmentsConfig.SchemaName != "" {
+		return t.config.SegmentsConfig.SchemaName
+	}
+	tableName, _ := splitTableNameWithType(t.config.TableName)
+	return tableName
+}
+
+func (t *table) brokerTenant() string {
+	return tenantOrDefault(t.config.Tenants.Broker)
+}
+
+func (t *table) serverTenant() string {
+	return tenantOrDefault(t.config.Tenants.Server)
+}
+
+func tenantOrDefault(tenant string) string {
+	if tenant == "" {
+		return DefaultTenant
+	}
+	return tenant
+}
+
+func (c *Controller) serveTables(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 0, handler: c.getTables},
+		{method: http.MethodPost, segments: 0, handler: c.createTable},
+		{method: http.MethodGet, segments: 1, handler: c.getTable},
+		{method: http.MethodPut, segments: 1, handler: c.updateTable},
+		{method: http.MethodDelete, segments: 1, handler: c.deleteTable},
+		{method: http.MethodGet, segments: 2, action: "schema", handler: c.getTableSchema},
+		{method: http.MethodGet, segments: 2, action: "instances", handler: c.getTableInstances},
+		{method: http.MethodGet, segments: 2, action: "idealstate", handler: c.getIdealState},
+		{method: http.MethodGet, segments: 2, action: "externalview", handler: c.getExternalView},
+		{method: http.MethodGet, segments: 2, action: "state", handler: c.getTableState},
+		{method: http.MethodPut, segments: 2, action: "state", handler: c.setTableState},
+		{method: http.MethodPost, segments: 2, action: "rebalance", handler: c.rebalanceTable},
+		{method: http.MethodDelete, segments: 2, action: "rebalance", handler: c.cancelTableRebalance},
+	})
+}
+
+// findTables returns the names with type of the existing tables a table name refers to, a raw table name
+// refers to both types unless tableType is set
+func (c *Controller) findTables(tableName string, tableType string) []string {
+
+	rawTableName, suffix := splitTableNameWithType(tableName)
+	types := []string{tableTypeOffline, tableTypeRealtime}
+	if suffix != "" {
+		types = []string{suffix}
+	} else if tableType != "" {
+		types = []string{strings.ToUpper(tableType)}
+	}
+
+	var found []string
+	for _, t := range types {
+		if _, ok := c.tables[rawTableName+"_"+t]; ok {
+			found = append(found, rawTableName+"_"+t)
+		}
+	}
+
+	return found
+}
+
+// decodeTable decodes a table config from the request body and validates it like the controller does
+func (c *Controller) decodeTable(w http.ResponseWriter, r *http.Request) (*table, bool) {
+
+	var raw json.RawMessage
+	if !decodeBody(w, r, &raw) {
+		return nil, false
+	}
+
+	var config model.Table
+	if err := json.Unmarshal(raw, &config); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid table config: %s", err)
+		return nil, false
+	}
+
+	if config.TableName == "" {
+		writeError(w, http.StatusBadRequest, "Invalid table config: tableName is required")
+		return nil, false
+	}
+
+	tableType := strings.ToUpper(config.TableType)
+	if tableType != tableTypeOffline && tableType != tableTypeRealtime {
+		writeError(w, http.StatusBadRequest, "Invalid table config for table %s: tableType must be OFFLINE or REALTIME", config.TableName)
+		return nil, false
+	}
+
+	rawTableName, suffix := splitTableNameWithType(config.TableName)
+	if suffix != "" && suffix != tableType {
+		writeError(w, http.StatusBadRequest, "Invalid table config: table name %s does not match table type %s", config.TableName, tableType)
+		return nil, false
+	}
+
+	config.TableName = rawTableName + "_" + tableType
+	config.TableType = tableType
+
+	raw, err := setField(raw, "tableName", config.TableName)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid table config: %s", err)
+		return nil, false
+	}
+
+	t := &table{config: config, raw: raw}
+
+	if _, ok := c.schemas[t.schemaName()]; !ok {
+		writeError(w, http.StatusBadRequest, "Invalid table config for table %s: Failed to find schema for table: %s", config.TableName, t.schemaName())
+		return nil, false
+	}
+
+	if !c.brokerTenants[t.brokerTenant()] {
+		writeError(w, http.StatusBadRequest, "Failed to find instances with tag: %s_BROKER for table: %s", t.brokerTenant(), config.TableName)
+		return nil, false
+	}
+
+	if !c.serverTenants[t.serverTenant()] {
+		writeError(w, http.StatusBadRequest, "Failed to find instances with tag: %s_%s for table: %s", t.serverTenant(), tableType, config.TableName)
+		return nil, false
+	}
+
+	return t, true
+}
+
+// setField sets a top level field of a JSON object
+func setField(raw json.RawMessage, name string, value any) (json.RawMessage, error) {
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields[name] = encoded
+
+	return json.Marshal(fields)
+}
+
+func (c *Controller) getTables(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tableType := strings.ToUpper(r.URL.Query().Get("type"))
+
+	tableNames := []string{}
+	seen := make(map[string]bool)
+	for _, tableNameWithType := range sortedKeys(c.tables) {
+		tableName, t := splitTableNameWithType(tableNameWithType)
+		if (tableType != "" && t != tableType) || seen[tableName] {
+			continue
+		}
+		seen[tableName] = true
+		tableNames = append(tableNames, tableName)
+	}
+
+	writeJSON(w, http.StatusOK, model.GetTablesResponse{Tables: tableNames})
+}
+
+func (c *Controller) getTable(w http.ResponseWriter, r *http.Request, path []string) {
+
+	found := c.findTables(path[0], r.URL.Query().Get("type"))
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	configs := make(map[string]json.RawMessage)
+	for _, tableNameWithType := range found {
+		t := c.tables[tableNameWithType]
+		configs[t.config.TableType] = t.raw
+	}
+
+	writeJSON(w, http.StatusOK, configs)
+}
+
+func (c *Controller) createTable(w http.ResponseWriter, r *http.Request, path []string) {
+
+	t, ok := c.decodeTable(w, r)
+	if !ok {
+		return
+	}
+
+	if _, exists := c.tables[t.config.TableName]; exists {
+		writeError(w, http.StatusConflict, "Table config for %s already exists. If this is unexpected, try deleting the table to remove all metadata associated with it.", t.config.TableName)
+		return
+	}
+
+	c.tables[t.config.TableName] = t
+	writeJSON(w, http.StatusOK, model.CreateTablesResponse{
+		UnrecognizedProperties: map[string]any{},
+		Status:                 fmt.Sprintf("Table %s successfully added", t.config.TableName),
+	})
+}
+
+func (c *Controller) updateTable(w http.ResponseWriter, r *http.Request, path []string) {
+
+	t, ok := c.decodeTable(w, r)
+	if !ok {
+		return
+	}
+
+	pathTableName, _ := splitTableNameWithType(path[0])
+	tableName, _ := splitTableNameWithType(t.config.TableName)
+	if pathTableName != tableName {
+		writeError(w, http.StatusBadRequest, "Request table %s does not match table name in the body %s", path[0], t.config.TableName)
+		return
+	}
+
+	if _, exists := c.tables[t.config.TableName]; !exists {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", t.config.TableName)
+		return
+	}
+
+	t.disabled = c.tables[t.config.TableName].disabled
+	c.tables[t.config.TableName] = t
+	writeStatus(w, "Table config updated for %s", t.config.TableName)
+}
+
+// deleteTable deletes a table and its segments, both types of a raw table name unless type is set
+func (c *Controller) deleteTable(w http.ResponseWriter, r *http.Request, path []string) {
+
+	found := c.findTables(path[0], r.URL.Query().Get("type"))
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	for _, tableNameWithType := range found {
+		delete(c.tables, tableNameWithType)
+		delete(c.segments, tableNameWithType)
+	}
+
+	writeStatus(w, "Tables: [%s] deleted", strings.Join(found, ", "))
+}
+
+func (c *Controller) getTableSchema(w http.ResponseWriter, r *http.Request, path []string) {
+
+	found := c.findTables(path[0], "")
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	schema, ok := c.schemas[c.tables[found[0]].schemaName()]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Schema not found for table: %s", path[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schema)
+}
+
+// typedTable returns the table with type given by the table name and the type query parameter, writing a
+// 400 or 404 when there is none
+func (c *Controller) typedTable(w http.ResponseWriter, r *http.Request, tableName string) (*table, bool) {
+
+	tableType := strings.ToUpper(r.URL.Query().Get("type"))
+	if _, suffix := splitTableNameWithType(tableName); suffix == "" && tableType != tableTypeOffline && tableType != tableTypeRealtime {
+		writeError(w, http.StatusBadRequest, "Table type must be %s or %s", tableTypeOffline, tableTypeRealtime)
+		return nil, false
+	}
+
+	found := c.findTables(tableName, tableType)
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", tableName)
+		return nil, false
+	}
+
+	return c.tables[found[0]], true
+}
+
+func (c *Controller) getTableState(w http.ResponseWriter, r *http.Request, path []string) {
+
+	t, ok := c.typedTable(w, r, path[0])
+	if !ok {
+		return
+	}
+
+	state := "enabled"
+	if t.disabled {
+		state = "disabled"
+	}
+
+	writeJSON(w, http.StatusOK, model.GetTableStateResponse{State: state})
+}
+
+// setTableState enables or disables a table, the fake keeps serving the segments of a disabled table
+func (c *Controller) setTableState(w http.ResponseWriter, r *http.Request, path []string) {
+
+	t, ok := c.typedTable(w, r, path[0])
+	if !ok {
+		return
+	}
+
+	state := strings.ToLower(r.URL.Query().Get("state"))
+	switch state {
+	case "enable":
+		t.disabled = false
+	case "disable":
+		t.disabled = true
+	default:
+		writeError(w, http.StatusBadRequest, "Unknown state '%s' for table request", r.URL.Query().Get("state"))
+		return
+	}
+
+	writeStatus(w, "Request to %s table '%s' is successful", state, t.config.TableName)
+}
+
+// getTableInstances returns the instances tagged for the tenants of a table
+func (c *Controller) getTableInstances(w http.ResponseWriter, r *http.Request, path []string) {
+
+	found := c.findTables(path[0], r.URL.Query().Get("type"))
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, "Table %s does not exist", path[0])
+		return
+	}
+
+	tableName, _ := splitTableNameWithType(path[0])
+	result := model.GetTableInstancesResponse{TableName: tableName}
+
+	for _, tableNameWithType := range found {
+		t := c.tables[tableNameWithType]
+		tableType := strings.ToLower(t.config.TableType)
+
+		result.Brokers = append(result.Brokers, model.BrokerInstances{
+			TableType: tableType,
+			Instances: c.instancesWithTag(t.brokerTenant() + "_BROKER"),
+		})
+		result.Servers = append(result.Servers, model.ServerInstances{
+			TableType: tableType,
+			Instances: c.instancesWithTag(t.serverTenant() + "_" + t.config.TableType),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// tenantTables returns the tables with type that use a tenant as their broker or server tenant
+func (c *Controller) tenantTables(tenantName string, role string) []string {
+
+	tables := []string{}
+	for _, tableNameWithType := range sortedKeys(c.tables) {
+		t := c.tables[tableNameWithType]
+		if (role != model.TenantRoleServer && t.brokerTenant() == tenantName) ||
+			(role != model.TenantRoleBroker && t.serverTenant() == tenantName) {
+			tables = append(tables, tableNameWithType)
+		}
+	}
+
+	return tables
+}
@@ -0,0 +1,191 @@
+package pinottest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+func (c *Controller) serveTenants(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 0, handler: c.getTenants},
+		{method: http.MethodPost, segments: 0, handler: c.createTenant},
+		{method: http.MethodPut, segments: 0, handler: c.updateTenant},
+		{method: http.MethodGet, segments: 1, handler: c.getTenant},
+		{method: http.MethodDelete, segments: 1, handler: c.deleteTenant},
+		{method: http.MethodGet, segments: 2, action: "tables", handler: c.getTenantTables},
+		{method: http.MethodGet, segments: 2, action: "metadata", handler: c.getTenantMetadata},
+	})
+}
+
+// tenants returns the tenants of a role, BROKER or SERVER
+func (c *Controller) tenants(role string) map[string]bool {
+	if role == model.TenantRoleBroker {
+		return c.brokerTenants
+	}
+	return c.serverTenants
+}
+
+func (c *Controller) tenantExists(tenantName string) bool {
+	return c.brokerTenants[tenantName] || c.serverTenants[tenantName]
+}
+
+// decodeTenant decodes a tenant from the request body and validates it like the client does
+func decodeTenant(w http.ResponseWriter, r *http.Request) (model.TenantRequest, bool) {
+
+	var tenant model.TenantRequest
+	if !decodeBody(w, r, &tenant) {
+		return tenant, false
+	}
+
+	tenant.TenantRole = strings.ToUpper(tenant.TenantRole)
+	if err := tenant.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid tenant: %s", err)
+		return tenant, false
+	}
+
+	return tenant, true
+}
+
+func (c *Controller) getTenants(w http.ResponseWriter, r *http.Request, path []string) {
+	writeJSON(w, http.StatusOK, model.GetTenantsResponse{
+		ServerTenants: sortedKeys(c.serverTenants),
+		BrokerTenants: sortedKeys(c.brokerTenants),
+	})
+}
+
+// createTenant adds a tenant, instances join it through their tags, e.g. {tenant}_BROKER or {tenant}_OFFLINE
+func (c *Controller) createTenant(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tenant, ok := decodeTenant(w, r)
+	if !ok {
+		return
+	}
+
+	tenants := c.tenants(tenant.TenantRole)
+	if tenants[tenant.TenantName] {
+		writeError(w, http.StatusConflict, "%s tenant %s already exists", tenant.TenantRole, tenant.TenantName)
+		return
+	}
+
+	tenants[tenant.TenantName] = true
+	writeStatus(w, "Successfully created tenant")
+}
+
+func (c *Controller) updateTenant(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tenant, ok := decodeTenant(w, r)
+	if !ok {
+		return
+	}
+
+	if !c.tenants(tenant.TenantRole)[tenant.TenantName] {
+		writeError(w, http.StatusNotFound, "%s tenant %s does not exist", tenant.TenantRole, tenant.TenantName)
+		return
+	}
+
+	writeStatus(w, "Updated tenant")
+}
+
+// getTenant returns the instances of a tenant, limited to brokers or servers by type and to servers of a
+// table type by tableType
+func (c *Controller) getTenant(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tenantName := path[0]
+	if !c.tenantExists(tenantName) {
+		writeError(w, http.StatusNotFound, "Tenant %s not found", tenantName)
+		return
+	}
+
+	role := strings.ToUpper(r.URL.Query().Get("type"))
+	tableType := strings.ToUpper(r.URL.Query().Get("tableType"))
+
+	result := model.GetTenantResponse{
+		TenantName:      tenantName,
+		BrokerInstances: []string{},
+		ServerInstances: []string{},
+	}
+
+	if role != model.TenantRoleServer {
+		result.BrokerInstances = c.instancesWithTag(tenantName + "_BROKER")
+	}
+
+	if role != model.TenantRoleBroker {
+		result.ServerInstances = c.serverInstances(tenantName, tableType)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// serverInstances returns the servers of a tenant tagged for a table type, or for either type
+func (c *Controller) serverInstances(tenantName string, tableType string) []string {
+
+	if tableType != "" {
+		return c.instancesWithTag(tenantName + "_" + tableType)
+	}
+
+	instances := []string{}
+	for _, instanceName := range sortedKeys(c.instances) {
+		tags := c.instances[instanceName].tags()
+		if tags[tenantName+"_"+tableTypeOffline] || tags[tenantName+"_"+tableTypeRealtime] {
+			instances = append(instances, instanceName)
+		}
+	}
+
+	return instances
+}
+
+func (c *Controller) getTenantTables(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tenantName := path[0]
+	if !c.tenantExists(tenantName) {
+		writeError(w, http.StatusNotFound, "Tenant %s not found", tenantName)
+		return
+	}
+
+	role := strings.ToUpper(r.URL.Query().Get("type"))
+	writeJSON(w, http.StatusOK, model.GetTablesResponse{Tables: c.tenantTables(tenantName, role)})
+}
+
+func (c *Controller) getTenantMetadata(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tenantName := path[0]
+	if !c.tenantExists(tenantName) {
+		writeError(w, http.StatusNotFound, "Tenant %s not found", tenantName)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, model.GetTenantMetadataResponse{
+		TenantName:              tenantName,
+		BrokerInstances:         c.instancesWithTag(tenantName + "_BROKER"),
+		ServerInstances:         c.serverInstances(tenantName, ""),
+		OfflineServerInstances:  c.instancesWithTag(tenantName + "_" + tableTypeOffline),
+		RealtimeServerInstances: c.instancesWithTag(tenantName + "_" + tableTypeRealtime),
+	})
+}
+
+// deleteTenant deletes the broker or server tenant given by type, refusing while tables use it
+func (c *Controller) deleteTenant(w http.ResponseWriter, r *http.Request, path []string) {
+
+	tenantName := path[0]
+	role := strings.ToUpper(r.URL.Query().Get("type"))
+	if role != model.TenantRoleBroker && role != model.TenantRoleServer {
+		writeError(w, http.StatusBadRequest, "Tenant type must be %s or %s", model.TenantRoleBroker, model.TenantRoleServer)
+		return
+	}
+
+	tenants := c.tenants(role)
+	if !tenants[tenantName] {
+		writeError(w, http.StatusNotFound, "%s tenant %s does not exist", role, tenantName)
+		return
+	}
+
+	if tables := c.tenantTables(tenantName, role); len(tables) > 0 {
+		writeError(w, http.StatusBadRequest, "%s tenant %s is not deletable, it is used by tables: %s", role, tenantName, strings.Join(tables, ", "))
+		return
+	}
+
+	delete(tenants, tenantName)
+	writeStatus(w, "Successfully deleted tenant %s", tenantName)
+}
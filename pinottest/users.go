@@ -0,0 +1,163 @@
+package pinottest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// user is a user stored by username and component, e.g. admin_BROKER
+type user struct {
+	config       model.User
+	passwordHash string
+}
+
+// response returns the user as the controller does, with its password hashed
+func (u *user) response() model.User {
+	response := u.config
+	response.Password = u.passwordHash
+	return response
+}
+
+func hashPassword(password string) string {
+	hash := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(hash[:])
+}
+
+func userKey(username string, component string) string {
+	return username + "_" + strings.ToUpper(component)
+}
+
+func (c *Controller) serveUsers(w http.ResponseWriter, r *http.Request, path []string) {
+	serve(w, r, path, []route{
+		{method: http.MethodGet, segments: 0, handler: c.getUsers},
+		{method: http.MethodPost, segments: 0, handler: c.createUser},
+		{method: http.MethodGet, segments: 1, handler: c.getUser},
+		{method: http.MethodPut, segments: 1, handler: c.updateUser},
+		{method: http.MethodDelete, segments: 1, handler: c.deleteUser},
+	})
+}
+
+// CheckPassword reports whether a user exists with the password, as passwords are only returned hashed
+func (c *Controller) CheckPassword(username string, component model.UserComponent, password string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u, ok := c.users[userKey(username, string(component))]
+	return ok && u.passwordHash == hashPassword(password)
+}
+
+// decodeUser decodes a user from the request body and validates it like the controller does
+func decodeUser(w http.ResponseWriter, r *http.Request) (model.User, bool) {
+
+	var config model.User
+	if !decodeBody(w, r, &config) {
+		return config, false
+	}
+
+	if config.Username == "" || config.Password == "" {
+		writeError(w, http.StatusBadRequest, "Invalid user: username and password are required")
+		return config, false
+	}
+
+	config.Component = model.UserComponent(strings.ToUpper(string(config.Component)))
+	switch config.Component {
+	case model.UserComponentController, model.UserComponentBroker, model.UserComponentServer, model.UserComponentMinion:
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid user %s: unknown component %s", config.Username, config.Component)
+		return config, false
+	}
+
+	config.Role = model.UserRole(strings.ToUpper(string(config.Role)))
+	if config.Role != model.UserRoleAdmin && config.Role != model.UserRoleUser {
+		writeError(w, http.StatusBadRequest, "Invalid user %s: unknown role %s", config.Username, config.Role)
+		return config, false
+	}
+
+	config.UsernameWithComponent = userKey(config.Username, string(config.Component))
+	return config, true
+}
+
+func (c *Controller) getUsers(w http.ResponseWriter, r *http.Request, path []string) {
+
+	users := make(map[string]model.User)
+	for key, u := range c.users {
+		users[key] = u.response()
+	}
+
+	writeJSON(w, http.StatusOK, model.GetUsersResponse{Users: users})
+}
+
+func (c *Controller) getUser(w http.ResponseWriter, r *http.Request, path []string) {
+
+	key := userKey(path[0], r.URL.Query().Get("component"))
+	u, ok := c.users[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "User %s does not exist", key)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]model.User{key: u.response()})
+}
+
+func (c *Controller) createUser(w http.ResponseWriter, r *http.Request, path []string) {
+
+	config, ok := decodeUser(w, r)
+	if !ok {
+		return
+	}
+
+	key := config.UsernameWithComponent
+	if _, exists := c.users[key]; exists {
+		writeError(w, http.StatusConflict, "User %s already exists", key)
+		return
+	}
+
+	c.users[key] = &user{config: config, passwordHash: hashPassword(config.Password)}
+	writeStatus(w, "User %s has been successfully added!", key)
+}
+
+// updateUser replaces a user, keeping its password unless passwordChanged=true as the body then carries
+// the hash returned by the controller
+func (c *Controller) updateUser(w http.ResponseWriter, r *http.Request, path []string) {
+
+	key := userKey(path[0], r.URL.Query().Get("component"))
+	existing, ok := c.users[key]
+	if !ok {
+		writeError(w, http.StatusNotFound, "User %s does not exist", key)
+		return
+	}
+
+	config, ok := decodeUser(w, r)
+	if !ok {
+		return
+	}
+
+	if config.UsernameWithComponent != key {
+		writeError(w, http.StatusBadRequest, "Request user %s does not match %s in the body", key, config.UsernameWithComponent)
+		return
+	}
+
+	passwordHash := existing.passwordHash
+	if r.URL.Query().Get("passwordChanged") == "true" {
+		passwordHash = hashPassword(config.Password)
+	}
+
+	c.users[key] = &user{config: config, passwordHash: passwordHash}
+	writeStatus(w, "User config update for %s", key)
+}
+
+func (c *Controller) deleteUser(w http.ResponseWriter, r *http.Request, path []string) {
+
+	key := userKey(path[0], r.URL.Query().Get("component"))
+	if _, ok := c.users[key]; !ok {
+		writeError(w, http.StatusNotFound, "User %s does not exist", key)
+		return
+	}
+
+	delete(c.users, key)
+	writeStatus(w, "User: %s has been successfully deleted", key)
+}
@@ -0,0 +1,178 @@
+package goPinotAPI
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limits caps the rate and concurrency of a class of requests, zero values are unlimited
+type Limits struct {
+	RequestsPerSecond float64
+	// Burst is how many requests can be sent at once after a quiet period, defaults to 1
+	Burst int
+	// MaxInFlight is how many requests can wait for a response at once, a request stays in
+	// flight until its response body is closed
+	MaxInFlight int
+}
+
+// RateLimitOptions sets limits per class of request
+type RateLimitOptions struct {
+	Reads     Limits // GET and HEAD requests
+	Mutations Limits // every other request
+}
+
+const rateLimit = "rateLimit"
+
+// rateLimitOpt is an option to limit the requests the client sends
+type rateLimitOpt struct {
+	options RateLimitOptions
+}
+
+func (o *rateLimitOpt) apply(c *cfg) {
+	c.rateLimit = o.options
+}
+
+func (o *rateLimitOpt) Type() string {
+	return rateLimit
+}
+
+// RateLimit limits the rate and concurrency of the requests the client sends, so bulk operations
+// don't overload the controller. Requests over the limits wait for their turn until their context
+// is done, see WithContext
+func RateLimit(options RateLimitOptions) Opt {
+	return &rateLimitOpt{options: options}
+}
+
+// limiter enforces the Limits of a class of requests
+type limiter struct {
+	bucket   *tokenBucket
+	inFlight chan struct{}
+}
+
+func newLimiter(limits Limits) *limiter {
+
+	if limits.RequestsPerSecond <= 0 && limits.MaxInFlight <= 0 {
+		return nil
+	}
+
+	l := &limiter{}
+	if limits.RequestsPerSecond > 0 {
+		l.bucket = newTokenBucket(limits.RequestsPerSecond, max(limits.Burst, 1))
+	}
+	if limits.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limits.MaxInFlight)
+	}
+
+	return l
+}
+
+// acquire waits for an in flight slot and then for a token, returning the func releasing the slot
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+
+	release := func() {}
+
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-l.inFlight }) }
+	}
+
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// tokenBucket refills at rate tokens a second up to burst, a request takes one token
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait reserves a token, waiting for it to be refilled when the bucket is empty
+func (b *tokenBucket) wait(ctx context.Context) error {
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(deficit / b.rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// hand the reservation back for the requests queued behind this one
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// limit waits for the limiter of the request's class and sends the request
+func (p *pinotHttp) limit(req *http.Request, send Handler) (*http.Response, error) {
+
+	l := p.mutationLimiter
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		l = p.readLimiter
+	}
+
+	if l == nil {
+		return send(req)
+	}
+
+	release, err := l.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := send(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	res.Body = &releasingBody{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+// releasingBody releases the in flight slot of a request when its response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,105 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	controller := createMockController(t)
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controller.URL),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.RateLimit(goPinotAPI.RateLimitOptions{
+			Reads: goPinotAPI.Limits{RequestsPerSecond: 50, Burst: 2},
+		}),
+	)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		_, err := client.GetClusterInfo()
+		assert.NoError(t, err)
+	}
+	// the first 2 requests use the burst, the other 4 wait 20ms each
+	assert.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond, "Expected reads to be rate limited")
+
+	start = time.Now()
+	for i := 0; i < 6; i++ {
+		_, err := client.EnableInstance("Server_172.17.0.4_7050")
+		assert.NoError(t, err)
+	}
+	assert.Less(t, time.Since(start), 70*time.Millisecond, "Expected mutations not to be limited by the read limit")
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteClusterInfo, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"clusterName": "PinotCluster"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(server.URL),
+		goPinotAPI.RateLimit(goPinotAPI.RateLimitOptions{
+			Reads: goPinotAPI.Limits{MaxInFlight: 2},
+		}),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetClusterInfo()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight), "Expected at most 2 requests in flight")
+}
+
+func TestRateLimitContext(t *testing.T) {
+	controller := createMockController(t)
+
+	client := goPinotAPI.NewPinotAPIClient(
+		goPinotAPI.ControllerUrl(controller.URL),
+		goPinotAPI.AuthToken("YWRtaW46YWRtaW4K"),
+		goPinotAPI.RateLimit(goPinotAPI.RateLimitOptions{
+			Reads: goPinotAPI.Limits{RequestsPerSecond: 0.1},
+		}),
+	)
+
+	_, err := client.GetClusterInfo()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.WithContext(ctx).GetClusterInfo()
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected waiting for the rate limit to stop with the context")
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,115 @@
+package goPinotAPI
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// PlanTableRebalance runs a dry run rebalance of the table and compares the target segment assignment
+// with the current ideal state to show which segments would move between servers
+func (c *PinotAPIClient) PlanTableRebalance(tableName string, tableType string, options model.RebalanceTableOptions) (*model.RebalancePlan, error) {
+
+	options.DryRun = true
+	result, err := c.RebalanceTable(tableName, tableType, options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dry run rebalance of table %s: %w", tableName, err)
+	}
+
+	if result.Status == model.RebalanceStatusFailed {
+		return nil, fmt.Errorf("dry run rebalance of table %s failed: %s", tableName, result.Description)
+	}
+
+	idealState, err := c.GetTableIdealState(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ideal state of table %s: %w", tableName, err)
+	}
+
+	currentAssignment := idealState.Offline
+	if strings.EqualFold(tableType, "REALTIME") {
+		currentAssignment = idealState.Realtime
+	}
+
+	return planSegmentMovements(*result, currentAssignment), nil
+}
+
+func planSegmentMovements(result model.RebalanceResult, currentAssignment map[string]map[string]string) *model.RebalancePlan {
+
+	plan := &model.RebalancePlan{
+		Result:           result,
+		SegmentMovements: []model.SegmentMovement{},
+	}
+
+	segmentNames := make(map[string]bool)
+	for segmentName := range currentAssignment {
+		segmentNames[segmentName] = true
+	}
+	for segmentName := range result.SegmentAssignment {
+		segmentNames[segmentName] = true
+	}
+	plan.TotalSegments = len(segmentNames)
+
+	instancesInvolved := make(map[string]bool)
+
+	for segmentName := range segmentNames {
+
+		current := sortedKeys(currentAssignment[segmentName])
+		target := sortedKeys(result.SegmentAssignment[segmentName])
+
+		added := difference(target, current)
+		removed := difference(current, target)
+
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		plan.SegmentMovements = append(plan.SegmentMovements, model.SegmentMovement{
+			SegmentName:      segmentName,
+			CurrentInstances: current,
+			TargetInstances:  target,
+			InstancesAdded:   added,
+			InstancesRemoved: removed,
+		})
+		plan.ReplicasToAdd += len(added)
+		plan.ReplicasToRemove += len(removed)
+
+		for _, instance := range append(added, removed...) {
+			instancesInvolved[instance] = true
+		}
+	}
+
+	sort.Slice(plan.SegmentMovements, func(i, j int) bool {
+		return plan.SegmentMovements[i].SegmentName < plan.SegmentMovements[j].SegmentName
+	})
+
+	plan.SegmentsToMove = len(plan.SegmentMovements)
+	plan.InstancesInvolved = sortedKeys(instancesInvolved)
+
+	return plan
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// difference returns the elements of a that are not in b
+func difference(a []string, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !inB[s] {
+			diff = append(diff, s)
+		}
+	}
+	return diff
+}
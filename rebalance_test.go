@@ -0,0 +1,30 @@
+package goPinotAPI_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanTableRebalance(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	// a second replica has to be added to the server without the segment
+	_, err := client.UpdateTable("test", []byte(strings.Replace(testTableConfig, `"replication": "1"`, `"replication": "2"`, 1)))
+	assert.NoError(t, err)
+
+	plan, err := client.PlanTableRebalance("test", "OFFLINE", model.RebalanceTableOptions{})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, 1, plan.TotalSegments, "Expected 1 segment in the table")
+	assert.Equal(t, 1, plan.SegmentsToMove, "Expected 1 segment to move")
+	assert.Equal(t, 1, plan.ReplicasToAdd, "Expected 1 replica to be added")
+	assert.Equal(t, 0, plan.ReplicasToRemove, "Expected no replicas to be removed")
+	assert.Equal(t, []string{"Server_172.17.0.4_7050"}, plan.SegmentMovements[0].InstancesAdded, "Expected segment to be added to the new server")
+	assert.Equal(t, []string{"Server_172.17.0.4_7050"}, plan.InstancesInvolved, "Expected only the new server to be involved")
+}
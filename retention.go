@@ -0,0 +1,158 @@
+package goPinotAPI
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+// timeUnitDurations maps the java TimeUnit names Pinot uses in table and segment configs to durations
+var timeUnitDurations = map[string]time.Duration{
+	"NANOSECONDS":  time.Nanosecond,
+	"MICROSECONDS": time.Microsecond,
+	"MILLISECONDS": time.Millisecond,
+	"SECONDS":      time.Second,
+	"MINUTES":      time.Minute,
+	"HOURS":        time.Hour,
+	"DAYS":         24 * time.Hour,
+}
+
+// PreviewRetention lists the segments the retention manager would purge if the table's retention
+// was set to retentionTimeValue retentionTimeUnit, without changing the table config
+func (c *PinotAPIClient) PreviewRetention(tableName string, retentionTimeUnit string, retentionTimeValue string) (*model.RetentionPreview, error) {
+	return c.PreviewRetentionAt(tableName, retentionTimeUnit, retentionTimeValue, time.Now())
+}
+
+// PreviewRetentionAt is PreviewRetention evaluated as if the retention manager ran at the given time
+func (c *PinotAPIClient) PreviewRetentionAt(tableName string, retentionTimeUnit string, retentionTimeValue string, now time.Time) (*model.RetentionPreview, error) {
+
+	retention, err := parseRetention(retentionTimeUnit, retentionTimeValue)
+	if err != nil {
+		return nil, err
+	}
+
+	zkMetadata, err := c.GetSegmentZKMetadata(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get segment zk metadata for table %s: %w", tableName, err)
+	}
+
+	tableSize, err := c.GetTableSize(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get size of table %s: %w", tableName, err)
+	}
+
+	cutoff := now.Add(-retention)
+
+	preview := &model.RetentionPreview{
+		TableName:          tableName,
+		RetentionTimeUnit:  strings.ToUpper(retentionTimeUnit),
+		RetentionTimeValue: retentionTimeValue,
+		Cutoff:             cutoff,
+		TotalSegments:      len(*zkMetadata),
+		PurgedSegments:     []model.RetentionPreviewSegment{},
+		TableSizeInBytes:   tableSize.ReportedSizeInBytes,
+	}
+
+	for segmentName, metadata := range *zkMetadata {
+
+		endTime, ok := segmentEndTime(metadata)
+		if !ok {
+			// consuming segments and segments without a time column are never purged by retention
+			preview.SkippedSegments = append(preview.SkippedSegments, segmentName)
+			continue
+		}
+
+		if !endTime.Before(cutoff) {
+			continue
+		}
+
+		totalDocs, _ := strconv.ParseInt(metadata.SegmentTotalDocs, 10, 64)
+		sizeInBytes := segmentReportedSize(tableSize, segmentName)
+
+		preview.PurgedSegments = append(preview.PurgedSegments, model.RetentionPreviewSegment{
+			SegmentName: segmentName,
+			EndTime:     endTime,
+			AgeInDays:   now.Sub(endTime).Hours() / 24,
+			SizeInBytes: sizeInBytes,
+			TotalDocs:   totalDocs,
+			SegmentTier: metadata.SegmentTier,
+		})
+		preview.PurgedSizeInBytes += sizeInBytes
+	}
+
+	sort.Strings(preview.SkippedSegments)
+	sort.Slice(preview.PurgedSegments, func(i, j int) bool {
+		return preview.PurgedSegments[i].EndTime.Before(preview.PurgedSegments[j].EndTime)
+	})
+
+	return preview, nil
+}
+
+func parseRetention(retentionTimeUnit string, retentionTimeValue string) (time.Duration, error) {
+
+	unit, ok := timeUnitDurations[strings.ToUpper(retentionTimeUnit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid retention time unit: %s", retentionTimeUnit)
+	}
+
+	value, err := strconv.ParseInt(retentionTimeValue, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retention time value %s: %w", retentionTimeValue, err)
+	}
+
+	if value <= 0 {
+		return 0, fmt.Errorf("retention time value must be positive, got %d", value)
+	}
+
+	if value > math.MaxInt64/int64(unit) {
+		return 0, fmt.Errorf("retention of %d %s is too long", value, retentionTimeUnit)
+	}
+
+	return time.Duration(value) * unit, nil
+}
+
+func segmentEndTime(metadata model.ZkSegmentMetadata) (time.Time, bool) {
+
+	if metadata.SegmentEndTime == "" {
+		return time.Time{}, false
+	}
+
+	endTime, err := strconv.ParseInt(metadata.SegmentEndTime, 10, 64)
+	if err != nil || endTime < 0 {
+		return time.Time{}, false
+	}
+
+	// end time is stored in the segment time unit, defaulting to milliseconds
+	unit := time.Millisecond
+	if metadata.SegmentTimeUnit != "" {
+		var ok bool
+		unit, ok = timeUnitDurations[strings.ToUpper(metadata.SegmentTimeUnit)]
+		if !ok {
+			return time.Time{}, false
+		}
+	}
+
+	if endTime > math.MaxInt64/int64(unit) {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, 0).Add(time.Duration(endTime) * unit), true
+}
+
+func segmentReportedSize(tableSize *model.GetTableSizeResponse, segmentName string) int64 {
+
+	if detail, ok := tableSize.OfflineSegments.Segments[segmentName]; ok {
+		return detail.ReportedSizeInBytes
+	}
+
+	if detail, ok := tableSize.RealtimeSegments.Segments[segmentName]; ok {
+		return detail.ReportedSizeInBytes
+	}
+
+	return 0
+}
@@ -0,0 +1,54 @@
+package goPinotAPI_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// end of day 16101 since epoch, the newest segment in the mock zk metadata
+var retentionPreviewNow = time.UnixMilli(16101 * 24 * 60 * 60 * 1000)
+
+func TestPreviewRetention(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.PreviewRetentionAt("test", "DAYS", "29", retentionPreviewNow)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, 31, res.TotalSegments, "Expected 31 segments in the table")
+	assert.Equal(t, 1, len(res.PurgedSegments), "Expected 1 segment to be purged")
+	assert.Equal(t, "test_OFFLINE_16071_16071_0", res.PurgedSegments[0].SegmentName, "Expected oldest segment to be purged")
+	assert.Equal(t, int64(148141), res.PurgedSizeInBytes, "Expected purged size to be 148141")
+	assert.Equal(t, int64(4723495), res.TableSizeInBytes, "Expected table size to be 4723495")
+}
+
+func TestPreviewRetentionNothingPurged(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.PreviewRetentionAt("test", "days", "30", retentionPreviewNow)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, 0, len(res.PurgedSegments), "Expected no segments to be purged")
+	assert.Equal(t, int64(0), res.PurgedSizeInBytes, "Expected purged size to be 0")
+}
+
+func TestPreviewRetentionInvalidRetention(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.PreviewRetention("test", "WEEKS", "1")
+	assert.Error(t, err, "Expected error for invalid retention time unit")
+
+	_, err = client.PreviewRetention("test", "DAYS", "-1")
+	assert.Error(t, err, "Expected error for negative retention time value")
+
+	_, err = client.PreviewRetention("test", "DAYS", "9999999999")
+	assert.ErrorContains(t, err, "too long", "Expected error for a retention that overflows")
+}
@@ -0,0 +1,218 @@
+package goPinotAPI
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+const passwordAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#%^&*-_=+"
+
+// UserSyncOptions controls how SyncUsers reconciles the desired users against the cluster
+type UserSyncOptions struct {
+	DryRun bool // report the changes without making them
+	Prune  bool // delete users that are not in the desired list
+	// UpdatePasswords pushes the desired password of every existing user, as the controller only
+	// returns password hashes and a changed password can't otherwise be detected
+	UpdatePasswords bool
+}
+
+// GeneratePassword returns a random password of the given length drawn from crypto/rand
+func GeneratePassword(length int) (string, error) {
+
+	if length <= 0 {
+		return "", fmt.Errorf("password length must be positive, got %d", length)
+	}
+
+	password := make([]byte, length)
+	alphabetSize := big.NewInt(int64(len(passwordAlphabet)))
+	for i := range password {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("unable to generate password: %w", err)
+		}
+		password[i] = passwordAlphabet[n.Int64()]
+	}
+
+	return string(password), nil
+}
+
+// RotateUserPassword sets a new password for an existing user, keeping its role and table grants
+func (c *PinotAPIClient) RotateUserPassword(username string, component model.UserComponent, newPassword string) (*model.UserActionResponse, error) {
+
+	if newPassword == "" {
+		return nil, fmt.Errorf("new password for user %s_%s must not be empty", username, component)
+	}
+
+	user, err := c.GetUser(username, component)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get user %s_%s: %w", username, component, err)
+	}
+
+	if user.Username == "" {
+		return nil, fmt.Errorf("user %s_%s not found", username, component)
+	}
+
+	user.Password = newPassword
+
+	userBytes, err := json.Marshal(user)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal user %s_%s: %w", username, component, err)
+	}
+
+	return c.UpdateUser(username, component, true, userBytes)
+}
+
+// RotateUserPasswordGenerated rotates the password of a user to a generated one of the given length,
+// returning the new password
+func (c *PinotAPIClient) RotateUserPasswordGenerated(username string, component model.UserComponent, length int) (string, error) {
+
+	password, err := GeneratePassword(length)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := c.RotateUserPassword(username, component, password); err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+// SyncUsers reconciles the users in the cluster against the desired list. Missing users are created,
+// users whose role, permissions or tables differ are updated and, with Prune, users that are not
+// desired are deleted. Users are matched on username and component
+func (c *PinotAPIClient) SyncUsers(desired []model.User, opts *UserSyncOptions) (*model.UserSyncResult, error) {
+
+	if opts == nil {
+		opts = &UserSyncOptions{}
+	}
+
+	desiredUsers := make(map[string]model.User, len(desired))
+	for _, user := range desired {
+		if user.Username == "" || user.Component == "" {
+			return nil, fmt.Errorf("desired user %q must have a username and component", user.Username)
+		}
+		key := userKey(user)
+		if _, ok := desiredUsers[key]; ok {
+			return nil, fmt.Errorf("duplicate desired user %s", key)
+		}
+		desiredUsers[key] = user
+	}
+
+	existing, err := c.GetUsers()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get users: %w", err)
+	}
+
+	existingUsers := make(map[string]model.User, len(existing.Users))
+	for _, user := range existing.Users {
+		existingUsers[userKey(user)] = user
+	}
+
+	result := &model.UserSyncResult{
+		Created:   []string{},
+		Updated:   []string{},
+		Deleted:   []string{},
+		Unchanged: []string{},
+		DryRun:    opts.DryRun,
+	}
+
+	for _, key := range sortedKeys(desiredUsers) {
+		user := desiredUsers[key]
+
+		current, ok := existingUsers[key]
+		if !ok {
+			if !opts.DryRun {
+				if _, err := c.CreateUser(user); err != nil {
+					return result, fmt.Errorf("unable to create user %s: %w", key, err)
+				}
+			}
+			result.Created = append(result.Created, key)
+			continue
+		}
+
+		if !opts.UpdatePasswords && userGrantsEqual(current, user) {
+			result.Unchanged = append(result.Unchanged, key)
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := c.syncUser(current, user, opts.UpdatePasswords); err != nil {
+				return result, fmt.Errorf("unable to update user %s: %w", key, err)
+			}
+		}
+		result.Updated = append(result.Updated, key)
+	}
+
+	if opts.Prune {
+		for _, key := range sortedKeys(existingUsers) {
+			if _, ok := desiredUsers[key]; ok {
+				continue
+			}
+
+			user := existingUsers[key]
+			if !opts.DryRun {
+				if _, err := c.DeleteUser(user.Username, user.Component); err != nil {
+					return result, fmt.Errorf("unable to delete user %s: %w", key, err)
+				}
+			}
+			result.Deleted = append(result.Deleted, key)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *PinotAPIClient) syncUser(current model.User, desired model.User, updatePassword bool) error {
+
+	// without a password change the stored hash is sent back unchanged
+	if !updatePassword || desired.Password == "" {
+		desired.Password = current.Password
+		updatePassword = false
+	}
+
+	userBytes, err := json.Marshal(desired)
+	if err != nil {
+		return fmt.Errorf("unable to marshal user: %w", err)
+	}
+
+	_, err = c.UpdateUser(desired.Username, desired.Component, updatePassword, userBytes)
+	return err
+}
+
+func userKey(user model.User) string {
+	return fmt.Sprintf("%s_%s", user.Username, strings.ToUpper(string(user.Component)))
+}
+
+func userGrantsEqual(a model.User, b model.User) bool {
+	return strings.EqualFold(string(a.Role), string(b.Role)) &&
+		stringSetsEqual(a.Permissions, b.Permissions, func(p model.Permission) model.Permission {
+			return model.Permission(strings.ToUpper(string(p)))
+		}) &&
+		stringSetsEqual(a.Tables, b.Tables, func(s string) string { return s })
+}
+
+func stringSetsEqual[S ~string](a []S, b []S, normalize func(S) S) bool {
+
+	setA := make([]S, 0, len(a))
+	for _, s := range a {
+		setA = append(setA, normalize(s))
+	}
+	setB := make([]S, 0, len(b))
+	for _, s := range b {
+		setB = append(setB, normalize(s))
+	}
+
+	slices.Sort(setA)
+	setA = slices.Compact(setA)
+	slices.Sort(setB)
+	setB = slices.Compact(setB)
+
+	return slices.Equal(setA, setB)
+}
@@ -0,0 +1,122 @@
+package goPinotAPI_test
+
+import (
+	"testing"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/azaurus1/go-pinot-api/pinottest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	password, err := goPinotAPI.GeneratePassword(24)
+	assert.NoError(t, err)
+	assert.Len(t, password, 24)
+
+	other, err := goPinotAPI.GeneratePassword(24)
+	assert.NoError(t, err)
+	assert.NotEqual(t, password, other, "Expected generated passwords to differ")
+
+	_, err = goPinotAPI.GeneratePassword(0)
+	assert.Error(t, err, "Expected error for non positive length")
+}
+
+func TestRotateUserPassword(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.RotateUserPassword("test", model.UserComponentBroker, "newPassword")
+	assert.NoError(t, err)
+	assert.Equal(t, "User config update for test_BROKER", res.Status)
+
+	password, err := client.RotateUserPasswordGenerated("test", model.UserComponentBroker, 16)
+	assert.NoError(t, err)
+	assert.Len(t, password, 16)
+
+	_, err = client.RotateUserPassword("test", model.UserComponentServer, "newPassword")
+	assert.Error(t, err, "Expected error for a user that does not exist")
+}
+
+func TestSyncUsers(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	desired := []model.User{
+		{
+			Username:    "test",
+			Password:    "password",
+			Component:   model.UserComponentBroker,
+			Role:        model.UserRoleUser,
+			Permissions: []model.Permission{model.PermissionRead},
+			Tables:      []string{"airlineStats"},
+		},
+		{
+			Username:  "testUser",
+			Password:  "password",
+			Component: model.UserComponentBroker,
+			Role:      model.UserRoleAdmin,
+		},
+	}
+
+	res, err := client.SyncUsers(desired, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testUser_BROKER"}, res.Created)
+	assert.Equal(t, []string{"test_BROKER"}, res.Updated)
+	assert.Empty(t, res.Deleted)
+	assert.Empty(t, res.Unchanged)
+}
+
+func TestSyncUsersUnchangedAndPrune(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.SyncUsers([]model.User{
+		{Username: "test", Component: "broker", Role: "admin"},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_BROKER"}, res.Unchanged, "Expected role and component to match case insensitively")
+
+	res, err = client.SyncUsers([]model.User{}, &goPinotAPI.UserSyncOptions{Prune: true, DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, res.DryRun)
+	assert.Equal(t, []string{"test_BROKER"}, res.Deleted)
+
+	res, err = client.SyncUsers([]model.User{}, &goPinotAPI.UserSyncOptions{Prune: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_BROKER"}, res.Deleted)
+
+	_, err = client.SyncUsers([]model.User{{Username: "test"}}, nil)
+	assert.Error(t, err, "Expected error for a user without a component")
+}
+
+func TestSyncUsersConverges(t *testing.T) {
+	controller := pinottest.NewController(pinottest.Options{})
+	defer controller.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(controller.URL))
+
+	desired := []model.User{
+		{Username: "reader", Password: "password", Component: model.UserComponentBroker, Role: model.UserRoleUser, Permissions: []model.Permission{model.PermissionRead}},
+		{Username: "admin", Password: "password", Component: model.UserComponentController, Role: model.UserRoleAdmin},
+	}
+
+	res, err := client.SyncUsers(desired, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin_CONTROLLER", "reader_BROKER"}, res.Created)
+
+	res, err = client.SyncUsers(desired, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Created)
+	assert.Empty(t, res.Updated)
+	assert.Equal(t, []string{"admin_CONTROLLER", "reader_BROKER"}, res.Unchanged, "Expected a second sync to change nothing")
+
+	res, err = client.SyncUsers(desired[:1], &goPinotAPI.UserSyncOptions{Prune: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin_CONTROLLER"}, res.Deleted)
+
+	users, err := client.GetUsers()
+	assert.NoError(t, err)
+	assert.Len(t, users.Users, 1)
+	assert.True(t, controller.CheckPassword("reader", model.UserComponentBroker, "password"))
+}
@@ -0,0 +1,329 @@
+package goPinotAPI
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azaurus1/go-pinot-api/model"
+)
+
+const (
+	defaultWaitInitialInterval = time.Second
+	defaultWaitMaxInterval     = 30 * time.Second
+	defaultWaitMultiplier      = 2.0
+)
+
+// WaitOptions configures how often a waiter polls, the overall timeout is taken from the context
+type WaitOptions struct {
+	// InitialInterval is the delay before the second poll, defaults to 1s
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between polls, defaults to 30s
+	MaxInterval time.Duration
+	// Multiplier grows the delay after each poll, defaults to 2
+	Multiplier float64
+	// OnProgress is called with the status returned by every poll
+	OnProgress func(progress WaitProgress)
+}
+
+// WaitProgress is passed to WaitOptions.OnProgress after every poll
+type WaitProgress struct {
+	Attempt int
+	Elapsed time.Duration
+	Status  any
+}
+
+func (o *WaitOptions) withDefaults() WaitOptions {
+	var opts WaitOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = defaultWaitInitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaultWaitMaxInterval
+	}
+	if opts.MaxInterval < opts.InitialInterval {
+		opts.MaxInterval = opts.InitialInterval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaultWaitMultiplier
+	}
+	return opts
+}
+
+// PollUntil calls poll with an exponentially growing interval until it reports done, returns an
+// error, or ctx is done. The last status polled is returned along with any error.
+func PollUntil[T any](ctx context.Context, opts *WaitOptions, poll func() (T, bool, error)) (T, error) {
+
+	waitOpts := opts.withDefaults()
+	start := time.Now()
+	interval := waitOpts.InitialInterval
+
+	var status T
+	for attempt := 1; ; attempt++ {
+
+		if err := ctx.Err(); err != nil {
+			return status, fmt.Errorf("client: stopped waiting after %d attempts: %w", attempt-1, err)
+		}
+
+		var done bool
+		var err error
+		status, done, err = poll()
+		if err != nil {
+			return status, err
+		}
+
+		if waitOpts.OnProgress != nil {
+			waitOpts.OnProgress(WaitProgress{Attempt: attempt, Elapsed: time.Since(start), Status: status})
+		}
+
+		if done {
+			return status, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, fmt.Errorf("client: stopped waiting after %d attempts: %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * waitOpts.Multiplier)
+		if interval > waitOpts.MaxInterval {
+			interval = waitOpts.MaxInterval
+		}
+	}
+}
+
+// WaitForReload waits for a reload job to reload every segment, it returns as soon as a server call fails
+// so check TotalServerCallsFailed on the returned status
+func (c *PinotAPIClient) WaitForReload(ctx context.Context, jobId string, opts *WaitOptions) (*model.GetReloadJobStatusResponse, error) {
+	return PollUntil(ctx, opts, func() (*model.GetReloadJobStatusResponse, bool, error) {
+		status, err := c.GetReloadJobStatus(jobId)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to get status of reload job %s: %w", jobId, err)
+		}
+		return status, status.IsComplete(), nil
+	})
+}
+
+// ReloadTableSegmentsAndWait reloads all segments of a table and waits for the reload jobs to finish,
+// the result is keyed by table name with type
+func (c *PinotAPIClient) ReloadTableSegmentsAndWait(ctx context.Context, tableName string, opts *WaitOptions) (map[string]*model.GetReloadJobStatusResponse, error) {
+
+	res, err := c.ReloadTableSegments(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reload segments of table %s: %w", tableName, err)
+	}
+
+	jobs, err := model.ParseReloadJobs(res.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*model.GetReloadJobStatusResponse, len(jobs))
+	for tableNameWithType, job := range jobs {
+		status, err := c.WaitForReload(ctx, job.ReloadJobId, opts)
+		if err != nil {
+			return result, fmt.Errorf("reload of table %s did not complete: %w", tableNameWithType, err)
+		}
+		result[tableNameWithType] = status
+	}
+
+	return result, nil
+}
+
+// WaitForTenantRebalance waits for a tenant rebalance job to finish rebalancing every table
+func (c *PinotAPIClient) WaitForTenantRebalance(ctx context.Context, jobId string, opts *WaitOptions) (*model.GetTenantRebalanceStatusResponse, error) {
+	return PollUntil(ctx, opts, func() (*model.GetTenantRebalanceStatusResponse, bool, error) {
+		status, err := c.GetTenantRebalanceStatus(jobId)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to get status of tenant rebalance job %s: %w", jobId, err)
+		}
+		return status, status.IsComplete(), nil
+	})
+}
+
+// RebalanceTenantAndWait rebalances a tenant and waits for the rebalance job to finish
+func (c *PinotAPIClient) RebalanceTenantAndWait(ctx context.Context, tenantName string, config model.TenantRebalanceConfig, opts *WaitOptions) (*model.GetTenantRebalanceStatusResponse, error) {
+
+	res, err := c.RebalanceTenant(tenantName, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to rebalance tenant %s: %w", tenantName, err)
+	}
+
+	if res.JobId == "" {
+		return nil, fmt.Errorf("rebalance of tenant %s did not return a job id", tenantName)
+	}
+
+	return c.WaitForTenantRebalance(ctx, res.JobId, opts)
+}
+
+// WaitForTableRebalance waits for a table rebalance job to stop running, the final status may be
+// DONE, NO_OP, FAILED, ABORTED or CANCELLED
+func (c *PinotAPIClient) WaitForTableRebalance(ctx context.Context, jobId string, opts *WaitOptions) (*model.GetTableRebalanceStatusResponse, error) {
+	return PollUntil(ctx, opts, func() (*model.GetTableRebalanceStatusResponse, bool, error) {
+		status, err := c.GetTableRebalanceStatus(jobId)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to get status of table rebalance job %s: %w", jobId, err)
+		}
+		return status, status.IsComplete(), nil
+	})
+}
+
+// RebalanceTableAndWait rebalances a table and waits for the rebalance job to stop running
+func (c *PinotAPIClient) RebalanceTableAndWait(ctx context.Context, tableName string, tableType string, options model.RebalanceTableOptions, opts *WaitOptions) (*model.GetTableRebalanceStatusResponse, error) {
+
+	result, err := c.RebalanceTable(tableName, tableType, options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to rebalance table %s: %w", tableName, err)
+	}
+
+	// dry runs and no-op rebalances finish synchronously without a job to poll
+	if result.Status != model.RebalanceStatusInProgress {
+		return &model.GetTableRebalanceStatusResponse{
+			TableRebalanceProgressStats: model.TableRebalanceProgressStats{
+				Status:              result.Status,
+				CompletionStatusMsg: result.Description,
+			},
+		}, nil
+	}
+
+	return c.WaitForTableRebalance(ctx, result.JobId, opts)
+}
+
+// WaitForForceCommit waits for every segment of a force commit job to be committed
+func (c *PinotAPIClient) WaitForForceCommit(ctx context.Context, jobId string, opts *WaitOptions) (*model.GetForceCommitStatusResponse, error) {
+	return PollUntil(ctx, opts, func() (*model.GetForceCommitStatusResponse, bool, error) {
+		status, err := c.GetForceCommitStatus(jobId)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to get status of force commit job %s: %w", jobId, err)
+		}
+		return status, status.IsComplete(), nil
+	})
+}
+
+// ForceCommitAndWait force commits the consuming segments of a realtime table and waits for them to be committed
+func (c *PinotAPIClient) ForceCommitAndWait(ctx context.Context, tableName string, options model.ForceCommitOptions, opts *WaitOptions) (*model.GetForceCommitStatusResponse, error) {
+
+	res, err := c.ForceCommit(tableName, options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to force commit table %s: %w", tableName, err)
+	}
+
+	if res.ForceCommitJobId == "" {
+		return nil, fmt.Errorf("force commit of table %s did not return a job id", tableName)
+	}
+
+	return c.WaitForForceCommit(ctx, res.ForceCommitJobId, opts)
+}
+
+// WaitForTask waits for a minion task to reach a final state
+func (c *PinotAPIClient) WaitForTask(ctx context.Context, taskName string, opts *WaitOptions) (model.TaskState, error) {
+	return PollUntil(ctx, opts, func() (model.TaskState, bool, error) {
+		state, err := c.GetTaskState(taskName)
+		if err != nil {
+			return state, false, fmt.Errorf("unable to get state of task %s: %w", taskName, err)
+		}
+		return state, state.IsFinal(), nil
+	})
+}
+
+// ScheduleTasksAndWait schedules tasks and waits for every scheduled task to reach a final state,
+// the result maps task names to their final state
+func (c *PinotAPIClient) ScheduleTasksAndWait(ctx context.Context, taskType string, tableNameWithType string, opts *WaitOptions) (map[string]model.TaskState, error) {
+
+	res, err := c.ScheduleTasks(taskType, tableNameWithType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to schedule tasks: %w", err)
+	}
+
+	result := make(map[string]model.TaskState)
+	for _, taskNames := range *res {
+		for _, taskName := range strings.Split(taskNames, ",") {
+			taskName = strings.TrimSpace(taskName)
+			if taskName == "" {
+				continue
+			}
+			state, err := c.WaitForTask(ctx, taskName, opts)
+			result[taskName] = state
+			if err != nil {
+				return result, fmt.Errorf("task %s did not finish: %w", taskName, err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetTableConvergence compares the ideal state of a table with its external view
+func (c *PinotAPIClient) GetTableConvergence(tableName string) (*model.TableConvergence, error) {
+
+	idealState, err := c.GetTableIdealState(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ideal state of table %s: %w", tableName, err)
+	}
+
+	externalView, err := c.GetTableExternalView(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get external view of table %s: %w", tableName, err)
+	}
+
+	pending := make(map[string]map[string]string)
+	comparePartialState(idealState.Offline, externalView.Offline, pending)
+	comparePartialState(idealState.Realtime, externalView.Realtime, pending)
+
+	convergence := &model.TableConvergence{Converged: len(pending) == 0}
+	if len(pending) > 0 {
+		convergence.PendingSegments = pending
+	}
+
+	return convergence, nil
+}
+
+// WaitForTableConvergence waits for the external view of a table to match its ideal state
+func (c *PinotAPIClient) WaitForTableConvergence(ctx context.Context, tableName string, opts *WaitOptions) (*model.TableConvergence, error) {
+	return PollUntil(ctx, opts, func() (*model.TableConvergence, bool, error) {
+		convergence, err := c.GetTableConvergence(tableName)
+		if err != nil {
+			return nil, false, err
+		}
+		return convergence, convergence.Converged, nil
+	})
+}
+
+// ChangeTableStateAndWait changes the state of a table and waits for its external view to converge
+func (c *PinotAPIClient) ChangeTableStateAndWait(ctx context.Context, tableName string, tableType string, state string, opts *WaitOptions) (*model.TableConvergence, error) {
+
+	_, err := c.ChangeTableState(tableName, tableType, state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to %s table %s: %w", state, tableName, err)
+	}
+
+	return c.WaitForTableConvergence(ctx, tableName, opts)
+}
+
+// comparePartialState records in pending every segment replica whose external view state differs from its ideal state
+func comparePartialState(idealState map[string]map[string]string, externalView map[string]map[string]string, pending map[string]map[string]string) {
+	for segmentName, instanceStates := range idealState {
+		for instanceName, idealInstanceState := range instanceStates {
+			// replicas that should be offline or dropped are allowed to be missing from the external view
+			if idealInstanceState == "OFFLINE" || idealInstanceState == "DROPPED" {
+				if viewState, ok := externalView[segmentName][instanceName]; !ok || viewState == idealInstanceState {
+					continue
+				}
+			} else if externalView[segmentName][instanceName] == idealInstanceState {
+				continue
+			}
+
+			if pending[segmentName] == nil {
+				pending[segmentName] = make(map[string]string)
+			}
+			pending[segmentName][instanceName] = idealInstanceState
+		}
+	}
+}
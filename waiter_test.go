@@ -0,0 +1,204 @@
+package goPinotAPI_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goPinotAPI "github.com/azaurus1/go-pinot-api"
+	"github.com/azaurus1/go-pinot-api/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var fastWaitOptions = &goPinotAPI.WaitOptions{
+	InitialInterval: time.Millisecond,
+	MaxInterval:     5 * time.Millisecond,
+}
+
+func TestReloadTableSegmentsAndWait(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ReloadTableSegmentsAndWait(context.Background(), "test", fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res["test_OFFLINE"].SuccessCount, 1, "Expected 1 segment to be reloaded")
+}
+
+func TestWaitForReloadWaitsForCounts(t *testing.T) {
+
+	// the job reports no segments until servers have been queried
+	var polls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/segments/segmentReloadStatus/f9db13c7-3ad6-45a8-a08f-75cd03c42fb5", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			fmt.Fprint(w, `{"totalSegmentCount": 0, "successCount": 0, "totalServersQueried": 0, "totalServerCallsFailed": 0}`)
+			return
+		}
+		fmt.Fprint(w, `{"totalSegmentCount": 31, "successCount": 31, "totalServersQueried": 2, "totalServerCallsFailed": 0}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(server.URL))
+
+	res, err := client.WaitForReload(context.Background(), "f9db13c7-3ad6-45a8-a08f-75cd03c42fb5", fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.SuccessCount, 31, "Expected waiting to continue until the counts were populated")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&polls))
+
+	empty := model.GetReloadJobStatusResponse{TotalServersQueried: 2}
+	assert.True(t, empty.IsComplete(), "Expected a job of a table without segments to complete once servers were queried")
+}
+
+func TestWaitForReloadReturnsOnFailure(t *testing.T) {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/segments/segmentReloadStatus/f9db13c7-3ad6-45a8-a08f-75cd03c42fb5", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"totalSegmentCount": 31, "successCount": 12, "totalServersQueried": 2, "totalServerCallsFailed": 1}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	res, err := client.WaitForReload(ctx, "f9db13c7-3ad6-45a8-a08f-75cd03c42fb5", fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.TotalServerCallsFailed, 1, "Expected the failed status to be returned")
+}
+
+func TestRebalanceTenantAndWait(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.RebalanceTenantAndWait(context.Background(), "DefaultTenant", model.TenantRebalanceConfig{}, fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.TenantRebalanceProgressStats.RemainingTables, 0, "Expected no tables remaining")
+}
+
+func TestScheduleTasksAndWait(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ScheduleTasksAndWait(context.Background(), "MergeRollupTask", "test_OFFLINE", fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res["Task_MergeRollupTask_1712959630094"], model.TaskStateCompleted, "Expected task to be COMPLETED")
+}
+
+func TestChangeTableStateAndWait(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ChangeTableStateAndWait(context.Background(), "test", "OFFLINE", "enable", fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Converged, true, "Expected external view to match ideal state")
+}
+
+func TestWaitForTableConvergence(t *testing.T) {
+
+	var polls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteTablesTestIdealState, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"OFFLINE": {"test_OFFLINE_0": {"Server_1": "ONLINE", "Server_2": "ONLINE"}}}`)
+	})
+	mux.HandleFunc(RouteTablesTestExternalView, func(w http.ResponseWriter, r *http.Request) {
+		// the second replica comes online on the third poll
+		if polls.Add(1) < 3 {
+			fmt.Fprint(w, `{"OFFLINE": {"test_OFFLINE_0": {"Server_1": "ONLINE", "Server_2": "OFFLINE"}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"OFFLINE": {"test_OFFLINE_0": {"Server_1": "ONLINE", "Server_2": "ONLINE"}}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(server.URL))
+
+	var progress []goPinotAPI.WaitProgress
+	opts := *fastWaitOptions
+	opts.OnProgress = func(p goPinotAPI.WaitProgress) {
+		progress = append(progress, p)
+	}
+
+	res, err := client.WaitForTableConvergence(context.Background(), "test", &opts)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.Converged, true, "Expected external view to converge")
+	assert.Equal(t, len(progress), 3, "Expected progress to be reported for every poll")
+	assert.Equal(t, progress[0].Status.(*model.TableConvergence).PendingSegments["test_OFFLINE_0"]["Server_2"], "ONLINE", "Expected Server_2 to be pending")
+}
+
+func TestWaitForTaskTimeout(t *testing.T) {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteTasksTaskState, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `"IN_PROGRESS"`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := goPinotAPI.NewPinotAPIClient(goPinotAPI.ControllerUrl(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	state, err := client.WaitForTask(ctx, "Task_MergeRollupTask_1712959630094", fastWaitOptions)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected waiting to stop at the deadline")
+	assert.Equal(t, state, model.TaskStateInProgress, "Expected last polled state to be returned")
+}
+
+func TestRebalanceTableAndWait(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	_, err := client.UpdateTable("test", []byte(strings.Replace(testTableConfig, `"replication": "1"`, `"replication": "2"`, 1)))
+	assert.NoError(t, err)
+
+	res, err := client.RebalanceTableAndWait(context.Background(), "test", "OFFLINE", model.RebalanceTableOptions{}, fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.TableRebalanceProgressStats.Status, model.RebalanceStatusDone, "Expected rebalance to be DONE")
+}
+
+func TestForceCommitAndWait(t *testing.T) {
+	controller := createMockController(t)
+	client := createPinotClient(controller.URL)
+
+	res, err := client.ForceCommitAndWait(context.Background(), "test", model.ForceCommitOptions{Partitions: []int{0, 1}}, fastWaitOptions)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	assert.Equal(t, res.NumberOfSegmentsYetToBeCommitted, 0, "Expected no segments left to commit")
+}